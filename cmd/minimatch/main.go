@@ -17,9 +17,15 @@ package main
 
 import (
 	"open-match.dev/open-match/internal/app/minimatch"
+	"open-match.dev/open-match/internal/app/rulesmmf"
 	"open-match.dev/open-match/internal/appmain"
+	"open-match.dev/open-match/pkg/matchfunction"
 )
 
 func main() {
+	// The rule based match function is run by the backend for FetchMatches
+	// calls using the IN_PROCESS function type, so minimatch can make matches
+	// on its own.
+	matchfunction.Register("rules", rulesmmf.Function)
 	appmain.RunApplication("minimatch", minimatch.BindService)
 }
@@ -19,6 +19,7 @@
 package mmf
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -103,3 +104,28 @@ func (s *matchFunctionService) Run(req *pb.RunRequest, stream pb.MatchFunction_R
 
 	return nil
 }
+
+// RunInProcess is the same match function, run inside the Open Match Backend
+// instead of as a service.  Register it with
+// matchfunction.Register("soloduel", matchfunction.InProcessFunctionFunc(RunInProcess))
+// in the Backend's binary to use it with the IN_PROCESS FunctionConfig type.
+func RunInProcess(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+	poolTickets, err := matchfunction.QueryPools(ctx, query, profile.GetPools())
+	if err != nil {
+		return err
+	}
+
+	proposals, err := makeMatches(poolTickets)
+	if err != nil {
+		return err
+	}
+
+	for _, proposal := range proposals {
+		select {
+		case out <- proposal:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -49,6 +49,7 @@ require (
 )
 
 require (
+	github.com/tetratelabs/wazero v1.8.2
 	google.golang.org/genproto/googleapis/api v0.0.0-20230815205213-6bfd019c3878
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20230815205213-6bfd019c3878
 )
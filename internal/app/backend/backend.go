@@ -17,7 +17,9 @@ package backend
 import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
 	"google.golang.org/grpc"
+	"open-match.dev/open-match/internal/app/query"
 	"open-match.dev/open-match/internal/appmain"
 	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/statestore"
@@ -29,8 +31,12 @@ var (
 	totalBytesPerMatch      = stats.Int64("open-match.dev/backend/total_bytes_per_match", "Total bytes per match", stats.UnitBytes)
 	ticketsPerMatch         = stats.Int64("open-match.dev/backend/tickets_per_match", "Number of tickets per match", stats.UnitDimensionless)
 	ticketsReleased         = stats.Int64("open-match.dev/backend/tickets_released", "Number of tickets released per request", stats.UnitDimensionless)
+	ticketsRevoked          = stats.Int64("open-match.dev/backend/tickets_revoked", "Number of tickets with revoked assignments per request", stats.UnitDimensionless)
 	ticketsAssigned         = stats.Int64("open-match.dev/backend/tickets_assigned", "Number of tickets assigned per request", stats.UnitDimensionless)
 	ticketsTimeToAssignment = stats.Int64("open-match.dev/backend/ticket_time_to_assignment", "Time to assignment for tickets", stats.UnitMilliseconds)
+	proposalsRejected       = stats.Int64("open-match.dev/backend/proposals_rejected", "Number of match proposals rejected before evaluation", stats.UnitDimensionless)
+
+	keyReason = tag.MustNewKey("reason")
 
 	totalMatchesView = &view.View{
 		Measure:     totalBytesPerMatch,
@@ -62,6 +68,12 @@ var (
 		Description: "Number of tickets released per request",
 		Aggregation: view.Sum(),
 	}
+	ticketsRevokedView = &view.View{
+		Measure:     ticketsRevoked,
+		Name:        "open-match.dev/backend/tickets_revoked",
+		Description: "Number of tickets with revoked assignments per request",
+		Aggregation: view.Sum(),
+	}
 
 	ticketsTimeToAssignmentView = &view.View{
 		Measure:     ticketsTimeToAssignment,
@@ -69,14 +81,51 @@ var (
 		Description: "Time to assignment for tickets",
 		Aggregation: telemetry.DefaultMillisecondsDistribution,
 	}
+	proposalsRejectedView = &view.View{
+		Measure:     proposalsRejected,
+		Name:        "open-match.dev/backend/proposals_rejected",
+		Description: "Number of match proposals rejected before evaluation",
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{keyReason},
+	}
 )
 
 // BindService creates the backend service and binds it to the serving harness.
 func BindService(p *appmain.Params, b *appmain.Bindings) error {
+	wasm, err := newWasmRuntime(p.Config())
+	if err != nil {
+		return err
+	}
+	b.AddCloser(wasm.close)
+
+	store := statestore.New(p.Config())
+	validator, err := newProposalValidator(p.Config(), store)
+	if err != nil {
+		return err
+	}
+	history, err := newMatchHistorySink(p.Config(), store)
+	if err != nil {
+		return err
+	}
+	recorder := newMatchRecorder(history)
+	b.AddCloser(recorder.close)
+
+	// Match functions run in the backend query tickets in process.
+	queryClient, err := query.NewLocalClient(p, b)
+	if err != nil {
+		return err
+	}
+
+	cc := rpc.NewClientCache(p.Config())
 	service := &backendService{
-		synchronizer: newSynchronizerClient(p.Config()),
-		store:        statestore.New(p.Config()),
-		cc:           rpc.NewClientCache(p.Config()),
+		cfg:          p.Config(),
+		synchronizer: newSynchronizerClient(p.Config(), store, cc),
+		store:        store,
+		cc:           cc,
+		query:        queryClient,
+		wasm:         wasm,
+		validator:    validator,
+		recorder:     recorder,
 	}
 
 	b.AddHealthCheckFunc(service.store.HealthCheck)
@@ -89,7 +138,9 @@ func BindService(p *appmain.Params, b *appmain.Bindings) error {
 		ticketsPerMatchView,
 		ticketsAssignedView,
 		ticketsReleasedView,
+		ticketsRevokedView,
 		ticketsTimeToAssignmentView,
+		proposalsRejectedView,
 	)
 	return nil
 }
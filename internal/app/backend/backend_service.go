@@ -37,6 +37,7 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"open-match.dev/open-match/internal/appmain/contextcause"
+	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/ipb"
 	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/statestore"
@@ -46,9 +47,17 @@ import (
 // The service implementing the Backend API that is called to generate matches
 // and make assignments for Tickets.
 type backendService struct {
+	cfg          config.View
 	synchronizer *synchronizerClient
 	store        statestore.Service
 	cc           *rpc.ClientCache
+	query        pb.QueryServiceClient
+	wasm         *wasmRuntime
+	// validator checks the MMF proposals, nil if proposals aren't validated.
+	validator *proposalValidator
+	// recorder adds the returned matches to the history, nil if matches
+	// aren't recorded.
+	recorder *matchRecorder
 }
 
 var (
@@ -71,9 +80,16 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 		return status.Error(codes.InvalidArgument, ".profile is required")
 	}
 
+	// When the synchronizer is sharded, the profile's proposals go to the shard
+	// owning its partition.
+	partition, err := profilePartition(s.cfg, req.GetProfile())
+	if err != nil {
+		return err
+	}
+
 	// Error group for handling the synchronizer calls only.
 	eg, ctx := errgroup.WithContext(stream.Context())
-	syncStream, err := s.synchronizer.synchronize(ctx)
+	syncStream, err := s.synchronizer.synchronize(ctx, partition)
 	if err != nil {
 		return err
 	}
@@ -87,11 +103,20 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	proposals := make(chan *pb.Match)
 	m := &sync.Map{}
 
+	toSync := proposals
+	if key := s.cfg.GetString(synchronizerPartitionKey); key != "" {
+		inPartition := make(chan *pb.Match)
+		eg.Go(func() error {
+			// An error cancels ctx, which stops the mmf.
+			return forwardPartition(ctx, key, partition, proposals, inPartition)
+		})
+		toSync = inPartition
+	}
 	eg.Go(func() error {
-		return synchronizeSend(ctx, syncStream, m, proposals)
+		return synchronizeSend(ctx, syncStream, m, req.GetPriority(), toSync)
 	})
 	eg.Go(func() error {
-		return synchronizeRecv(ctx, syncStream, m, stream, startMmfs, cancelMmfs, s.store)
+		return synchronizeRecv(ctx, syncStream, m, stream, startMmfs, cancelMmfs, s.store, s.recorder)
 	})
 
 	var mmfErr error
@@ -99,7 +124,7 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	case <-mmfCtx.Done():
 		mmfErr = fmt.Errorf("mmf was never started")
 	case <-startMmfs:
-		mmfErr = callMmf(mmfCtx, s.cc, req, proposals)
+		mmfErr = s.runMmf(mmfCtx, req, proposals)
 	}
 
 	syncErr := eg.Wait()
@@ -116,7 +141,7 @@ func (s *backendService) FetchMatches(req *pb.FetchMatchesRequest, stream pb.Bac
 	return nil
 }
 
-func synchronizeSend(ctx context.Context, syncStream synchronizerStream, m *sync.Map, proposals <-chan *pb.Match) error {
+func synchronizeSend(ctx context.Context, syncStream synchronizerStream, m *sync.Map, priority int32, proposals <-chan *pb.Match) error {
 sendProposals:
 	for {
 		select {
@@ -130,7 +155,7 @@ sendProposals:
 			if loaded {
 				return fmt.Errorf("MatchMakingFunction returned same match_id twice: \"%s\"", p.GetMatchId())
 			}
-			err := syncStream.Send(&ipb.SynchronizeRequest{Proposal: p})
+			err := syncStream.Send(&ipb.SynchronizeRequest{Proposal: p, Priority: priority})
 			if err != nil {
 				return fmt.Errorf("error sending proposal to synchronizer: %w", err)
 			}
@@ -144,7 +169,7 @@ sendProposals:
 	return nil
 }
 
-func synchronizeRecv(ctx context.Context, syncStream synchronizerStream, m *sync.Map, stream pb.BackendService_FetchMatchesServer, startMmfs chan<- struct{}, cancelMmfs contextcause.CancelErrFunc, store statestore.Service) error {
+func synchronizeRecv(ctx context.Context, syncStream synchronizerStream, m *sync.Map, stream pb.BackendService_FetchMatchesServer, startMmfs chan<- struct{}, cancelMmfs contextcause.CancelErrFunc, store statestore.Service, recorder *matchRecorder) error {
 	var startMmfsOnce sync.Once
 
 	for {
@@ -180,10 +205,10 @@ func synchronizeRecv(ctx context.Context, syncStream synchronizerStream, m *sync
 					ticketIds = append(ticketIds, t.Id)
 				}
 
-				err = createOrUpdateBackfill(ctx, backfill, ticketIds, store)
+				err = createOrUpdateBackfill(ctx, match, ticketIds, store)
 				if err != nil {
 					e, ok := status.FromError(err)
-					if err == errBackfillGenerationMismatch || (ok && e.Code() == codes.NotFound) {
+					if err == errBackfillGenerationMismatch || err == errBackfillOverfilled || err == errBackfillTeamUnknown || (ok && e.Code() == codes.NotFound) {
 						err = doReleaseTickets(ctx, ticketIds, store)
 						if err != nil {
 							logger.WithError(err).Errorf("failed to remove match tickets from pending release: %v", ticketIds)
@@ -202,20 +227,49 @@ func synchronizeRecv(ctx context.Context, syncStream synchronizerStream, m *sync
 			if err != nil {
 				return fmt.Errorf("error sending match to caller of backend: %w", err)
 			}
+			recorder.record(match)
 		}
 	}
 }
 
+// runMmf calls the MMF, passing its proposals through the validator if one is
+// configured.
+func (s *backendService) runMmf(ctx context.Context, req *pb.FetchMatchesRequest, proposals chan<- *pb.Match) error {
+	if s.validator == nil {
+		return s.callMmf(ctx, req, proposals)
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	unvalidated := make(chan *pb.Match)
+	eg.Go(func() error {
+		return s.callMmf(ctx, req, unvalidated)
+	})
+	eg.Go(func() error {
+		err := s.validator.forward(ctx, unvalidated, proposals)
+		// Unblock the mmf if validation stopped early.
+		for range unvalidated {
+		}
+		return err
+	})
+	return eg.Wait()
+}
+
 // callMmf triggers execution of MMFs to fetch match proposals.
-func callMmf(ctx context.Context, cc *rpc.ClientCache, req *pb.FetchMatchesRequest, proposals chan<- *pb.Match) error {
+func (s *backendService) callMmf(ctx context.Context, req *pb.FetchMatchesRequest, proposals chan<- *pb.Match) error {
 	defer close(proposals)
 	address := fmt.Sprintf("%s:%d", req.GetConfig().GetHost(), req.GetConfig().GetPort())
 
 	switch req.GetConfig().GetType() {
 	case pb.FunctionConfig_GRPC:
-		return callGrpcMmf(ctx, cc, req.GetProfile(), address, proposals)
+		return callGrpcMmf(ctx, s.cc, req.GetProfile(), address, proposals)
 	case pb.FunctionConfig_REST:
-		return callHTTPMmf(ctx, cc, req.GetProfile(), address, proposals)
+		return callHTTPMmf(ctx, s.cc, req.GetProfile(), address, proposals)
+	case pb.FunctionConfig_WEBSOCKET:
+		return callWebSocketMmf(ctx, s.cc, req.GetProfile(), address, proposals)
+	case pb.FunctionConfig_IN_PROCESS:
+		return callInProcessMmf(ctx, s.query, req.GetConfig().GetName(), req.GetProfile(), proposals)
+	case pb.FunctionConfig_WASM:
+		return callWasmMmf(ctx, s.wasm, s.query, s.store, req.GetConfig(), req.GetProfile(), proposals)
 	default:
 		return status.Error(codes.InvalidArgument, "provided match function type is not supported")
 	}
@@ -296,8 +350,8 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 	dec := json.NewDecoder(resp.Body)
 	for {
 		var item struct {
-			Result json.RawMessage        `json:"result"`
-			Error  map[string]interface{} `json:"error"`
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
 		}
 
 		err := dec.Decode(&item)
@@ -308,7 +362,7 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 			return status.Errorf(codes.Unavailable, "failed to read response from HTTP JSON stream: %s", err.Error())
 		}
 		if len(item.Error) != 0 {
-			return status.Errorf(codes.Unavailable, "failed to execute matchfunction.Run: %v", item.Error)
+			return rpc.StatusFromJSON(item.Error)
 		}
 		resp := &pb.RunResponse{}
 		if err := jsonpb.UnmarshalString(string(item.Result), resp); err != nil {
@@ -324,6 +378,51 @@ func callHTTPMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProf
 	return nil
 }
 
+func callWebSocketMmf(ctx context.Context, cc *rpc.ClientCache, profile *pb.MatchProfile, address string, proposals chan<- *pb.Match) error {
+	stream, err := cc.DialWebSocket(ctx, address, "/v1/matchfunction:run")
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = errors.Wrapf(err, "failed to establish websocket connection to match function: %s", address)
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			logger.WithError(err).Debug("failed to close match function websocket")
+		}
+	}()
+
+	if err := stream.Send(&pb.RunRequest{Profile: profile}); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to send run request to mmf for profile %s: %s", profile.GetName(), err.Error())
+	}
+	if err := stream.CloseSend(); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to close the send direction of mmf websocket for profile %s: %s", profile.GetName(), err.Error())
+	}
+
+	for {
+		resp := &pb.RunResponse{}
+		err := stream.Recv(resp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		// Proposals are only read from the websocket as fast as they can be
+		// forwarded, leaving backpressure to the underlying TCP connection.
+		select {
+		case proposals <- resp.GetProposal():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func (s *backendService) ReleaseTickets(ctx context.Context, req *pb.ReleaseTicketsRequest) (*pb.ReleaseTicketsResponse, error) {
 	err := doReleaseTickets(ctx, req.GetTicketIds(), s.store)
 	if err != nil {
@@ -344,6 +443,25 @@ func doReleaseTickets(ctx context.Context, ticketIds []string, store statestore.
 	return nil
 }
 
+// RevokeAssignments removes the Assignment of the input TicketIds, returning
+// them to the active pool with their original create time.
+func (s *backendService) RevokeAssignments(ctx context.Context, req *pb.RevokeAssignmentsRequest) (*pb.RevokeAssignmentsResponse, error) {
+	resp, err := s.store.RevokeAssignments(ctx, req.GetTicketIds())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range resp.GetFailures() {
+		logger.WithFields(logrus.Fields{
+			"ticket_id": f.GetTicketId(),
+			"cause":     f.GetCause().String(),
+		}).Warning("failed to revoke ticket assignment")
+	}
+
+	stats.Record(ctx, ticketsRevoked.M(int64(len(req.GetTicketIds())-len(resp.GetFailures()))))
+	return resp, nil
+}
+
 func (s *backendService) ReleaseAllTickets(ctx context.Context, req *pb.ReleaseAllTicketsRequest) (*pb.ReleaseAllTicketsResponse, error) {
 	err := s.store.ReleaseAllTickets(ctx)
 	if err != nil {
@@ -352,6 +470,50 @@ func (s *backendService) ReleaseAllTickets(ctx context.Context, req *pb.ReleaseA
 	return &pb.ReleaseAllTicketsResponse{}, nil
 }
 
+// RegisterWasmModule stores a WebAssembly match function module under a name,
+// after checking it compiles and exports the functions the backend calls.
+func (s *backendService) RegisterWasmModule(ctx context.Context, req *pb.RegisterWasmModuleRequest) (*pb.RegisterWasmModuleResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".name is required")
+	}
+	if len(req.GetModule()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ".module is required")
+	}
+
+	_, release, err := s.wasm.compile(ctx, req.GetModule())
+	if err != nil {
+		return nil, err
+	}
+	release()
+
+	err = s.store.PutWasmModule(ctx, req.GetName(), req.GetModule())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RegisterWasmModuleResponse{}, nil
+}
+
+// GetMatchHistory returns the recorded matches of a ticket.
+func (s *backendService) GetMatchHistory(ctx context.Context, req *pb.GetMatchHistoryRequest) (*pb.GetMatchHistoryResponse, error) {
+	if req.GetTicketId() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_id is required")
+	}
+
+	mode, err := matchHistoryMode(s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if mode != matchHistoryStatestore {
+		return nil, status.Errorf(codes.FailedPrecondition, "match history is only queryable with the %q sink, not %q", matchHistoryStatestore, mode)
+	}
+
+	records, err := s.store.GetTicketMatchHistory(ctx, req.GetTicketId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMatchHistoryResponse{Records: records}, nil
+}
+
 // AssignTickets overwrites the Assignment field of the input TicketIds.
 func (s *backendService) AssignTickets(ctx context.Context, req *pb.AssignTicketsRequest) (*pb.AssignTicketsResponse, error) {
 	resp, err := doAssignTickets(ctx, req, s.store)
@@ -368,12 +530,23 @@ func (s *backendService) AssignTickets(ctx context.Context, req *pb.AssignTicket
 	return resp, nil
 }
 
-func createOrUpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIds []string, store statestore.Service) error {
+// createOrUpdateBackfill stores the backfill of the match, taking the slots of
+// the match tickets from its capacity.  New backfills are created with the
+// capacity proposed by the match function, while existing backfills keep their
+// stored capacity.
+func createOrUpdateBackfill(ctx context.Context, match *pb.Match, ticketIds []string, store statestore.Service) error {
+	backfill := match.GetBackfill()
 	if backfill.Id == "" {
+		capacity, err := fillCapacity(backfill.Capacity, match)
+		if err != nil {
+			return err
+		}
+
 		backfill.Id = xid.New().String()
 		backfill.CreateTime = timestamppb.Now()
 		backfill.Generation = 1
-		err := store.CreateBackfill(ctx, backfill, ticketIds)
+		backfill.Capacity = capacity
+		err = store.CreateBackfill(ctx, backfill, ticketIds)
 		if err != nil {
 			return err
 		}
@@ -381,36 +554,29 @@ func createOrUpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketId
 		return store.IndexBackfill(ctx, backfill)
 	}
 
-	m := store.NewMutex(backfill.Id)
-	err := m.Lock(ctx)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_, unlockErr := m.Unlock(context.Background())
-		if unlockErr != nil {
-			logger.WithFields(logrus.Fields{"backfill_id": backfill.Id}).WithError(unlockErr).Error("failed to make unlock")
+	b, err := store.ModifyBackfill(ctx, backfill.Id, func(b *pb.Backfill, ids []string) ([]string, error) {
+		if b.Generation != backfill.Generation {
+			logger.WithFields(logrus.Fields{"backfill_id": backfill.Id}).
+				WithError(errBackfillGenerationMismatch).
+				Errorf("failed to update backfill, expecting: %d generation but got: %d", b.Generation, backfill.Generation)
+			return nil, errBackfillGenerationMismatch
 		}
-	}()
 
-	b, ids, err := store.GetBackfill(ctx, backfill.Id)
-	if err != nil {
-		return err
-	}
-
-	if b.Generation != backfill.Generation {
-		logger.WithFields(logrus.Fields{"backfill_id": backfill.Id}).
-			WithError(errBackfillGenerationMismatch).
-			Errorf("failed to update backfill, expecting: %d generation but got: %d", b.Generation, backfill.Generation)
-		return errBackfillGenerationMismatch
-	}
+		capacity, err := fillCapacity(b.Capacity, match)
+		if err != nil {
+			logger.WithFields(logrus.Fields{"backfill_id": backfill.Id}).
+				WithError(err).
+				Errorf("failed to update backfill, match %s doesn't fit its capacity", match.GetMatchId())
+			return nil, err
+		}
 
-	b.SearchFields = backfill.SearchFields
-	b.Extensions = backfill.Extensions
-	b.Generation++
+		b.SearchFields = backfill.SearchFields
+		b.Extensions = backfill.Extensions
+		b.Capacity = capacity
+		b.Generation++
 
-	err = store.UpdateBackfill(ctx, b, append(ids, ticketIds...))
+		return append(ids, ticketIds...), nil
+	})
 	if err != nil {
 		return err
 	}
@@ -431,27 +597,6 @@ func doAssignTickets(ctx context.Context, req *pb.AssignTicketsRequest, store st
 		}
 	}
 
-	ids := []string{}
-
-	for _, ag := range req.Assignments {
-		ids = append(ids, ag.TicketIds...)
-	}
-
-	for _, id := range ids {
-		err = store.DeindexTicket(ctx, id)
-		// Try to deindex all input tickets. Log without returning an error if the deindexing operation failed.
-		// TODO: consider retry the index operation
-		if err != nil {
-			logger.WithError(err).Errorf("failed to deindex ticket %s after updating the assignments", id)
-		}
-	}
-
-	if err = store.DeleteTicketsFromPendingRelease(ctx, ids); err != nil {
-		logger.WithFields(logrus.Fields{
-			"ticket_ids": ids,
-		}).Error(err)
-	}
-
 	return resp, nil
 }
 
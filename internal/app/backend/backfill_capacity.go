@@ -0,0 +1,65 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+var (
+	errBackfillOverfilled  = errors.New("backfill overfilled")
+	errBackfillTeamUnknown = errors.New("backfill team unknown")
+)
+
+// fillCapacity takes the slots of the match tickets from the capacity, and
+// returns the capacity left.  The input capacity is not modified.  Returns an
+// error if the match doesn't fit in the capacity.  A nil capacity is not
+// tracked, and fits any match.
+func fillCapacity(capacity *pb.BackfillCapacity, match *pb.Match) (*pb.BackfillCapacity, error) {
+	if capacity == nil {
+		return nil, nil
+	}
+
+	left, ok := proto.Clone(capacity).(*pb.BackfillCapacity)
+	if !ok {
+		return nil, errors.New("failed to clone backfill capacity proto")
+	}
+
+	left.OpenSlots -= int32(len(match.GetTickets()))
+	if left.OpenSlots < 0 {
+		return nil, errBackfillOverfilled
+	}
+
+	if len(left.TeamOpenSlots) == 0 {
+		return left, nil
+	}
+	for _, t := range match.GetTickets() {
+		team, ok := match.GetTicketTeams()[t.GetId()]
+		if !ok {
+			return nil, errBackfillTeamUnknown
+		}
+		slots, ok := left.TeamOpenSlots[team]
+		if !ok {
+			return nil, errBackfillTeamUnknown
+		}
+		if slots < 1 {
+			return nil, errBackfillOverfilled
+		}
+		left.TeamOpenSlots[team] = slots - 1
+	}
+	return left, nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestFillCapacity(t *testing.T) {
+	tickets := []*pb.Ticket{{Id: "1"}, {Id: "2"}}
+
+	tests := []struct {
+		description string
+		capacity    *pb.BackfillCapacity
+		teams       map[string]string
+		want        *pb.BackfillCapacity
+		wantErr     error
+	}{
+		{
+			description: "untracked capacity",
+		},
+		{
+			description: "open slots",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 3},
+			want:        &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 1},
+		},
+		{
+			description: "exactly full",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 2},
+			want:        &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 0},
+		},
+		{
+			description: "overfilled",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 1},
+			wantErr:     errBackfillOverfilled,
+		},
+		{
+			description: "team open slots",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 4, TeamOpenSlots: map[string]int32{"red": 2, "blue": 2}},
+			teams:       map[string]string{"1": "red", "2": "blue"},
+			want:        &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 2, TeamOpenSlots: map[string]int32{"red": 1, "blue": 1}},
+		},
+		{
+			description: "team overfilled",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 4, TeamOpenSlots: map[string]int32{"red": 1, "blue": 2}},
+			teams:       map[string]string{"1": "red", "2": "red"},
+			wantErr:     errBackfillOverfilled,
+		},
+		{
+			description: "ticket without team",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 4, TeamOpenSlots: map[string]int32{"red": 2, "blue": 2}},
+			teams:       map[string]string{"1": "red"},
+			wantErr:     errBackfillTeamUnknown,
+		},
+		{
+			description: "ticket of unknown team",
+			capacity:    &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 4, TeamOpenSlots: map[string]int32{"red": 2, "blue": 2}},
+			teams:       map[string]string{"1": "red", "2": "green"},
+			wantErr:     errBackfillTeamUnknown,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			before := proto.Clone(test.capacity)
+			got, err := fillCapacity(test.capacity, &pb.Match{Tickets: tickets, TicketTeams: test.teams})
+			require.Equal(t, test.wantErr, err)
+			require.True(t, proto.Equal(test.want, got), "got %v, want %v", got, test.want)
+			require.True(t, proto.Equal(before, test.capacity), "input capacity modified")
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/matchfunction"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// callInProcessMmf runs a match function registered in the backend, passing
+// it the backend's local query client.
+func callInProcessMmf(ctx context.Context, query pb.QueryServiceClient, name string, profile *pb.MatchProfile, proposals chan<- *pb.Match) error {
+	mmf, ok := matchfunction.Lookup(name)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "no match function registered in the backend with name %q", name)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	out := make(chan *pb.Match)
+
+	g.Go(func() (err error) {
+		defer close(out)
+		// A broken match function must not take down the whole backend.
+		defer func() {
+			if r := recover(); r != nil {
+				logger.WithField("stack", string(debug.Stack())).Errorf("match function %q panicked: %v", name, r)
+				err = status.Errorf(codes.Internal, "match function %q panicked: %v", name, r)
+			}
+		}()
+		return mmf.Run(ctx, query, profile, out)
+	})
+	g.Go(func() error {
+		defer func() {
+			for range out {
+			}
+		}()
+
+		for m := range out {
+			select {
+			case proposals <- m:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("failed to run match function %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// Values for the matchHistory config.
+const (
+	matchHistoryNone       = "none"
+	matchHistoryStatestore = "statestore"
+	matchHistoryLog        = "log"
+)
+
+// matchHistorySink records the matches returned by FetchMatches.
+type matchHistorySink interface {
+	record(ctx context.Context, r *pb.MatchRecord) error
+}
+
+// newMatchHistorySink returns the sink configured by matchHistory, or nil if
+// matches aren't recorded.
+func newMatchHistorySink(cfg config.View, store statestore.Service) (matchHistorySink, error) {
+	mode, err := matchHistoryMode(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case matchHistoryStatestore:
+		retention, err := matchHistoryRetention(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return &statestoreHistory{store: store, retention: retention}, nil
+	case matchHistoryLog:
+		return logHistory{}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func matchHistoryMode(cfg config.View) (string, error) {
+	const name = "matchHistory"
+
+	mode := matchHistoryNone
+	if cfg.IsSet(name) {
+		mode = cfg.GetString(name)
+	}
+
+	switch mode {
+	case matchHistoryNone, "":
+		return matchHistoryNone, nil
+	case matchHistoryStatestore, matchHistoryLog:
+		return mode, nil
+	default:
+		return "", status.Errorf(codes.FailedPrecondition, "unknown %s sink %q, must be one of %q, %q or %q", name, mode, matchHistoryNone, matchHistoryStatestore, matchHistoryLog)
+	}
+}
+
+func matchHistoryRetention(cfg config.View) (time.Duration, error) {
+	const (
+		name             = "matchHistoryRetention"
+		defaultRetention = 24 * time.Hour
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultRetention, nil
+	}
+
+	// Records are stored with an expiry, which redis requires to be positive.
+	retention := cfg.GetDuration(name)
+	if retention < time.Millisecond {
+		return 0, status.Errorf(codes.FailedPrecondition, "%s must be at least 1ms, got %v", name, retention)
+	}
+	return retention, nil
+}
+
+// statestoreHistory keeps match records in the state storage, where they can be
+// queried by ticket id with GetMatchHistory.
+type statestoreHistory struct {
+	store     statestore.Service
+	retention time.Duration
+}
+
+func (h *statestoreHistory) record(ctx context.Context, r *pb.MatchRecord) error {
+	return h.store.RecordMatch(ctx, r, h.retention)
+}
+
+// logHistory writes match records to the log, for log based pipelines to
+// export.
+type logHistory struct{}
+
+func (logHistory) record(ctx context.Context, r *pb.MatchRecord) error {
+	logger.WithFields(logrus.Fields{
+		"match_id":       r.GetMatchId(),
+		"match_profile":  r.GetMatchProfile(),
+		"match_function": r.GetMatchFunction(),
+		"ticket_ids":     r.GetTicketIds(),
+		"backfill_id":    r.GetBackfillId(),
+		"create_time":    r.GetCreateTime().AsTime(),
+		"score":          r.GetScore(),
+	}).Info("match history record")
+	return nil
+}
+
+// newMatchRecord returns the record of a match returned at now.
+func newMatchRecord(m *pb.Match, now time.Time) *pb.MatchRecord {
+	r := &pb.MatchRecord{
+		MatchId:       m.GetMatchId(),
+		MatchProfile:  m.GetMatchProfile(),
+		MatchFunction: m.GetMatchFunction(),
+		TicketIds:     make([]string, 0, len(m.GetTickets())),
+		BackfillId:    m.GetBackfill().GetId(),
+		CreateTime:    timestamppb.New(now),
+	}
+	for _, t := range m.GetTickets() {
+		r.TicketIds = append(r.TicketIds, t.GetId())
+	}
+
+	if a, ok := m.GetExtensions()["evaluation_input"]; ok {
+		inp := &pb.DefaultEvaluationCriteria{}
+		if err := a.UnmarshalTo(inp); err == nil {
+			r.Score = inp.GetScore()
+		}
+	}
+	return r
+}
+
+const (
+	// matchRecorderQueue is how many matches can wait to be recorded before
+	// further ones are dropped.
+	matchRecorderQueue = 10000
+	// matchRecordTimeout bounds recording a match, which outlives the call
+	// returning it.  It also bounds recording the queued matches when the
+	// backend stops.
+	matchRecordTimeout = 10 * time.Second
+)
+
+// matchRecorder adds the matches returned by FetchMatches calls to the history
+// in the background, in the order they were returned, so that a slow sink
+// doesn't hold up the matches returned after them.  The backend has a single
+// recorder, started with the service.
+type matchRecorder struct {
+	history matchHistorySink
+	records chan *pb.MatchRecord
+	// stop is closed when the backend stops, and done once the matches queued
+	// by then were recorded.
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newMatchRecorder starts recording to the history, or returns nil if matches
+// aren't recorded.
+func newMatchRecorder(history matchHistorySink) *matchRecorder {
+	if history == nil {
+		return nil
+	}
+
+	r := &matchRecorder{
+		history: history,
+		records: make(chan *pb.MatchRecord, matchRecorderQueue),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *matchRecorder) run() {
+	defer close(r.done)
+	for {
+		select {
+		case record := <-r.records:
+			r.write(record)
+		case <-r.stop:
+			for {
+				select {
+				case record := <-r.records:
+					r.write(record)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *matchRecorder) write(record *pb.MatchRecord) {
+	ctx, cancel := context.WithTimeout(context.Background(), matchRecordTimeout)
+	defer cancel()
+	if err := r.history.record(ctx, record); err != nil {
+		logger.WithError(err).WithField("match_id", record.GetMatchId()).Warning("failed to record match history")
+	}
+}
+
+// record queues the match to be added to the history.  Failures are logged
+// rather than failing the call, as the match was already returned.
+func (r *matchRecorder) record(m *pb.Match) {
+	if r == nil {
+		return
+	}
+
+	select {
+	case r.records <- newMatchRecord(m, time.Now()):
+	default:
+		logger.WithField("match_id", m.GetMatchId()).Warning("match history is falling behind, dropping the match record")
+	}
+}
+
+// close records the queued matches, waiting at most matchRecordTimeout.
+// Matches queued after close are dropped with the process.
+func (r *matchRecorder) close() {
+	if r == nil {
+		return
+	}
+
+	close(r.stop)
+	select {
+	case <-r.done:
+	case <-time.After(matchRecordTimeout):
+		logger.Warning("timed out recording the queued match history, dropping the rest")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestNewMatchRecord(t *testing.T) {
+	inp, err := anypb.New(&pb.DefaultEvaluationCriteria{Score: 7.5})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+
+	r := newMatchRecord(&pb.Match{
+		MatchId:       "m",
+		MatchProfile:  "1v1",
+		MatchFunction: "rules",
+		Tickets:       []*pb.Ticket{{Id: "1"}, {Id: "2"}},
+		Backfill:      &pb.Backfill{Id: "bf"},
+		Extensions:    map[string]*anypb.Any{"evaluation_input": inp},
+	}, now)
+
+	require.Equal(t, "m", r.GetMatchId())
+	require.Equal(t, "1v1", r.GetMatchProfile())
+	require.Equal(t, "rules", r.GetMatchFunction())
+	require.Equal(t, []string{"1", "2"}, r.GetTicketIds())
+	require.Equal(t, "bf", r.GetBackfillId())
+	require.Equal(t, now, r.GetCreateTime().AsTime().Local())
+	require.Equal(t, 7.5, r.GetScore())
+
+	require.Equal(t, 0.0, newMatchRecord(&pb.Match{MatchId: "no-input"}, now).GetScore())
+}
+
+func TestGetMatchHistory(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := context.Background()
+	s := &backendService{cfg: cfg, store: store}
+
+	_, err := s.GetMatchHistory(ctx, &pb.GetMatchHistoryRequest{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Matches aren't recorded by default.
+	history, err := newMatchHistorySink(cfg, store)
+	require.NoError(t, err)
+	require.Nil(t, history)
+	_, err = s.GetMatchHistory(ctx, &pb.GetMatchHistoryRequest{TicketId: "1"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	cfg.Set("matchHistory", "unknown")
+	_, err = newMatchHistorySink(cfg, store)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	cfg.Set("matchHistory", matchHistoryLog)
+	history, err = newMatchHistorySink(cfg, store)
+	require.NoError(t, err)
+	recorder := newMatchRecorder(history)
+	recorder.record(&pb.Match{MatchId: "logged", Tickets: []*pb.Ticket{{Id: "1"}}})
+	recorder.close()
+	_, err = s.GetMatchHistory(ctx, &pb.GetMatchHistoryRequest{TicketId: "1"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Redis rejects expiring records straight away.
+	cfg.Set("matchHistory", matchHistoryStatestore)
+	cfg.Set("matchHistoryRetention", "0s")
+	_, err = newMatchHistorySink(cfg, store)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	cfg.Set("matchHistoryRetention", "1h")
+	history, err = newMatchHistorySink(cfg, store)
+	require.NoError(t, err)
+	recorder = newMatchRecorder(history)
+	recorder.record(&pb.Match{MatchId: "a", MatchProfile: "1v1", Tickets: []*pb.Ticket{{Id: "1"}, {Id: "2"}}})
+	recorder.record(&pb.Match{MatchId: "b", MatchProfile: "1v1", Tickets: []*pb.Ticket{{Id: "1"}}})
+	recorder.close()
+
+	resp, err := s.GetMatchHistory(ctx, &pb.GetMatchHistoryRequest{TicketId: "1"})
+	require.NoError(t, err)
+	require.Len(t, resp.GetRecords(), 2)
+	require.Equal(t, "b", resp.GetRecords()[0].GetMatchId())
+	require.Equal(t, "a", resp.GetRecords()[1].GetMatchId())
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// synchronizerPartitionKey names the string_arg which partitions tickets
+	// between synchronizer shards.  Unset disables sharding.
+	synchronizerPartitionKey = "synchronizerPartitionKey"
+	// synchronizerShardsPrefix is followed by a partition to configure the
+	// address of the synchronizer shard owning it.
+	synchronizerShardsPrefix = "api.synchronizerShards"
+)
+
+// profilePartition returns the partition of the tickets the profile can match,
+// which is the value every pool of the profile requires of the partition key.
+// When sharding is enabled, profiles whose pools don't all require the same
+// value are rejected: the default synchronizer would match the tickets owned
+// by the shards, with no evaluator in common to prevent collisions.
+func profilePartition(cfg config.View, profile *pb.MatchProfile) (string, error) {
+	key := cfg.GetString(synchronizerPartitionKey)
+	if key == "" {
+		return "", nil
+	}
+
+	partition := ""
+	for i, pool := range profile.GetPools() {
+		value, ok := poolPartition(pool, key)
+		if !ok {
+			return "", status.Errorf(codes.InvalidArgument, "pool %q of profile %q must filter on the partition key %q when the synchronizer is sharded", pool.GetName(), profile.GetName(), key)
+		}
+		if i > 0 && value != partition {
+			return "", status.Errorf(codes.InvalidArgument, "pools of profile %q must require the same value of the partition key %q when the synchronizer is sharded, got %q and %q", profile.GetName(), key, partition, value)
+		}
+		partition = value
+	}
+	return partition, nil
+}
+
+func poolPartition(pool *pb.Pool, key string) (string, bool) {
+	for _, f := range pool.GetStringEqualsFilters() {
+		if f.GetStringArg() == key {
+			return f.GetValue(), true
+		}
+	}
+	return "", false
+}
+
+// forwardPartition sends the proposals from in on out, failing on the first
+// proposal with a ticket outside the partition, as the synchronizer shard
+// owning the partition can't prevent collisions with other shards' matches.
+// out is closed once in is closed or an error is returned.
+func forwardPartition(ctx context.Context, key, partition string, in <-chan *pb.Match, out chan<- *pb.Match) error {
+	defer close(out)
+
+	for {
+		var p *pb.Match
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case m, ok := <-in:
+			if !ok {
+				return nil
+			}
+			p = m
+		}
+
+		for _, t := range p.GetTickets() {
+			if v := t.GetSearchFields().GetStringArgs()[key]; v != partition {
+				return status.Errorf(codes.FailedPrecondition, "match function returned proposal %q with ticket %q of partition %q, expected %q", p.GetMatchId(), t.GetId(), v, partition)
+			}
+		}
+
+		select {
+		case out <- p:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func regionPool(name, region string) *pb.Pool {
+	return &pb.Pool{
+		Name: name,
+		StringEqualsFilters: []*pb.StringEqualsFilter{
+			{StringArg: "mode", Value: "ranked"},
+			{StringArg: "region", Value: region},
+		},
+	}
+}
+
+func TestProfilePartition(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set(synchronizerPartitionKey, "region")
+
+	for name, test := range map[string]struct {
+		pools   []*pb.Pool
+		want    string
+		wantErr string
+	}{
+		"no pools":         {},
+		"one region":       {pools: []*pb.Pool{regionPool("a", "eu"), regionPool("b", "eu")}, want: "eu"},
+		"two regions":      {pools: []*pb.Pool{regionPool("a", "eu"), regionPool("b", "us")}, wantErr: `got "eu" and "us"`},
+		"unfiltered pool":  {pools: []*pb.Pool{regionPool("a", "eu"), {Name: "b"}}, wantErr: `pool "b"`},
+		"other filter key": {pools: []*pb.Pool{{Name: "a", StringEqualsFilters: []*pb.StringEqualsFilter{{StringArg: "mode", Value: "eu"}}}}, wantErr: `pool "a"`},
+	} {
+		got, err := profilePartition(cfg, &pb.MatchProfile{Pools: test.pools})
+		if test.wantErr != "" {
+			require.Equal(t, codes.InvalidArgument, status.Code(err), name)
+			require.Contains(t, err.Error(), test.wantErr, name)
+			continue
+		}
+		require.NoError(t, err, name)
+		require.Equal(t, test.want, got, name)
+	}
+
+	// Sharding is disabled without a partition key, so any profile is accepted.
+	got, err := profilePartition(viper.New(), &pb.MatchProfile{Pools: []*pb.Pool{regionPool("a", "eu"), {Name: "b"}}})
+	require.NoError(t, err)
+	require.Equal(t, "", got)
+}
+
+func TestForwardPartition(t *testing.T) {
+	ticket := func(id, region string) *pb.Ticket {
+		return &pb.Ticket{Id: id, SearchFields: &pb.SearchFields{StringArgs: map[string]string{"region": region}}}
+	}
+
+	in := make(chan *pb.Match, 2)
+	out := make(chan *pb.Match, 2)
+	in <- &pb.Match{MatchId: "eu", Tickets: []*pb.Ticket{ticket("1", "eu")}}
+	in <- &pb.Match{MatchId: "mixed", Tickets: []*pb.Ticket{ticket("2", "eu"), ticket("3", "us")}}
+	close(in)
+
+	err := forwardPartition(context.Background(), "region", "eu", in, out)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Contains(t, err.Error(), `ticket "3" of partition "us"`)
+
+	var got []string
+	for m := range out {
+		got = append(got, m.GetMatchId())
+	}
+	require.Equal(t, []string{"eu"}, got)
+}
+
+func TestSynchronizerShard(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("api.synchronizer.hostname", "om-synchronizer")
+	cfg.Set("api.synchronizer.grpcport", 50506)
+	cfg.Set(synchronizerShardsPrefix+".eu.hostname", "om-synchronizer-eu")
+	cfg.Set(synchronizerShardsPrefix+".eu.grpcport", 50506)
+
+	sc := newSynchronizerClient(cfg, nil, nil)
+	require.Same(t, sc.cacher, sc.shard(""))
+	require.Same(t, sc.cacher, sc.shard("us"))
+
+	eu := sc.shard("eu")
+	require.NotSame(t, sc.cacher, eu)
+	require.Same(t, eu, sc.shard("eu"))
+}
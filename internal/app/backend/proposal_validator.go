@@ -0,0 +1,180 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// Reasons a proposal can be rejected by the proposal validator.  Used as the
+// value of the reason tag on the proposals_rejected metric.
+const (
+	reasonDuplicateTicket            = "duplicate_ticket"
+	reasonTicketNotFound             = "ticket_not_found"
+	reasonTicketAssigned             = "ticket_assigned"
+	reasonTicketNotActive            = "ticket_not_active"
+	reasonBackfillNotFound           = "backfill_not_found"
+	reasonBackfillGenerationMismatch = "backfill_generation_mismatch"
+	reasonBackfillOverfilled         = "backfill_overfilled"
+	reasonBackfillTeamUnknown        = "backfill_team_unknown"
+)
+
+// Values for the proposalValidation config.
+const (
+	proposalValidationNone   = "none"
+	proposalValidationDrop   = "drop"
+	proposalValidationStrict = "strict"
+)
+
+// proposalValidator checks match proposals against the state storage before
+// they are sent to the synchronizer, so that invalid proposals never compete
+// with valid ones during evaluation.
+type proposalValidator struct {
+	store statestore.Service
+	// strict fails the MMF run on the first invalid proposal instead of
+	// dropping it.
+	strict bool
+}
+
+// newProposalValidator returns the validator configured by proposalValidation,
+// or nil if proposals should be forwarded as-is.
+func newProposalValidator(cfg config.View, store statestore.Service) (*proposalValidator, error) {
+	const name = "proposalValidation"
+
+	mode := proposalValidationNone
+	if cfg.IsSet(name) {
+		mode = cfg.GetString(name)
+	}
+
+	switch mode {
+	case proposalValidationNone, "":
+		return nil, nil
+	case proposalValidationDrop:
+		return &proposalValidator{store: store}, nil
+	case proposalValidationStrict:
+		return &proposalValidator{store: store, strict: true}, nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "unknown %s mode %q, must be one of %q, %q or %q", name, mode, proposalValidationNone, proposalValidationDrop, proposalValidationStrict)
+	}
+}
+
+// forward reads proposals from in, and sends the valid ones on out.  out is
+// closed once in is closed or an error is returned.
+func (v *proposalValidator) forward(ctx context.Context, in <-chan *pb.Match, out chan<- *pb.Match) error {
+	defer close(out)
+
+	for p := range in {
+		reason, err := v.validate(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		if reason != "" {
+			logger.WithFields(logrus.Fields{
+				"match_id":       p.GetMatchId(),
+				"match_profile":  p.GetMatchProfile(),
+				"match_function": p.GetMatchFunction(),
+				"reason":         reason,
+			}).Warning("Invalid match proposal, dropping it before evaluation.")
+
+			err = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(keyReason, reason)}, proposalsRejected.M(1))
+			if err != nil {
+				logger.WithError(err).Info("failed to record rejected proposal")
+			}
+
+			if v.strict {
+				return status.Errorf(codes.FailedPrecondition, "match function returned invalid proposal %q: %s", p.GetMatchId(), reason)
+			}
+			continue
+		}
+
+		select {
+		case out <- p:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// validate returns the reason a proposal is invalid, or an empty string if the
+// proposal is valid.
+func (v *proposalValidator) validate(ctx context.Context, p *pb.Match) (string, error) {
+	ids := make([]string, 0, len(p.GetTickets()))
+	seen := make(map[string]struct{}, len(p.GetTickets()))
+	for _, t := range p.GetTickets() {
+		if _, ok := seen[t.GetId()]; ok {
+			return reasonDuplicateTicket, nil
+		}
+		seen[t.GetId()] = struct{}{}
+		ids = append(ids, t.GetId())
+	}
+
+	// Look up the tickets and the backfill in a single round trip, as every
+	// proposal is validated.
+	state, err := v.store.GetProposalState(ctx, ids, p.GetBackfill().GetId())
+	if err != nil {
+		return "", fmt.Errorf("failed to get the state of proposal %q: %w", p.GetMatchId(), err)
+	}
+
+	if len(state.Tickets) != len(ids) {
+		return reasonTicketNotFound, nil
+	}
+	for _, t := range state.Tickets {
+		if t.GetAssignment() != nil {
+			return reasonTicketAssigned, nil
+		}
+	}
+	if len(state.ActiveTicketIDs) != len(ids) {
+		return reasonTicketNotActive, nil
+	}
+
+	if b := p.GetBackfill(); b != nil {
+		capacity := b.GetCapacity()
+		if b.GetId() != "" {
+			if state.Backfill == nil {
+				return reasonBackfillNotFound, nil
+			}
+			if state.Backfill.GetGeneration() != b.GetGeneration() {
+				return reasonBackfillGenerationMismatch, nil
+			}
+			capacity = state.Backfill.GetCapacity()
+		}
+
+		_, err = fillCapacity(capacity, p)
+		switch err {
+		case nil:
+		case errBackfillOverfilled:
+			return reasonBackfillOverfilled, nil
+		case errBackfillTeamUnknown:
+			return reasonBackfillTeamUnknown, nil
+		default:
+			return "", err
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestNewProposalValidator(t *testing.T) {
+	cfg := viper.New()
+
+	v, err := newProposalValidator(cfg, nil)
+	require.NoError(t, err)
+	require.Nil(t, v)
+
+	cfg.Set("proposalValidation", "drop")
+	v, err = newProposalValidator(cfg, nil)
+	require.NoError(t, err)
+	require.False(t, v.strict)
+
+	cfg.Set("proposalValidation", "strict")
+	v, err = newProposalValidator(cfg, nil)
+	require.NoError(t, err)
+	require.True(t, v.strict)
+
+	cfg.Set("proposalValidation", "sometimes")
+	_, err = newProposalValidator(cfg, nil)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestProposalValidatorValidate(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	newTicket := func(id string) *pb.Ticket {
+		ticket := &pb.Ticket{Id: id}
+		require.NoError(t, store.CreateTicket(ctx, ticket))
+		require.NoError(t, store.IndexTicket(ctx, ticket))
+		return ticket
+	}
+
+	active1 := newTicket("active1")
+	active2 := newTicket("active2")
+	pending := newTicket("pending")
+	require.NoError(t, store.AddTicketsToPendingRelease(ctx, []string{pending.Id}))
+	assigned := newTicket("assigned")
+	_, _, err := store.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{assigned.Id}, Assignment: &pb.Assignment{Connection: "a"}}},
+	})
+	require.NoError(t, err)
+
+	backfill := &pb.Backfill{Id: "backfill", Generation: 2}
+	require.NoError(t, store.CreateBackfill(ctx, backfill, nil))
+	full := &pb.Backfill{Id: "full", Generation: 1, Capacity: &pb.BackfillCapacity{MaxSlots: 4}}
+	require.NoError(t, store.CreateBackfill(ctx, full, nil))
+
+	tests := []struct {
+		description string
+		match       *pb.Match
+		reason      string
+	}{
+		{
+			description: "valid tickets",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, active2}},
+		},
+		{
+			description: "no tickets",
+			match:       &pb.Match{},
+		},
+		{
+			description: "duplicate ticket",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, active2, active1}},
+			reason:      reasonDuplicateTicket,
+		},
+		{
+			description: "missing ticket",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, {Id: "missing"}}},
+			reason:      reasonTicketNotFound,
+		},
+		{
+			description: "assigned ticket",
+			match:       &pb.Match{Tickets: []*pb.Ticket{assigned}},
+			reason:      reasonTicketAssigned,
+		},
+		{
+			description: "pending ticket",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, pending}},
+			reason:      reasonTicketNotActive,
+		},
+		{
+			description: "new backfill",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1}, Backfill: &pb.Backfill{}},
+		},
+		{
+			description: "current backfill",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1}, Backfill: &pb.Backfill{Id: "backfill", Generation: 2}},
+		},
+		{
+			description: "stale backfill",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1}, Backfill: &pb.Backfill{Id: "backfill", Generation: 1}},
+			reason:      reasonBackfillGenerationMismatch,
+		},
+		{
+			description: "missing backfill",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1}, Backfill: &pb.Backfill{Id: "missing"}},
+			reason:      reasonBackfillNotFound,
+		},
+		{
+			description: "full backfill",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1}, Backfill: &pb.Backfill{Id: "full", Generation: 1, Capacity: &pb.BackfillCapacity{OpenSlots: 4}}},
+			reason:      reasonBackfillOverfilled,
+		},
+		{
+			description: "new backfill with capacity",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, active2}, Backfill: &pb.Backfill{Capacity: &pb.BackfillCapacity{MaxSlots: 2, OpenSlots: 2}}},
+		},
+		{
+			description: "new backfill overfilled",
+			match:       &pb.Match{Tickets: []*pb.Ticket{active1, active2}, Backfill: &pb.Backfill{Capacity: &pb.BackfillCapacity{MaxSlots: 2, OpenSlots: 1}}},
+			reason:      reasonBackfillOverfilled,
+		},
+		{
+			description: "new backfill with unknown team",
+			match: &pb.Match{
+				Tickets:     []*pb.Ticket{active1},
+				Backfill:    &pb.Backfill{Capacity: &pb.BackfillCapacity{MaxSlots: 2, OpenSlots: 2, TeamOpenSlots: map[string]int32{"red": 1, "blue": 1}}},
+				TicketTeams: map[string]string{active1.Id: "green"},
+			},
+			reason: reasonBackfillTeamUnknown,
+		},
+	}
+
+	v := &proposalValidator{store: store}
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			reason, err := v.validate(ctx, test.match)
+			require.NoError(t, err)
+			require.Equal(t, test.reason, reason)
+		})
+	}
+}
+
+func TestProposalValidatorForward(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	ticket := &pb.Ticket{Id: "ticket"}
+	require.NoError(t, store.CreateTicket(ctx, ticket))
+	require.NoError(t, store.IndexTicket(ctx, ticket))
+
+	valid := &pb.Match{MatchId: "valid", Tickets: []*pb.Ticket{ticket}}
+	invalid := &pb.Match{MatchId: "invalid", Tickets: []*pb.Ticket{{Id: "missing"}}}
+
+	run := func(v *proposalValidator) ([]string, error) {
+		in := make(chan *pb.Match, 2)
+		in <- invalid
+		in <- valid
+		close(in)
+
+		out := make(chan *pb.Match, 2)
+		err := v.forward(ctx, in, out)
+
+		ids := []string{}
+		for m := range out {
+			ids = append(ids, m.MatchId)
+		}
+		return ids, err
+	}
+
+	ids, err := run(&proposalValidator{store: store})
+	require.NoError(t, err)
+	require.Equal(t, []string{"valid"}, ids)
+
+	ids, err = run(&proposalValidator{store: store, strict: true})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Contains(t, err.Error(), reasonTicketNotFound)
+	require.Empty(t, ids)
+}
@@ -2,19 +2,47 @@ package backend
 
 import (
 	"context"
+	"sync"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/ipb"
 	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/statestore"
 )
 
+// synchronizerLeaseDuration enables synchronizer leader election, with calls
+// sent to the replica holding the synchronizer's lease.
+const synchronizerLeaseDuration = "synchronizerLeaseDuration"
+
+// synchronizerClient calls the synchronizer, or the synchronizer shard owning
+// a ticket partition when the synchronizer is sharded.
 type synchronizerClient struct {
+	cfg    config.View
 	cacher *config.Cacher
+	// store and cc find and call the leading replica with leader election.
+	store statestore.Service
+	cc    *rpc.ClientCache
+
+	m      sync.Mutex
+	shards map[string]*config.Cacher
+}
+
+func newSynchronizerClient(cfg config.View, store statestore.Service, cc *rpc.ClientCache) *synchronizerClient {
+	return &synchronizerClient{
+		cfg:    cfg,
+		cacher: newSynchronizerCacher(cfg, "api.synchronizer"),
+		store:  store,
+		cc:     cc,
+		shards: map[string]*config.Cacher{},
+	}
 }
 
-func newSynchronizerClient(cfg config.View) *synchronizerClient {
+func newSynchronizerCacher(cfg config.View, prefix string) *config.Cacher {
 	newInstance := func(cfg config.View) (interface{}, func(), error) {
-		conn, err := rpc.GRPCClientFromConfig(cfg, "api.synchronizer")
+		conn, err := rpc.GRPCClientFromConfig(cfg, prefix)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -29,9 +57,36 @@ func newSynchronizerClient(cfg config.View) *synchronizerClient {
 		return ipb.NewSynchronizerClient(conn), close, nil
 	}
 
-	return &synchronizerClient{
-		cacher: config.NewCacher(cfg, newInstance),
+	return config.NewCacher(cfg, newInstance)
+}
+
+// shard returns the cacher of the synchronizer owning the partition.  The
+// default synchronizer owns the partitions no shard is configured for, and all
+// tickets when sharding is disabled.
+func (sc *synchronizerClient) shard(partition string) *config.Cacher {
+	partition = sc.shardPartition(partition)
+	if partition == "" {
+		return sc.cacher
+	}
+	prefix := synchronizerShardsPrefix + "." + partition
+
+	sc.m.Lock()
+	defer sc.m.Unlock()
+	c, ok := sc.shards[partition]
+	if !ok {
+		c = newSynchronizerCacher(sc.cfg, prefix)
+		sc.shards[partition] = c
+	}
+	return c
+}
+
+// shardPartition returns the partition the synchronizer owning the partition
+// was deployed for, which is "" for the default synchronizer.
+func (sc *synchronizerClient) shardPartition(partition string) string {
+	if partition == "" || !sc.cfg.IsSet(synchronizerShardsPrefix+"."+partition+".hostname") {
+		return ""
 	}
+	return partition
 }
 
 type synchronizerStream interface {
@@ -40,10 +95,52 @@ type synchronizerStream interface {
 	CloseSend() error
 }
 
-func (sc *synchronizerClient) synchronize(ctx context.Context) (synchronizerStream, error) {
-	client, err := sc.cacher.Get()
+func (sc *synchronizerClient) synchronize(ctx context.Context, partition string) (synchronizerStream, error) {
+	if ttl := sc.cfg.GetDuration(synchronizerLeaseDuration); ttl > 0 {
+		return sc.synchronizeLeader(ctx, partition, ttl)
+	}
+
+	client, err := sc.shard(partition).Get()
 	if err != nil {
 		return nil, err
 	}
 	return client.(ipb.SynchronizerClient).Synchronize(ctx)
 }
+
+// synchronizeLeader calls the replica holding the lease of the synchronizer
+// owning the partition.  While there is no leader, or the leader can't be
+// reached, it retries for up to two leases, giving a standby time to take over.
+func (sc *synchronizerClient) synchronizeLeader(ctx context.Context, partition string, ttl time.Duration) (synchronizerStream, error) {
+	lease := statestore.SynchronizerLease(sc.shardPartition(partition))
+	deadline := time.Now().Add(2 * ttl)
+
+	for {
+		stream, err := sc.callLeader(ctx, lease)
+		if err == nil || status.Code(err) != codes.Unavailable || time.Now().After(deadline) {
+			return stream, err
+		}
+
+		logger.WithError(err).Debug("synchronizer leader unavailable, retrying")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(ttl / 3):
+		}
+	}
+}
+
+func (sc *synchronizerClient) callLeader(ctx context.Context, lease string) (synchronizerStream, error) {
+	leader, err := sc.store.GetLeaseHolder(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+	if leader == "" {
+		return nil, status.Errorf(codes.Unavailable, "no synchronizer leader holds lease %q", lease)
+	}
+
+	conn, err := sc.cc.GetGRPC(leader)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to connect to synchronizer leader %s: %v", leader, err)
+	}
+	return ipb.NewSynchronizerClient(conn).Synchronize(ctx)
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/ipb"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/internal/statestore"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+)
+
+type leaderSynchronizer struct {
+	ipb.UnimplementedSynchronizerServer
+}
+
+func (leaderSynchronizer) Synchronize(ipb.Synchronizer_SynchronizeServer) error {
+	return status.Error(codes.Aborted, "reached the leader")
+}
+
+func TestSynchronizerLeaderFailover(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	const ttl = 150 * time.Millisecond
+	cfg.Set(synchronizerLeaseDuration, ttl)
+	cfg.Set(synchronizerShardsPrefix+".eu.hostname", "om-synchronizer-eu")
+
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	ipb.RegisterSynchronizerServer(s, leaderSynchronizer{})
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	defer s.Stop()
+
+	sc := newSynchronizerClient(cfg, store, rpc.NewClientCache(cfg))
+	ctx := context.Background()
+	lease := statestore.SynchronizerLease("eu")
+
+	// Without a leader, the call fails once a standby had time to take over.
+	start := time.Now()
+	_, err = sc.synchronize(ctx, "eu")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.GreaterOrEqual(t, int64(time.Since(start)), int64(2*ttl))
+
+	// A leader elected while the call waits is used.
+	elected := make(chan error, 1)
+	go func() {
+		time.Sleep(ttl / 2)
+		_, err := store.AcquireLease(ctx, lease, lis.Addr().String(), time.Minute)
+		elected <- err
+	}()
+	stream, err := sc.synchronize(ctx, "eu")
+	require.NoError(t, err)
+	require.NoError(t, <-elected)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Aborted, status.Code(err))
+
+	// Partitions without a shard are synchronized by the default synchronizer,
+	// so its leader is called.
+	_, err = store.AcquireLease(ctx, statestore.SynchronizerLease(""), lis.Addr().String(), time.Minute)
+	require.NoError(t, err)
+	stream, err = sc.synchronize(ctx, "us")
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.Aborted, status.Code(err))
+}
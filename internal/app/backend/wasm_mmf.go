@@ -0,0 +1,473 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+	"open-match.dev/open-match/pkg/matchfunction"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// wasmHostModule is the module name host functions are imported from.
+	wasmHostModule = "openmatch"
+
+	// Compiled modules kept around, so that FetchMatches calls don't have to
+	// compile the module every time.
+	wasmCompiledCacheSize = 16
+
+	defaultWasmMaxMemoryMiB = 64
+	defaultWasmMaxModuleMiB = 16
+	wasmPageSize            = 64 * 1024
+)
+
+// wasmRuntime runs WebAssembly match functions.  Every call gets a fresh
+// instance of the module with its own memory, and modules can only reach the
+// backend through the host functions of wasmHostModule.  WASI is provided
+// without any file system, so modules compiled for it still work.
+type wasmRuntime struct {
+	runtime wazero.Runtime
+	// moduleDir is the directory wasm_path modules are loaded from, or empty
+	// if loading modules from the file system is disabled.
+	moduleDir      string
+	maxModuleBytes int
+
+	mu       sync.Mutex
+	compiled map[[sha256.Size]byte]*wasmCompiled
+	// Hashes of compiled in the order they were added, oldest first.
+	order [][sha256.Size]byte
+}
+
+// wasmCompiled is a compiled module, which is closed once it is evicted from
+// the cache and no longer in use.
+type wasmCompiled struct {
+	module  wazero.CompiledModule
+	refs    int
+	evicted bool
+}
+
+func newWasmRuntime(cfg config.View) (*wasmRuntime, error) {
+	maxMemoryMiB, err := getWasmMiB(cfg, "wasmMaxMemoryMiB", defaultWasmMaxMemoryMiB, 4096)
+	if err != nil {
+		return nil, err
+	}
+	maxModuleMiB, err := getWasmMiB(cfg, "wasmMaxModuleMiB", defaultWasmMaxModuleMiB, 1024)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleDir := cfg.GetString("wasmModuleDir")
+	if moduleDir != "" {
+		// Symlinks are resolved, so that modules are checked against the
+		// directory they really are in.
+		moduleDir, err = filepath.EvalSymlinks(moduleDir)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "invalid wasmModuleDir: %s", err.Error())
+		}
+		moduleDir, err = filepath.Abs(moduleDir)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "invalid wasmModuleDir: %s", err.Error())
+		}
+	}
+
+	ctx := context.Background()
+	r := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(uint32(maxMemoryMiB*1024*1024/wasmPageSize)))
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+
+	_, err = r.NewHostModuleBuilder(wasmHostModule).
+		NewFunctionBuilder().WithFunc(wasmQueryPool).Export("query_pool").
+		NewFunctionBuilder().WithFunc(wasmEmitProposal).Export("emit_proposal").
+		NewFunctionBuilder().WithFunc(wasmLog).Export("log").
+		Instantiate(ctx)
+	if err != nil {
+		_ = r.Close(ctx)
+		return nil, err
+	}
+
+	return &wasmRuntime{
+		runtime:        r,
+		moduleDir:      moduleDir,
+		maxModuleBytes: maxModuleMiB * 1024 * 1024,
+		compiled:       map[[sha256.Size]byte]*wasmCompiled{},
+	}, nil
+}
+
+func getWasmMiB(cfg config.View, name string, defaultMiB, maxMiB int) (int, error) {
+	mib := defaultMiB
+	if cfg.IsSet(name) {
+		mib = cfg.GetInt(name)
+	}
+	if mib <= 0 || mib > maxMiB {
+		return 0, status.Errorf(codes.FailedPrecondition, "%s must be between 1 and %d, got %d", name, maxMiB, mib)
+	}
+	return mib, nil
+}
+
+func (w *wasmRuntime) close() {
+	if err := w.runtime.Close(context.Background()); err != nil {
+		logger.WithError(err).Warning("Error closing wasm runtime.")
+	}
+}
+
+// checkSize checks a module's binary isn't larger than wasmMaxModuleMiB.
+func (w *wasmRuntime) checkSize(size int64) error {
+	if size > int64(w.maxModuleBytes) {
+		return status.Errorf(codes.InvalidArgument, "wasm module is %d bytes, which is larger than the limit of %d bytes", size, w.maxModuleBytes)
+	}
+	return nil
+}
+
+// readModule reads the module at path, which must be inside moduleDir.
+// Relative paths are relative to moduleDir.
+func (w *wasmRuntime) readModule(path string) ([]byte, error) {
+	if w.moduleDir == "" {
+		return nil, status.Error(codes.FailedPrecondition, ".config.wasm_path can't be used because wasmModuleDir is not configured")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(w.moduleDir, path)
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read wasm module %q: %s", path, err.Error())
+	}
+	rel, err := filepath.Rel(w.moduleDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, status.Errorf(codes.InvalidArgument, "wasm module %q is not in wasmModuleDir", path)
+	}
+
+	f, err := os.Open(resolved)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read wasm module %q: %s", path, err.Error())
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read wasm module %q: %s", path, err.Error())
+	}
+	if !info.Mode().IsRegular() {
+		return nil, status.Errorf(codes.InvalidArgument, "wasm module %q is not a regular file", path)
+	}
+	if err = w.checkSize(info.Size()); err != nil {
+		return nil, err
+	}
+
+	// The file may grow after Stat, so don't read past the limit.
+	binary, err := io.ReadAll(io.LimitReader(f, int64(w.maxModuleBytes)+1))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read wasm module %q: %s", path, err.Error())
+	}
+	if err = w.checkSize(int64(len(binary))); err != nil {
+		return nil, err
+	}
+	return binary, nil
+}
+
+// compile returns the compiled module for the binary, along with a function
+// to call once done with it.  Modules whose memory is larger than
+// wasmMaxMemoryMiB fail to compile, so they are never instantiated.
+func (w *wasmRuntime) compile(ctx context.Context, binary []byte) (wazero.CompiledModule, func(), error) {
+	if err := w.checkSize(int64(len(binary))); err != nil {
+		return nil, nil, err
+	}
+	hash := sha256.Sum256(binary)
+
+	w.mu.Lock()
+	c, ok := w.compiled[hash]
+	if ok {
+		c.refs++
+		w.mu.Unlock()
+		return c.module, w.releaseFunc(c), nil
+	}
+	w.mu.Unlock()
+
+	module, err := w.runtime.CompileModule(ctx, binary)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "failed to compile wasm module: %s", err.Error())
+	}
+	if err = checkWasmExports(module); err != nil {
+		_ = module.Close(ctx)
+		return nil, nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if existing, ok := w.compiled[hash]; ok {
+		// Compiled concurrently by another call.
+		_ = module.Close(ctx)
+		existing.refs++
+		return existing.module, w.releaseFunc(existing), nil
+	}
+
+	c = &wasmCompiled{module: module, refs: 1}
+	w.compiled[hash] = c
+	w.order = append(w.order, hash)
+	if len(w.order) > wasmCompiledCacheSize {
+		oldest := w.compiled[w.order[0]]
+		delete(w.compiled, w.order[0])
+		w.order = w.order[1:]
+		oldest.evicted = true
+		if oldest.refs == 0 {
+			_ = oldest.module.Close(context.Background())
+		}
+	}
+	return module, w.releaseFunc(c), nil
+}
+
+// checkWasmExports checks the module exports the functions called by the
+// backend, with the right signatures.
+func checkWasmExports(module wazero.CompiledModule) error {
+	want := []struct {
+		name   string
+		params []api.ValueType
+	}{
+		{name: "alloc", params: []api.ValueType{api.ValueTypeI32}},
+		{name: "run", params: []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}},
+	}
+
+	for _, w := range want {
+		f, ok := module.ExportedFunctions()[w.name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "wasm module must export function %q", w.name)
+		}
+		if !reflect.DeepEqual(f.ParamTypes(), w.params) || !reflect.DeepEqual(f.ResultTypes(), []api.ValueType{api.ValueTypeI32}) {
+			return status.Errorf(codes.InvalidArgument, "wasm module function %q must take %d i32 params and return an i32", w.name, len(w.params))
+		}
+	}
+	return nil
+}
+
+func (w *wasmRuntime) releaseFunc(c *wasmCompiled) func() {
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		c.refs--
+		if c.evicted && c.refs == 0 {
+			_ = c.module.Close(context.Background())
+		}
+	}
+}
+
+// wasmCall is the state of a single match function run, available to host
+// functions through the context.
+type wasmCall struct {
+	name      string
+	query     pb.QueryServiceClient
+	proposals chan<- *pb.Match
+
+	// err is the first error of a host function, which is reported instead of
+	// the module's failure.
+	err error
+}
+
+type wasmCallKey struct{}
+
+func (c *wasmCall) fail(err error) uint32 {
+	if c.err == nil {
+		c.err = err
+	}
+	return 1
+}
+
+// run runs the match function in binary for profile.
+func (w *wasmRuntime) run(ctx context.Context, query pb.QueryServiceClient, name string, binary []byte, profile *pb.MatchProfile, proposals chan<- *pb.Match) error {
+	compiled, release, err := w.compile(ctx, binary)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	call := &wasmCall{
+		name:      name,
+		query:     query,
+		proposals: proposals,
+	}
+	ctx = context.WithValue(ctx, wasmCallKey{}, call)
+
+	// Reactor modules (eg. built by TinyGo or Rust for wasi) initialize
+	// themselves with _initialize, and must not run main.
+	mod, err := w.runtime.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return wasmCallErr(ctx, call, err)
+	}
+	defer mod.Close(context.Background())
+
+	profileBytes, err := proto.Marshal(profile)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal profile: %s", err.Error())
+	}
+	ptr, err := wasmWrite(ctx, mod, profileBytes)
+	if err != nil {
+		return wasmCallErr(ctx, call, err)
+	}
+
+	results, err := mod.ExportedFunction("run").Call(ctx, uint64(ptr), uint64(len(profileBytes)))
+	if err != nil {
+		return wasmCallErr(ctx, call, err)
+	}
+	if call.err != nil {
+		return call.err
+	}
+	if code := int32(results[0]); code != 0 {
+		return status.Errorf(codes.Unknown, "run returned %d", code)
+	}
+	return nil
+}
+
+// wasmCallErr explains why calling into the module failed.
+func wasmCallErr(ctx context.Context, call *wasmCall, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if call.err != nil {
+		return call.err
+	}
+	return status.Errorf(codes.Unknown, "wasm module failed: %s", err.Error())
+}
+
+// wasmWrite copies b into memory allocated by the module.
+func wasmWrite(ctx context.Context, mod api.Module, b []byte) (uint32, error) {
+	results, err := mod.ExportedFunction("alloc").Call(ctx, uint64(len(b)))
+	if err != nil {
+		return 0, err
+	}
+	ptr := uint32(results[0])
+	if !mod.Memory().Write(ptr, b) {
+		return 0, status.Errorf(codes.OutOfRange, "alloc returned %d, which is out of range for %d bytes", ptr, len(b))
+	}
+	return ptr, nil
+}
+
+// wasmRead copies length bytes at ptr from the module's memory.
+func wasmRead(mod api.Module, ptr, length uint32) ([]byte, error) {
+	b, ok := mod.Memory().Read(ptr, length)
+	if !ok {
+		return nil, status.Errorf(codes.OutOfRange, "%d bytes at %d is out of range", length, ptr)
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func wasmQueryPool(ctx context.Context, mod api.Module, poolPtr, poolLen, outPtrPtr, outLenPtr uint32) uint32 {
+	call := ctx.Value(wasmCallKey{}).(*wasmCall)
+
+	b, err := wasmRead(mod, poolPtr, poolLen)
+	if err != nil {
+		return call.fail(fmt.Errorf("query_pool: %w", err))
+	}
+	pool := &pb.Pool{}
+	if err = proto.Unmarshal(b, pool); err != nil {
+		return call.fail(status.Errorf(codes.InvalidArgument, "query_pool: failed to unmarshal pool: %s", err.Error()))
+	}
+
+	tickets, err := matchfunction.QueryPool(ctx, call.query, pool)
+	if err != nil {
+		return call.fail(err)
+	}
+	b, err = proto.Marshal(&pb.QueryTicketsResponse{Tickets: tickets})
+	if err != nil {
+		return call.fail(status.Errorf(codes.Internal, "query_pool: failed to marshal tickets: %s", err.Error()))
+	}
+
+	ptr, err := wasmWrite(ctx, mod, b)
+	if err != nil {
+		return call.fail(fmt.Errorf("query_pool: %w", err))
+	}
+	if !mod.Memory().WriteUint32Le(outPtrPtr, ptr) || !mod.Memory().WriteUint32Le(outLenPtr, uint32(len(b))) {
+		return call.fail(status.Error(codes.OutOfRange, "query_pool: output pointers are out of range"))
+	}
+	return 0
+}
+
+func wasmEmitProposal(ctx context.Context, mod api.Module, matchPtr, matchLen uint32) uint32 {
+	call := ctx.Value(wasmCallKey{}).(*wasmCall)
+
+	b, err := wasmRead(mod, matchPtr, matchLen)
+	if err != nil {
+		return call.fail(fmt.Errorf("emit_proposal: %w", err))
+	}
+	m := &pb.Match{}
+	if err = proto.Unmarshal(b, m); err != nil {
+		return call.fail(status.Errorf(codes.InvalidArgument, "emit_proposal: failed to unmarshal match: %s", err.Error()))
+	}
+
+	select {
+	case call.proposals <- m:
+		return 0
+	case <-ctx.Done():
+		return call.fail(ctx.Err())
+	}
+}
+
+func wasmLog(ctx context.Context, mod api.Module, msgPtr, msgLen uint32) {
+	call := ctx.Value(wasmCallKey{}).(*wasmCall)
+
+	b, err := wasmRead(mod, msgPtr, msgLen)
+	if err != nil {
+		logger.WithField("wasmModule", call.name).WithError(err).Warning("log: message is out of range")
+		return
+	}
+	logger.WithField("wasmModule", call.name).Info(string(b))
+}
+
+// callWasmMmf runs a WebAssembly match function, loaded from config's
+// wasm_path in wasmModuleDir if set, or else from the modules registered by
+// name.
+func callWasmMmf(ctx context.Context, w *wasmRuntime, query pb.QueryServiceClient, store statestore.Service, cfg *pb.FunctionConfig, profile *pb.MatchProfile, proposals chan<- *pb.Match) error {
+	name := cfg.GetName()
+	var binary []byte
+	var err error
+	if path := cfg.GetWasmPath(); path != "" {
+		name = path
+		binary, err = w.readModule(path)
+		if err != nil {
+			return err
+		}
+	} else {
+		if name == "" {
+			return status.Error(codes.InvalidArgument, ".config.name or .config.wasm_path is required for WASM match functions")
+		}
+		binary, err = store.GetWasmModule(ctx, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := w.run(ctx, query, name, binary, profile, proposals); err != nil {
+		return fmt.Errorf("failed to run match function %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,448 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// The test modules are assembled by hand, using these function types:
+const (
+	wasmTypeI32ToI32    = 0 // (i32) -> i32
+	wasmType2I32ToI32   = 1 // (i32, i32) -> i32
+	wasmType4I32ToI32   = 2 // (i32, i32, i32, i32) -> i32
+	wasmType2I32ToEmpty = 3 // (i32, i32) -> ()
+)
+
+type wasmTestImport struct {
+	name string
+	typ  byte
+}
+
+type wasmTestFunc struct {
+	export string
+	typ    byte
+	// locals is the number of i32 locals besides the params.
+	locals byte
+	code   []byte
+}
+
+// wasmTestAlloc is a bump allocator using global 0.
+var wasmTestAlloc = wasmTestFunc{
+	export: "alloc",
+	typ:    wasmTypeI32ToI32,
+	code: []byte{
+		0x23, 0x00, // global.get 0
+		0x23, 0x00, // global.get 0
+		0x20, 0x00, // local.get 0
+		0x6a,       // i32.add
+		0x24, 0x00, // global.set 0
+	},
+}
+
+func wasmULEB(v uint32) []byte {
+	var b []byte
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if v != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if v == 0 {
+			return b
+		}
+	}
+}
+
+func wasmI32Const(v int32) []byte {
+	b := []byte{0x41}
+	for {
+		c := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && c&0x40 == 0) || (v == -1 && c&0x40 != 0) {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
+
+func wasmVec(items ...[]byte) []byte {
+	b := wasmULEB(uint32(len(items)))
+	for _, item := range items {
+		b = append(b, item...)
+	}
+	return b
+}
+
+func wasmName(s string) []byte {
+	return append(wasmULEB(uint32(len(s))), s...)
+}
+
+func wasmSection(id byte, contents []byte) []byte {
+	return append(append([]byte{id}, wasmULEB(uint32(len(contents)))...), contents...)
+}
+
+func concat(parts ...[]byte) []byte {
+	var b []byte
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// buildWasm assembles a module importing host functions from the openmatch
+// module, followed by funcs.  It has one page of exported memory, data
+// written at offset 1024, and a heap for alloc starting at offset 4096.
+func buildWasm(imports []wasmTestImport, funcs []wasmTestFunc, data []byte) []byte {
+	i32 := byte(0x7f)
+	types := wasmVec(
+		concat([]byte{0x60}, wasmVec([]byte{i32}), wasmVec([]byte{i32})),
+		concat([]byte{0x60}, wasmVec([]byte{i32}, []byte{i32}), wasmVec([]byte{i32})),
+		concat([]byte{0x60}, wasmVec([]byte{i32}, []byte{i32}, []byte{i32}, []byte{i32}), wasmVec([]byte{i32})),
+		concat([]byte{0x60}, wasmVec([]byte{i32}, []byte{i32}), wasmVec()),
+	)
+
+	var importEntries, funcTypes, exports, code [][]byte
+	for _, imp := range imports {
+		importEntries = append(importEntries, concat(wasmName("openmatch"), wasmName(imp.name), []byte{0x00, imp.typ}))
+	}
+	exports = append(exports, concat(wasmName("memory"), []byte{0x02, 0x00}))
+	for i, f := range funcs {
+		funcTypes = append(funcTypes, []byte{f.typ})
+		if f.export != "" {
+			exports = append(exports, concat(wasmName(f.export), []byte{0x00}, wasmULEB(uint32(len(imports)+i))))
+		}
+		var locals []byte
+		if f.locals > 0 {
+			locals = wasmVec([]byte{f.locals, i32})
+		} else {
+			locals = wasmVec()
+		}
+		body := concat(locals, f.code, []byte{0x0b})
+		code = append(code, concat(wasmULEB(uint32(len(body))), body))
+	}
+
+	return concat(
+		[]byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00},
+		wasmSection(1, types),
+		wasmSection(2, wasmVec(importEntries...)),
+		wasmSection(3, wasmVec(funcTypes...)),
+		wasmSection(5, wasmVec([]byte{0x00, 0x01})),
+		wasmSection(6, wasmVec(concat([]byte{i32, 0x01}, wasmI32Const(4096), []byte{0x0b}))),
+		wasmSection(7, wasmVec(exports...)),
+		wasmSection(10, wasmVec(code...)),
+		wasmSection(11, wasmVec(concat([]byte{0x00}, wasmI32Const(1024), []byte{0x0b}, wasmVec(splitBytes(data)...)))),
+	)
+}
+
+func splitBytes(b []byte) [][]byte {
+	r := make([][]byte, len(b))
+	for i := range b {
+		r[i] = b[i : i+1]
+	}
+	return r
+}
+
+// wasmEchoProfile logs the profile, then emits it as a match.  A profile with
+// only a name reads as a match with that id.
+var wasmEchoProfile = buildWasm(
+	[]wasmTestImport{{"emit_proposal", wasmType2I32ToI32}, {"log", wasmType2I32ToEmpty}},
+	[]wasmTestFunc{wasmTestAlloc, {
+		export: "run",
+		typ:    wasmType2I32ToI32,
+		code: []byte{
+			0x20, 0x00, 0x20, 0x01, 0x10, 0x01, // log(profile_ptr, profile_len)
+			0x20, 0x00, 0x20, 0x01, 0x10, 0x00, // return emit_proposal(profile_ptr, profile_len)
+		},
+	}},
+	nil,
+)
+
+// wasmQueryTickets queries the pool at offset 1024, and emits a match with
+// the tickets by rewriting the tags of the QueryTicketsResponse's tickets to
+// those of Match.tickets.  Serialized tickets must be under 128 bytes.
+func wasmQueryTickets(pool *pb.Pool) []byte {
+	poolBytes, err := proto.Marshal(pool)
+	if err != nil {
+		panic(err)
+	}
+
+	code := concat(
+		wasmI32Const(1024), wasmI32Const(int32(len(poolBytes))), wasmI32Const(2048), wasmI32Const(2052),
+		[]byte{0x10, 0x00},                  // call query_pool
+		[]byte{0x04, 0x40},                  // if nonzero
+		wasmI32Const(1), []byte{0x0f, 0x0b}, // return 1, end
+		wasmI32Const(2048), []byte{0x28, 0x02, 0x00, 0x21, 0x02}, // pos = response ptr
+		[]byte{0x20, 0x02}, wasmI32Const(2052), []byte{0x28, 0x02, 0x00, 0x6a, 0x21, 0x03}, // end = pos + response len
+		[]byte{0x02, 0x40, 0x03, 0x40},                                   // block, loop
+		[]byte{0x20, 0x02, 0x20, 0x03, 0x49, 0x45, 0x0d, 0x01},           // br_if !(pos < end) out of the block
+		[]byte{0x20, 0x02}, wasmI32Const(0x22), []byte{0x3a, 0x00, 0x00}, // store8 pos 0x22
+		[]byte{0x20, 0x02, 0x20, 0x02, 0x2d, 0x00, 0x01, 0x6a}, wasmI32Const(2), []byte{0x6a, 0x21, 0x02}, // pos += 2 + load8_u(pos+1)
+		[]byte{0x0c, 0x00, 0x0b, 0x0b}, // br loop, end, end
+		wasmI32Const(2048), []byte{0x28, 0x02, 0x00}, wasmI32Const(2052), []byte{0x28, 0x02, 0x00},
+		[]byte{0x10, 0x01}, // return emit_proposal(response ptr, response len)
+	)
+
+	return buildWasm(
+		[]wasmTestImport{{"query_pool", wasmType4I32ToI32}, {"emit_proposal", wasmType2I32ToI32}},
+		[]wasmTestFunc{wasmTestAlloc, {export: "run", typ: wasmType2I32ToI32, locals: 2, code: code}},
+		poolBytes,
+	)
+}
+
+func wasmRun(code ...byte) []byte {
+	return buildWasm(nil, []wasmTestFunc{wasmTestAlloc, {export: "run", typ: wasmType2I32ToI32, code: code}}, nil)
+}
+
+type fakeQueryClient struct {
+	pb.QueryServiceClient
+	pools   []string
+	tickets []*pb.Ticket
+}
+
+func (c *fakeQueryClient) QueryTickets(ctx context.Context, req *pb.QueryTicketsRequest, opts ...grpc.CallOption) (pb.QueryService_QueryTicketsClient, error) {
+	c.pools = append(c.pools, req.GetPool().GetName())
+	return &fakeQueryTicketsClient{tickets: c.tickets}, nil
+}
+
+type fakeQueryTicketsClient struct {
+	grpc.ClientStream
+	tickets []*pb.Ticket
+	done    bool
+}
+
+func (c *fakeQueryTicketsClient) Recv() (*pb.QueryTicketsResponse, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	c.done = true
+	return &pb.QueryTicketsResponse{Tickets: c.tickets}, nil
+}
+
+func TestWasmRun(t *testing.T) {
+	w, err := newWasmRuntime(viper.New())
+	require.NoError(t, err)
+	defer w.close()
+
+	run := func(ctx context.Context, query pb.QueryServiceClient, binary []byte, profile *pb.MatchProfile) ([]*pb.Match, error) {
+		proposals := make(chan *pb.Match, 10)
+		err := w.run(ctx, query, "test", binary, profile, proposals)
+		close(proposals)
+		var matches []*pb.Match
+		for m := range proposals {
+			matches = append(matches, m)
+		}
+		return matches, err
+	}
+
+	ctx := utilTesting.NewContext(t)
+
+	matches, err := run(ctx, nil, wasmEchoProfile, &pb.MatchProfile{Name: "profile-1"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Equal(t, "profile-1", matches[0].GetMatchId())
+
+	// Instances don't share memory, so a second call gets a fresh heap.
+	matches, err = run(ctx, nil, wasmEchoProfile, &pb.MatchProfile{Name: "profile-2"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Equal(t, "profile-2", matches[0].GetMatchId())
+
+	query := &fakeQueryClient{tickets: []*pb.Ticket{{Id: "1"}, {Id: "2"}}}
+	matches, err = run(ctx, query, wasmQueryTickets(&pb.Pool{Name: "all"}), &pb.MatchProfile{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"all"}, query.pools)
+	require.Len(t, matches, 1)
+	require.Len(t, matches[0].GetTickets(), 2)
+	require.Equal(t, "1", matches[0].GetTickets()[0].GetId())
+	require.Equal(t, "2", matches[0].GetTickets()[1].GetId())
+
+	_, err = run(ctx, nil, wasmRun(wasmI32Const(7)...), &pb.MatchProfile{})
+	require.Equal(t, codes.Unknown, status.Code(err))
+	require.Contains(t, err.Error(), "run returned 7")
+
+	_, err = run(ctx, nil, wasmRun(0x00), &pb.MatchProfile{})
+	require.Equal(t, codes.Unknown, status.Code(err))
+	require.Contains(t, err.Error(), "unreachable")
+
+	// A module which never returns is stopped once the context is done.
+	loopCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	_, err = run(loopCtx, nil, wasmRun(concat([]byte{0x03, 0x40, 0x0c, 0x00, 0x0b}, wasmI32Const(0))...), &pb.MatchProfile{})
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = run(ctx, nil, []byte("not wasm"), &pb.MatchProfile{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = run(ctx, nil, buildWasm(nil, []wasmTestFunc{wasmTestAlloc}, nil), &pb.MatchProfile{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), `wasm module must export function "run"`)
+
+	_, err = run(ctx, nil, buildWasm(nil, []wasmTestFunc{wasmTestAlloc, {export: "run", typ: wasmTypeI32ToI32, code: wasmI32Const(0)}}, nil), &pb.MatchProfile{})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), `wasm module function "run" must take 2 i32 params and return an i32`)
+}
+
+func TestNewWasmRuntime(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("wasmMaxMemoryMiB", 0)
+	_, err := newWasmRuntime(cfg)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// The module's memory is larger than the limit.
+	cfg.Set("wasmMaxMemoryMiB", 1)
+	w, err := newWasmRuntime(cfg)
+	require.NoError(t, err)
+	defer w.close()
+	large := buildWasm(nil, []wasmTestFunc{wasmTestAlloc, {export: "run", typ: wasmType2I32ToI32, code: wasmI32Const(0)}}, nil)
+	// Bump the memory section's minimum from 1 to 17 pages.
+	large = replaceOnce(t, large, wasmSection(5, wasmVec([]byte{0x00, 0x01})), wasmSection(5, wasmVec([]byte{0x00, 0x11})))
+	// It fails to compile, so it is never instantiated.
+	err = w.run(utilTesting.NewContext(t), nil, "test", large, &pb.MatchProfile{}, make(chan *pb.Match))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	cfg = viper.New()
+	cfg.Set("wasmMaxModuleMiB", 0)
+	_, err = newWasmRuntime(cfg)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	cfg = viper.New()
+	cfg.Set("wasmModuleDir", "/does/not/exist")
+	_, err = newWasmRuntime(cfg)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func replaceOnce(t *testing.T, b, old, new []byte) []byte {
+	for i := 0; i+len(old) <= len(b); i++ {
+		if string(b[i:i+len(old)]) == string(old) {
+			return concat(b[:i], new, b[i+len(old):])
+		}
+	}
+	require.FailNow(t, "not found")
+	return nil
+}
+
+func TestRegisterWasmModule(t *testing.T) {
+	cfg := viper.New()
+	moduleDir := t.TempDir()
+	cfg.Set("wasmModuleDir", moduleDir)
+	cfg.Set("wasmMaxModuleMiB", 1)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+
+	w, err := newWasmRuntime(cfg)
+	require.NoError(t, err)
+	defer w.close()
+	s := &backendService{
+		cfg:   cfg,
+		store: store,
+		query: &fakeQueryClient{},
+		wasm:  w,
+	}
+
+	_, err = s.RegisterWasmModule(ctx, &pb.RegisterWasmModuleRequest{Module: wasmEchoProfile})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.RegisterWasmModule(ctx, &pb.RegisterWasmModuleRequest{Name: "echo"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = s.RegisterWasmModule(ctx, &pb.RegisterWasmModuleRequest{Name: "echo", Module: []byte("not wasm")})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	fetch := func(config *pb.FunctionConfig) ([]*pb.Match, error) {
+		proposals := make(chan *pb.Match, 10)
+		err := callWasmMmf(ctx, w, s.query, store, config, &pb.MatchProfile{Name: "profile"}, proposals)
+		close(proposals)
+		var matches []*pb.Match
+		for m := range proposals {
+			matches = append(matches, m)
+		}
+		return matches, err
+	}
+
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, Name: "echo"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.RegisterWasmModule(ctx, &pb.RegisterWasmModuleRequest{Name: "echo", Module: wasmEchoProfile})
+	require.NoError(t, err)
+
+	matches, err := fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, Name: "echo"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	require.Equal(t, "profile", matches[0].GetMatchId())
+
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: filepath.Join(moduleDir, "does-not-exist.wasm")})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	path := filepath.Join(moduleDir, "echo.wasm")
+	require.NoError(t, os.WriteFile(path, wasmEchoProfile, 0644))
+	matches, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: path})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	matches, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: "echo.wasm"})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	// Modules outside of wasmModuleDir can't be loaded, including through
+	// relative paths and symlinks.
+	outside := filepath.Join(t.TempDir(), "echo.wasm")
+	require.NoError(t, os.WriteFile(outside, wasmEchoProfile, 0644))
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: outside})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "is not in wasmModuleDir")
+	rel, err := filepath.Rel(moduleDir, outside)
+	require.NoError(t, err)
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: rel})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "is not in wasmModuleDir")
+	require.NoError(t, os.Symlink(outside, filepath.Join(moduleDir, "link.wasm")))
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: "link.wasm"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "is not in wasmModuleDir")
+
+	// Modules larger than wasmMaxModuleMiB are rejected.
+	large := make([]byte, 1024*1024+1)
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "large.wasm"), large, 0644))
+	_, err = fetch(&pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: "large.wasm"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "larger than the limit")
+	_, err = s.RegisterWasmModule(ctx, &pb.RegisterWasmModuleRequest{Name: "large", Module: large})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), "larger than the limit")
+
+	// Loading modules from the file system is disabled without wasmModuleDir.
+	w2, err := newWasmRuntime(viper.New())
+	require.NoError(t, err)
+	defer w2.close()
+	err = callWasmMmf(ctx, w2, s.query, store, &pb.FunctionConfig{Type: pb.FunctionConfig_WASM, WasmPath: path}, &pb.MatchProfile{}, make(chan *pb.Match, 10))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -19,13 +19,17 @@ import (
 	"context"
 	"math"
 	"sort"
+	"time"
 
 	"go.opencensus.io/stats"
 
 	"github.com/sirupsen/logrus"
 	"go.opencensus.io/stats/view"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/app/evaluator"
 	"open-match.dev/open-match/internal/appmain"
+	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -42,29 +46,255 @@ var (
 		Description: "Number of collided matches per default evaluator call",
 		Aggregation: view.Sum(),
 	}
+
+	// Waits from 1s to 1h, in milliseconds.
+	waitDistribution = view.Distribution(1000, 5000, 10000, 20000, 30000, 45000, 60000, 90000, 120000, 180000, 240000, 300000, 450000, 600000, 900000, 1200000, 1800000, 2700000, 3600000)
+
+	acceptedOldestTicketWait     = stats.Float64("open-match.dev/defaulteval/accepted_oldest_ticket_wait", "Wait of the oldest ticket of each accepted match", stats.UnitMilliseconds)
+	acceptedOldestTicketWaitView = &view.View{
+		Measure:     acceptedOldestTicketWait,
+		Name:        "open-match.dev/defaulteval/accepted_oldest_ticket_wait",
+		Description: "Wait of the oldest ticket of each accepted match",
+		Aggregation: waitDistribution,
+	}
+	rejectedOldestTicketWait     = stats.Float64("open-match.dev/defaulteval/rejected_oldest_ticket_wait", "Wait of the oldest ticket of each rejected match", stats.UnitMilliseconds)
+	rejectedOldestTicketWaitView = &view.View{
+		Measure:     rejectedOldestTicketWait,
+		Name:        "open-match.dev/defaulteval/rejected_oldest_ticket_wait",
+		Description: "Wait of the oldest ticket of each rejected match, high values show tickets starving",
+		Aggregation: waitDistribution,
+	}
+	waitBoost     = stats.Float64("open-match.dev/defaulteval/wait_boost", "Score added to matches for the wait of their oldest ticket", stats.UnitDimensionless)
+	waitBoostView = &view.View{
+		Measure:     waitBoost,
+		Name:        "open-match.dev/defaulteval/wait_boost",
+		Description: "Score added to matches for the wait of their oldest ticket",
+		Aggregation: view.Distribution(0.1, 1, 10, 100, 1000, 10000),
+	}
 )
 
 type matchInp struct {
 	match *pb.Match
 	inp   *pb.DefaultEvaluationCriteria
+	// wait is how long the oldest ticket of the match has waited.
+	wait time.Duration
+	// priority of the FetchMatches call which proposed the match.
+	priority int32
+}
+
+// defaultEvaluator scores matches by DefaultEvaluationCriteria.Score, plus a
+// boost for the wait of their oldest ticket.
+type defaultEvaluator struct {
+	waitBoostPerSecond float64
+	now                func() time.Time
 }
 
+const (
+	// selectionGreedy accepts matches in score order, skipping those which
+	// collide with an accepted match.
+	selectionGreedy = "greedy"
+	// selectionOptimal accepts the non-colliding matches with the highest total
+	// score.
+	selectionOptimal = "optimal"
+)
+
 // BindService define the initialization steps for this evaluator
 func BindService(p *appmain.Params, b *appmain.Bindings) error {
-	if err := evaluator.BindServiceFor(evaluate)(p, b); err != nil {
+	eval, err := newEvaluator(p.Config())
+	if err != nil {
+		return err
+	}
+	if err := evaluator.BindServiceForExplaining(eval)(p, b); err != nil {
+		return err
+	}
+	b.RegisterViews(
+		collidedMatchesPerEvaluateView,
+		acceptedOldestTicketWaitView,
+		rejectedOldestTicketWaitView,
+		waitBoostView,
+	)
+	return nil
+}
+
+// newEvaluator returns the evaluator using the selection configured by
+// evaluatorSelection, and the wait time boost configured by
+// evaluatorWaitBoostPerSecond.
+func newEvaluator(cfg config.View) (evaluator.ExplainingEvaluator, error) {
+	const (
+		selectionName = "evaluatorSelection"
+		boostName     = "evaluatorWaitBoostPerSecond"
+	)
+
+	e := &defaultEvaluator{now: time.Now}
+	if cfg.IsSet(boostName) {
+		e.waitBoostPerSecond = cfg.GetFloat64(boostName)
+		if e.waitBoostPerSecond < 0 || math.IsNaN(e.waitBoostPerSecond) || math.IsInf(e.waitBoostPerSecond, 0) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s must be a non-negative number, got %v", boostName, e.waitBoostPerSecond)
+		}
+	}
+
+	selection := selectionGreedy
+	if cfg.IsSet(selectionName) {
+		selection = cfg.GetString(selectionName)
+	}
+
+	switch selection {
+	case selectionGreedy, "":
+		return e.evaluate, nil
+	case selectionOptimal:
+		return e.evaluateOptimal, nil
+	default:
+		return nil, status.Errorf(codes.FailedPrecondition, "unknown %s %q, must be %q or %q", selectionName, selection, selectionGreedy, selectionOptimal)
+	}
+}
+
+// evaluate sorts the matches by priority, then DefaultEvaluationCriteria.Score
+// (optional), then returns matches which don't collide with previously
+// returned matches.
+func (e *defaultEvaluator) evaluate(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+	matches, err := e.readMatches(ctx, in, rejected)
+	if err != nil {
 		return err
 	}
-	b.RegisterViews(collidedMatchesPerEvaluateView)
+
+	d := decollider{
+		ticketsUsed:   make(map[string]*collidingMatch),
+		backfillsUsed: make(map[string]*collidingMatch),
+	}
+
+	for _, m := range matches {
+		d.maybeAdd(m)
+	}
+
+	stats.Record(context.Background(), collidedMatchesPerEvaluate.M(int64(len(matches)-len(d.resultIDs))))
+
+	accepted := make(map[string]bool, len(d.resultIDs))
+	for _, id := range d.resultIDs {
+		accepted[id] = true
+	}
+	isAccepted := make([]bool, len(matches))
+	for i, m := range matches {
+		isAccepted[i] = accepted[m.match.GetMatchId()]
+		recordWait(m, isAccepted[i])
+	}
+
+	for _, id := range d.resultIDs {
+		select {
+		case out <- id:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return explainRejections(ctx, matches, isAccepted, "", rejected)
+}
+
+// evaluateOptimal returns the matches which don't collide with each other
+// with the highest total DefaultEvaluationCriteria.Score, so that a match is
+// rejected in favor of several matches it collides with when they are worth
+// more together.  Matches of a higher priority are selected first.
+func (e *defaultEvaluator) evaluateOptimal(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+	matches, err := e.readMatches(ctx, in, rejected)
+	if err != nil {
+		return err
+	}
+
+	selected := selectOptimalByPriority(matches)
+
+	accepted := make([]bool, len(matches))
+	for _, i := range selected {
+		accepted[i] = true
+	}
+	for i, m := range matches {
+		recordWait(m, accepted[i])
+		if !accepted[i] {
+			logger.WithFields(logrus.Fields{
+				"match_id":    m.match.GetMatchId(),
+				"match_score": m.inp.GetScore(),
+			}).Info("Match collides with non-colliding matches of a higher total score. Rejecting match.")
+		}
+	}
+
+	stats.Record(context.Background(), collidedMatchesPerEvaluate.M(int64(len(matches)-len(selected))))
+
+	for _, i := range selected {
+		select {
+		case out <- matches[i].match.GetMatchId():
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return explainRejections(ctx, matches, accepted, "Accepted matches it collides with have a higher total score.", rejected)
+}
+
+// recordWait records the wait of the match's oldest ticket, so that tickets
+// starving behind better scored matches show up in the rejected waits.
+func recordWait(m *matchInp, accepted bool) {
+	if m.wait <= 0 {
+		return
+	}
+	if accepted {
+		stats.Record(context.Background(), acceptedOldestTicketWait.M(float64(m.wait)/float64(time.Millisecond)))
+	} else {
+		stats.Record(context.Background(), rejectedOldestTicketWait.M(float64(m.wait)/float64(time.Millisecond)))
+	}
+}
+
+// explainRejections sends the reason each match which wasn't accepted was
+// rejected, along with the first accepted match it collides with.
+func explainRejections(ctx context.Context, matches []*matchInp, accepted []bool, description string, rejected chan<- *pb.MatchRejection) error {
+	ticketWinners := map[string]string{}
+	backfillWinners := map[string]string{}
+	for i, m := range matches {
+		if !accepted[i] {
+			continue
+		}
+		for _, t := range m.match.GetTickets() {
+			ticketWinners[t.GetId()] = m.match.GetMatchId()
+		}
+		if id := m.match.GetBackfill().GetId(); id != "" {
+			backfillWinners[id] = m.match.GetMatchId()
+		}
+	}
+
+	for i, m := range matches {
+		if accepted[i] {
+			continue
+		}
+		r := &pb.MatchRejection{
+			MatchId:     m.match.GetMatchId(),
+			Description: description,
+		}
+		if winner, ok := backfillWinners[m.match.GetBackfill().GetId()]; ok {
+			r.Reason = pb.MatchRejection_BACKFILL_COLLISION
+			r.WinningMatchId = winner
+		} else {
+			for _, t := range m.match.GetTickets() {
+				if winner, ok := ticketWinners[t.GetId()]; ok {
+					r.Reason = pb.MatchRejection_TICKET_COLLISION
+					r.WinningMatchId = winner
+					break
+				}
+			}
+		}
+		select {
+		case rejected <- r:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 	return nil
 }
 
-// evaluate sorts the matches by DefaultEvaluationCriteria.Score (optional),
-// then returns matches which don't collide with previously returned matches.
-func evaluate(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+// readMatches reads the matches with their DefaultEvaluationCriteria, sorted
+// by descending priority, then descending score after the wait time boost.  Matches with an invalid
+// DefaultEvaluationCriteria are rejected.
+func (e *defaultEvaluator) readMatches(ctx context.Context, in <-chan *pb.EvaluateRequest, rejected chan<- *pb.MatchRejection) ([]*matchInp, error) {
 	matches := make([]*matchInp, 0)
 	nilEvaluationInputs := 0
+	now := e.now()
 
-	for m := range in {
+	for req := range in {
+		m := req.GetMatch()
 		// Evaluation criteria is optional, but sort it lower than any matches which
 		// provided criteria.
 		inp := &pb.DefaultEvaluationCriteria{
@@ -78,14 +308,38 @@ func evaluate(ctx context.Context, in <-chan *pb.Match, out chan<- string) error
 					"match_id": m.MatchId,
 					"error":    err,
 				}).Error("Failed to unmarshal match's DefaultEvaluationCriteria.  Rejecting match.")
+				r := &pb.MatchRejection{
+					MatchId:     m.GetMatchId(),
+					Reason:      pb.MatchRejection_INVALID_EVALUATION_INPUT,
+					Description: err.Error(),
+				}
+				select {
+				case rejected <- r:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
 				continue
 			}
 		} else {
 			nilEvaluationInputs++
 		}
+
+		wait := oldestTicketWait(m, inp, now)
+		rate := e.waitBoostPerSecond
+		if inp.GetWaitBoostPerSecond() > 0 {
+			rate = inp.GetWaitBoostPerSecond()
+		}
+		if rate > 0 && wait > 0 && !math.IsInf(inp.Score, 0) {
+			boost := rate * wait.Seconds()
+			inp.Score += boost
+			stats.Record(context.Background(), waitBoost.M(boost))
+		}
+
 		matches = append(matches, &matchInp{
-			match: m,
-			inp:   inp,
+			match:    m,
+			inp:      inp,
+			wait:     wait,
+			priority: req.GetPriority(),
 		})
 	}
 
@@ -97,22 +351,30 @@ func evaluate(ctx context.Context, in <-chan *pb.Match, out chan<- string) error
 
 	sort.Sort(byScore(matches))
 
-	d := decollider{
-		ticketsUsed:   make(map[string]*collidingMatch),
-		backfillsUsed: make(map[string]*collidingMatch),
-	}
+	return matches, nil
+}
 
-	for _, m := range matches {
-		d.maybeAdd(m)
+// oldestTicketWait returns how long the longest waiting ticket of the match
+// has waited, from DefaultEvaluationCriteria.oldest_ticket_create_time if set,
+// or else the create_time of the match's tickets.
+func oldestTicketWait(m *pb.Match, inp *pb.DefaultEvaluationCriteria, now time.Time) time.Duration {
+	var oldest time.Time
+	if inp.GetOldestTicketCreateTime() != nil {
+		oldest = inp.GetOldestTicketCreateTime().AsTime()
+	} else {
+		for _, t := range m.GetTickets() {
+			if t.GetCreateTime() == nil {
+				continue
+			}
+			if created := t.GetCreateTime().AsTime(); oldest.IsZero() || created.Before(oldest) {
+				oldest = created
+			}
+		}
 	}
-
-	stats.Record(context.Background(), collidedMatchesPerEvaluate.M(int64(len(matches)-len(d.resultIDs))))
-
-	for _, id := range d.resultIDs {
-		out <- id
+	if oldest.IsZero() || oldest.After(now) {
+		return 0
 	}
-
-	return nil
+	return now.Sub(oldest)
 }
 
 type collidingMatch struct {
@@ -181,5 +443,8 @@ func (m byScore) Swap(i, j int) {
 }
 
 func (m byScore) Less(i, j int) bool {
+	if m[i].priority != m[j].priority {
+		return m[i].priority > m[j].priority
+	}
 	return m[i].inp.Score > m[j].inp.Score
 }
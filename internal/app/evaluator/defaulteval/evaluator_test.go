@@ -17,10 +17,16 @@ package defaulteval
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"open-match.dev/open-match/internal/app/evaluator"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -177,30 +183,247 @@ func TestEvaluate(t *testing.T) {
 		},
 	}
 
+	// Without matches worth more together than a match they collide with,
+	// both selections agree.
+	for name, eval := range map[string]evaluator.ExplainingEvaluator{selectionGreedy: (&defaultEvaluator{now: time.Now}).evaluate, selectionOptimal: (&defaultEvaluator{now: time.Now}).evaluateOptimal} {
+		eval := eval
+		for _, test := range tests {
+			test := test
+			t.Run(name+" "+test.description, func(t *testing.T) {
+				t.Parallel()
+				in := make(chan *pb.EvaluateRequest, 10)
+				out := make(chan string, 10)
+				for _, m := range test.testMatches {
+					in <- &pb.EvaluateRequest{Match: m}
+				}
+				close(in)
+
+				err := eval(context.Background(), in, out, make(chan *pb.MatchRejection, 10))
+				require.Nil(t, err)
+
+				gotMatchIDs := []string{}
+				close(out)
+				for id := range out {
+					gotMatchIDs = append(gotMatchIDs, id)
+				}
+				require.Equal(t, len(test.wantMatchIDs), len(gotMatchIDs))
+
+				for _, mID := range gotMatchIDs {
+					require.Contains(t, test.wantMatchIDs, mID)
+				}
+			})
+		}
+	}
+}
+
+func TestNewEvaluator(t *testing.T) {
+	cfg := viper.New()
+	_, err := newEvaluator(cfg)
+	require.NoError(t, err)
+
+	for _, selection := range []string{selectionGreedy, selectionOptimal} {
+		cfg.Set("evaluatorSelection", selection)
+		_, err = newEvaluator(cfg)
+		require.NoError(t, err)
+	}
+
+	cfg.Set("evaluatorSelection", "best")
+	_, err = newEvaluator(cfg)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	cfg = viper.New()
+	cfg.Set("evaluatorWaitBoostPerSecond", 0.5)
+	_, err = newEvaluator(cfg)
+	require.NoError(t, err)
+
+	cfg.Set("evaluatorWaitBoostPerSecond", -1)
+	_, err = newEvaluator(cfg)
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestEvaluateWaitBoost(t *testing.T) {
+	now := time.Now()
+	created := func(age time.Duration) *timestamppb.Timestamp {
+		return timestamppb.New(now.Add(-age))
+	}
+
+	// A fresh match scored higher than a match with a ticket waiting for a
+	// minute, which collide on ticket 2.
+	fresh := &pb.Match{
+		MatchId: "fresh",
+		Tickets: []*pb.Ticket{{Id: "1", CreateTime: created(time.Second)}, {Id: "2", CreateTime: created(time.Second)}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 50}),
+		},
+	}
+	old := &pb.Match{
+		MatchId: "old",
+		Tickets: []*pb.Ticket{{Id: "2", CreateTime: created(time.Second)}, {Id: "3", CreateTime: created(time.Minute)}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 10}),
+		},
+	}
+	// Ticket 4 was re-created, but the match function knows its player has
+	// waited for a minute.
+	recreated := &pb.Match{
+		MatchId: "recreated",
+		Tickets: []*pb.Ticket{{Id: "1", CreateTime: created(time.Second)}, {Id: "4", CreateTime: created(time.Second)}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{
+				Score:                  10,
+				OldestTicketCreateTime: created(time.Minute),
+			}),
+		},
+	}
+	// The match function opts in to a boost for this match.
+	optIn := &pb.Match{
+		MatchId: "optIn",
+		Tickets: []*pb.Ticket{{Id: "2", CreateTime: created(time.Minute)}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{
+				Score:              10,
+				WaitBoostPerSecond: 2,
+			}),
+		},
+	}
+
+	tests := []struct {
+		description        string
+		waitBoostPerSecond float64
+		matches            []*pb.Match
+		want               []string
+	}{
+		{
+			description: "without boost the higher score wins",
+			matches:     []*pb.Match{fresh, old},
+			want:        []string{"fresh"},
+		},
+		{
+			description:        "boost too small",
+			waitBoostPerSecond: 0.5,
+			matches:            []*pb.Match{fresh, old},
+			want:               []string{"fresh"},
+		},
+		{
+			description:        "boost prioritizes the oldest ticket",
+			waitBoostPerSecond: 1,
+			matches:            []*pb.Match{fresh, old},
+			want:               []string{"old"},
+		},
+		{
+			description:        "oldest_ticket_create_time overrides create_time",
+			waitBoostPerSecond: 1,
+			matches:            []*pb.Match{fresh, recreated},
+			want:               []string{"recreated"},
+		},
+		{
+			description: "wait_boost_per_second overrides the configured boost",
+			matches:     []*pb.Match{fresh, optIn},
+			want:        []string{"optIn"},
+		},
+	}
+
 	for _, test := range tests {
-		test := test
-		t.Run(test.description, func(t *testing.T) {
-			t.Parallel()
-			in := make(chan *pb.Match, 10)
-			out := make(chan string, 10)
-			for _, m := range test.testMatches {
-				in <- m
+		e := &defaultEvaluator{
+			waitBoostPerSecond: test.waitBoostPerSecond,
+			now:                func() time.Time { return now },
+		}
+		for name, eval := range map[string]evaluator.ExplainingEvaluator{selectionGreedy: e.evaluate, selectionOptimal: e.evaluateOptimal} {
+			in := make(chan *pb.EvaluateRequest, len(test.matches))
+			out := make(chan string, len(test.matches))
+			for _, m := range test.matches {
+				in <- &pb.EvaluateRequest{Match: m}
 			}
 			close(in)
 
-			err := evaluate(context.Background(), in, out)
-			require.Nil(t, err)
-
-			gotMatchIDs := []string{}
+			require.NoError(t, eval(context.Background(), in, out, make(chan *pb.MatchRejection, len(test.matches))))
 			close(out)
+			got := []string{}
 			for id := range out {
-				gotMatchIDs = append(gotMatchIDs, id)
+				got = append(got, id)
 			}
-			require.Equal(t, len(test.wantMatchIDs), len(gotMatchIDs))
+			require.Equal(t, test.want, got, "%s %s", name, test.description)
+		}
+	}
+}
 
-			for _, mID := range gotMatchIDs {
-				require.Contains(t, test.wantMatchIDs, mID)
-			}
-		})
+func TestEvaluateRejections(t *testing.T) {
+	withScore := func(score float64) map[string]*anypb.Any {
+		return map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: score}),
+		}
+	}
+	matches := []*pb.Match{
+		{MatchId: "a", Tickets: []*pb.Ticket{{Id: "1"}, {Id: "2"}}, Extensions: withScore(10)},
+		{MatchId: "b", Tickets: []*pb.Ticket{{Id: "2"}}, Extensions: withScore(5)},
+		{MatchId: "c", Tickets: []*pb.Ticket{{Id: "3"}}, Backfill: &pb.Backfill{Id: "bf"}, Extensions: withScore(10)},
+		{MatchId: "d", Tickets: []*pb.Ticket{{Id: "4"}}, Backfill: &pb.Backfill{Id: "bf"}, Extensions: withScore(1)},
+		{MatchId: "e", Tickets: []*pb.Ticket{{Id: "5"}}, Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.Ticket{Id: "not criteria"}),
+		}},
+	}
+
+	e := &defaultEvaluator{now: time.Now}
+	for name, eval := range map[string]evaluator.ExplainingEvaluator{selectionGreedy: e.evaluate, selectionOptimal: e.evaluateOptimal} {
+		in := make(chan *pb.EvaluateRequest, len(matches))
+		out := make(chan string, len(matches))
+		rejected := make(chan *pb.MatchRejection, len(matches))
+		for _, m := range matches {
+			in <- &pb.EvaluateRequest{Match: m}
+		}
+		close(in)
+
+		require.NoError(t, eval(context.Background(), in, out, rejected), name)
+		close(out)
+		close(rejected)
+
+		got := []string{}
+		for id := range out {
+			got = append(got, id)
+		}
+		require.ElementsMatch(t, []string{"a", "c"}, got, name)
+
+		rejections := map[string]*pb.MatchRejection{}
+		for r := range rejected {
+			rejections[r.GetMatchId()] = r
+		}
+		require.Len(t, rejections, 3, name)
+		require.Equal(t, pb.MatchRejection_TICKET_COLLISION, rejections["b"].GetReason(), name)
+		require.Equal(t, "a", rejections["b"].GetWinningMatchId(), name)
+		require.Equal(t, pb.MatchRejection_BACKFILL_COLLISION, rejections["d"].GetReason(), name)
+		require.Equal(t, "c", rejections["d"].GetWinningMatchId(), name)
+		require.Equal(t, pb.MatchRejection_INVALID_EVALUATION_INPUT, rejections["e"].GetReason(), name)
+		require.Empty(t, rejections["e"].GetWinningMatchId(), name)
+	}
+}
+
+func TestEvaluatePriority(t *testing.T) {
+	// The ranked match wins the collision despite its lower score, because
+	// its FetchMatches call has a higher priority.
+	reqs := []*pb.EvaluateRequest{
+		{Match: &pb.Match{MatchId: "casual", Tickets: []*pb.Ticket{{Id: "1"}}, Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 10}),
+		}}},
+		{Match: &pb.Match{MatchId: "ranked", Tickets: []*pb.Ticket{{Id: "1"}}, Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 1}),
+		}}, Priority: 1},
+	}
+
+	e := &defaultEvaluator{now: time.Now}
+	for name, eval := range map[string]evaluator.ExplainingEvaluator{selectionGreedy: e.evaluate, selectionOptimal: e.evaluateOptimal} {
+		in := make(chan *pb.EvaluateRequest, len(reqs))
+		for _, req := range reqs {
+			in <- req
+		}
+		close(in)
+		out := make(chan string, len(reqs))
+
+		require.NoError(t, eval(context.Background(), in, out, make(chan *pb.MatchRejection, len(reqs))), name)
+		close(out)
+		got := []string{}
+		for id := range out {
+			got = append(got, id)
+		}
+		require.Equal(t, []string{"ranked"}, got, name)
 	}
 }
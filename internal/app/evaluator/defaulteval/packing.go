@@ -0,0 +1,384 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaulteval
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+)
+
+const (
+	// Groups of colliding matches up to this size have their best selection
+	// found exactly.  The search is exponential in the worst case.
+	exactSelectionLimit = 24
+
+	// Bound on the passes over a larger group of colliding matches looking for
+	// swaps which improve its selection.
+	localSearchPasses = 50
+)
+
+// collisionGraph links matches which share a ticket or backfill, so at most
+// one of them can be accepted.
+type collisionGraph struct {
+	weights   []float64
+	neighbors [][]int
+}
+
+func newCollisionGraph(matches []*matchInp) *collisionGraph {
+	g := &collisionGraph{
+		weights:   make([]float64, len(matches)),
+		neighbors: make([][]int, len(matches)),
+	}
+
+	byTicket := map[string][]int{}
+	byBackfill := map[string][]int{}
+	for i, m := range matches {
+		g.weights[i] = m.inp.GetScore()
+		for _, t := range m.match.GetTickets() {
+			byTicket[t.GetId()] = append(byTicket[t.GetId()], i)
+		}
+		if id := m.match.GetBackfill().GetId(); id != "" {
+			byBackfill[id] = append(byBackfill[id], i)
+		}
+	}
+
+	neighbors := make([]map[int]struct{}, len(matches))
+	link := func(shared []int) {
+		for _, i := range shared {
+			for _, j := range shared {
+				if i == j {
+					continue
+				}
+				if neighbors[i] == nil {
+					neighbors[i] = map[int]struct{}{}
+				}
+				neighbors[i][j] = struct{}{}
+			}
+		}
+	}
+	for _, shared := range byTicket {
+		link(shared)
+	}
+	for _, shared := range byBackfill {
+		link(shared)
+	}
+
+	for i, ns := range neighbors {
+		for j := range ns {
+			g.neighbors[i] = append(g.neighbors[i], j)
+		}
+		sort.Ints(g.neighbors[i])
+	}
+	return g
+}
+
+// optimized reports whether the match takes part in the search for the highest
+// total score.  Matches with a score which doesn't add to the total are only
+// accepted if they fit around the others.
+func (g *collisionGraph) optimized(i int) bool {
+	w := g.weights[i]
+	return w > 0 && !math.IsInf(w, 1) && !math.IsNaN(w)
+}
+
+// components splits the optimized matches into groups which only collide
+// within the group.  Each group is in ascending order.
+func (g *collisionGraph) components() [][]int {
+	seen := make([]bool, len(g.weights))
+	var components [][]int
+	for i := range g.weights {
+		if seen[i] || !g.optimized(i) {
+			continue
+		}
+		seen[i] = true
+		component := []int{i}
+		for next := 0; next < len(component); next++ {
+			for _, n := range g.neighbors[component[next]] {
+				if !seen[n] && g.optimized(n) {
+					seen[n] = true
+					component = append(component, n)
+				}
+			}
+		}
+		sort.Ints(component)
+		components = append(components, component)
+	}
+	return components
+}
+
+// packing is a set of selected matches, none of which collide.
+type packing struct {
+	g        *collisionGraph
+	selected []bool
+	// conflicts counts the selected neighbors of each match.
+	conflicts []int
+}
+
+func (p *packing) add(i int) {
+	p.selected[i] = true
+	for _, n := range p.g.neighbors[i] {
+		p.conflicts[n]++
+	}
+}
+
+func (p *packing) remove(i int) {
+	p.selected[i] = false
+	for _, n := range p.g.neighbors[i] {
+		p.conflicts[n]--
+	}
+}
+
+// fill selects the matches of candidates which don't collide with the
+// selection, in order.
+func (p *packing) fill(candidates []int) {
+	for _, i := range candidates {
+		if !p.selected[i] && p.conflicts[i] == 0 {
+			p.add(i)
+		}
+	}
+}
+
+// selectOptimal returns the indexes of non-colliding matches with the highest
+// total score, in ascending order.  matches must be sorted by descending
+// score.  Matches with an infinite score are accepted first, and matches which
+// don't add to the total are accepted in score order once the rest are
+// picked, as long as they don't collide.
+func selectOptimal(matches []*matchInp) []int {
+	g := newCollisionGraph(matches)
+	p := &packing{
+		g:         g,
+		selected:  make([]bool, len(matches)),
+		conflicts: make([]int, len(matches)),
+	}
+
+	var infinite []int
+	for i, w := range g.weights {
+		if math.IsInf(w, 1) {
+			infinite = append(infinite, i)
+		}
+	}
+	p.fill(infinite)
+
+	for _, component := range g.components() {
+		var available []int
+		for _, i := range component {
+			if p.conflicts[i] == 0 {
+				available = append(available, i)
+			}
+		}
+
+		if len(available) <= exactSelectionLimit {
+			p.fill(g.exact(available))
+		} else {
+			p.localSearch(available)
+		}
+	}
+
+	all := make([]int, len(matches))
+	for i := range all {
+		all[i] = i
+	}
+	p.fill(all)
+
+	var result []int
+	for i, s := range p.selected {
+		if s {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// selectOptimalByPriority returns the indexes of non-colliding matches, in
+// ascending order, selecting the highest total score among the matches of each
+// priority in turn, which don't collide with those of higher priorities.
+// matches must be sorted by descending priority, then descending score.
+func selectOptimalByPriority(matches []*matchInp) []int {
+	var selected []int
+	ticketsUsed := map[string]bool{}
+	backfillsUsed := map[string]bool{}
+
+	for start := 0; start < len(matches); {
+		end := start
+		var tier []int
+		for ; end < len(matches) && matches[end].priority == matches[start].priority; end++ {
+			if !collides(matches[end], ticketsUsed, backfillsUsed) {
+				tier = append(tier, end)
+			}
+		}
+
+		tierMatches := make([]*matchInp, len(tier))
+		for j, i := range tier {
+			tierMatches[j] = matches[i]
+		}
+		for _, j := range selectOptimal(tierMatches) {
+			m := matches[tier[j]]
+			for _, t := range m.match.GetTickets() {
+				ticketsUsed[t.GetId()] = true
+			}
+			if id := m.match.GetBackfill().GetId(); id != "" {
+				backfillsUsed[id] = true
+			}
+			selected = append(selected, tier[j])
+		}
+		start = end
+	}
+	return selected
+}
+
+func collides(m *matchInp, ticketsUsed, backfillsUsed map[string]bool) bool {
+	if backfillsUsed[m.match.GetBackfill().GetId()] {
+		return true
+	}
+	for _, t := range m.match.GetTickets() {
+		if ticketsUsed[t.GetId()] {
+			return true
+		}
+	}
+	return false
+}
+
+// exact returns the non-colliding subset of candidates with the highest total
+// weight, using branch and bound.  candidates must be sorted by descending
+// weight, and number at most 64.
+func (g *collisionGraph) exact(candidates []int) []int {
+	local := make(map[int]uint, len(candidates))
+	for i, c := range candidates {
+		local[c] = uint(i)
+	}
+	masks := make([]uint64, len(candidates))
+	for i, c := range candidates {
+		for _, n := range g.neighbors[c] {
+			if j, ok := local[n]; ok {
+				masks[i] |= 1 << j
+			}
+		}
+	}
+	weight := func(i int) float64 {
+		return g.weights[candidates[i]]
+	}
+
+	var best float64
+	var bestSet uint64
+	var search func(remaining, set uint64, total float64)
+	search = func(remaining, set uint64, total float64) {
+		if total > best {
+			best, bestSet = total, set
+		}
+
+		bound := total
+		for r := remaining; r != 0; r &= r - 1 {
+			bound += weight(bits.TrailingZeros64(r))
+		}
+		if bound <= best {
+			return
+		}
+
+		// The heaviest remaining candidate is either in the selection, or not.
+		v := uint(bits.TrailingZeros64(remaining))
+		search(remaining&^(1<<v|masks[v]), set|1<<v, total+weight(int(v)))
+		if masks[v]&remaining != 0 {
+			search(remaining&^(1<<v), set, total)
+		}
+	}
+
+	all := uint64(1)<<uint(len(candidates)) - 1
+	if len(candidates) == 64 {
+		all = math.MaxUint64
+	}
+	search(all, 0, 0)
+
+	var result []int
+	for s := bestSet; s != 0; s &= s - 1 {
+		result = append(result, candidates[bits.TrailingZeros64(s)])
+	}
+	return result
+}
+
+// localSearch selects from candidates starting from the greedy selection, then
+// swapping a match in for the matches it collides with, or a match out for
+// the matches only it collides with, while that increases the total weight.
+// candidates must be sorted by descending weight.
+func (p *packing) localSearch(candidates []int) {
+	w := p.g.weights
+	improves := func(gain, loss float64) bool {
+		return gain > loss+1e-9*math.Max(1, math.Abs(loss))
+	}
+
+	p.fill(candidates)
+	for pass := 0; pass < localSearchPasses; pass++ {
+		improved := false
+
+		for _, v := range candidates {
+			if p.selected[v] || p.conflicts[v] == 0 {
+				continue
+			}
+			loss := 0.0
+			for _, n := range p.g.neighbors[v] {
+				if p.selected[n] {
+					loss += w[n]
+				}
+			}
+			if improves(w[v], loss) {
+				for _, n := range p.g.neighbors[v] {
+					if p.selected[n] {
+						p.remove(n)
+					}
+				}
+				p.add(v)
+				improved = true
+			}
+		}
+
+		for _, u := range candidates {
+			if !p.selected[u] {
+				continue
+			}
+			// Matches which only collide with u, picked heaviest first.
+			var freed []int
+			inFreed := map[int]bool{}
+			gain := 0.0
+			for _, n := range p.g.neighbors[u] {
+				if p.selected[n] || p.conflicts[n] != 1 || !p.g.optimized(n) {
+					continue
+				}
+				ok := true
+				for _, nn := range p.g.neighbors[n] {
+					if inFreed[nn] {
+						ok = false
+						break
+					}
+				}
+				if ok {
+					freed = append(freed, n)
+					inFreed[n] = true
+					gain += w[n]
+				}
+			}
+			if improves(gain, w[u]) {
+				p.remove(u)
+				for _, n := range freed {
+					p.add(n)
+				}
+				improved = true
+			}
+		}
+
+		p.fill(candidates)
+		if !improved {
+			return
+		}
+	}
+}
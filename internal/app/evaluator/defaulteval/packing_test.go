@@ -0,0 +1,272 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaulteval
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"open-match.dev/open-match/internal/app/evaluator"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func newMatchInp(id string, score float64, ticketIDs ...string) *matchInp {
+	m := &pb.Match{MatchId: id}
+	for _, t := range ticketIDs {
+		m.Tickets = append(m.Tickets, &pb.Ticket{Id: t})
+	}
+	return &matchInp{match: m, inp: &pb.DefaultEvaluationCriteria{Score: score}}
+}
+
+// randomTickets returns up to max distinct ticket ids out of n.
+func randomTickets(r *rand.Rand, max, n int) []string {
+	seen := map[string]bool{}
+	var tickets []string
+	for j := 0; j < 1+r.Intn(max); j++ {
+		id := fmt.Sprint(r.Intn(n))
+		if !seen[id] {
+			seen[id] = true
+			tickets = append(tickets, id)
+		}
+	}
+	return tickets
+}
+
+func sortedByScore(matches ...*matchInp) []*matchInp {
+	sort.Sort(byScore(matches))
+	return matches
+}
+
+func selectedIDs(matches []*matchInp, selected []int) []string {
+	ids := []string{}
+	for _, i := range selected {
+		ids = append(ids, matches[i].match.GetMatchId())
+	}
+	return ids
+}
+
+// requirePacking checks the selection doesn't collide, and returns its total
+// score.
+func requirePacking(t *testing.T, matches []*matchInp, selected []int) float64 {
+	used := map[string]bool{}
+	total := 0.0
+	for _, i := range selected {
+		for _, tk := range matches[i].match.GetTickets() {
+			require.False(t, used[tk.GetId()], "ticket %s selected twice", tk.GetId())
+			used[tk.GetId()] = true
+		}
+		total += matches[i].inp.GetScore()
+	}
+	return total
+}
+
+func greedyTotal(matches []*matchInp) float64 {
+	d := decollider{
+		ticketsUsed:   make(map[string]*collidingMatch),
+		backfillsUsed: make(map[string]*collidingMatch),
+	}
+	for _, m := range matches {
+		d.maybeAdd(m)
+	}
+	total := 0.0
+	for _, m := range matches {
+		for _, id := range d.resultIDs {
+			if m.match.GetMatchId() == id {
+				total += m.inp.GetScore()
+			}
+		}
+	}
+	return total
+}
+
+func TestEvaluateOptimal(t *testing.T) {
+	both := &pb.Match{
+		MatchId: "both",
+		Tickets: []*pb.Ticket{{Id: "1"}, {Id: "2"}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 10}),
+		},
+	}
+	first := &pb.Match{
+		MatchId: "first",
+		Tickets: []*pb.Ticket{{Id: "1"}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 6}),
+		},
+	}
+	second := &pb.Match{
+		MatchId: "second",
+		Tickets: []*pb.Ticket{{Id: "2"}},
+		Extensions: map[string]*anypb.Any{
+			"evaluation_input": mustAny(&pb.DefaultEvaluationCriteria{Score: 6}),
+		},
+	}
+
+	for name, test := range map[string]struct {
+		eval evaluator.ExplainingEvaluator
+		want []string
+	}{
+		selectionGreedy:  {eval: (&defaultEvaluator{now: time.Now}).evaluate, want: []string{"both"}},
+		selectionOptimal: {eval: (&defaultEvaluator{now: time.Now}).evaluateOptimal, want: []string{"first", "second"}},
+	} {
+		in := make(chan *pb.EvaluateRequest, 3)
+		out := make(chan string, 3)
+		in <- &pb.EvaluateRequest{Match: both}
+		in <- &pb.EvaluateRequest{Match: first}
+		in <- &pb.EvaluateRequest{Match: second}
+		close(in)
+
+		require.NoError(t, test.eval(context.Background(), in, out, make(chan *pb.MatchRejection, 3)), name)
+		close(out)
+		got := []string{}
+		for id := range out {
+			got = append(got, id)
+		}
+		require.ElementsMatch(t, test.want, got, name)
+	}
+}
+
+func TestSelectOptimalAcceptsAllNonColliding(t *testing.T) {
+	matches := sortedByScore(
+		newMatchInp("inf", math.Inf(1), "1", "2"),
+		newMatchInp("collides-inf", 100, "2"),
+		newMatchInp("positive", 5, "3"),
+		newMatchInp("zero", 0, "4"),
+		newMatchInp("negative", -5, "5"),
+		newMatchInp("collides-negative", -6, "5"),
+		newMatchInp("no-input", math.Inf(-1), "6"),
+	)
+
+	got := selectedIDs(matches, selectOptimal(matches))
+	require.Equal(t, []string{"inf", "positive", "zero", "negative", "no-input"}, got)
+}
+
+func TestSelectOptimalBackfills(t *testing.T) {
+	matches := sortedByScore(
+		newMatchInp("a", 10, "1"),
+		newMatchInp("b", 6, "2"),
+		newMatchInp("c", 6, "3"),
+	)
+	matches[0].match.Backfill = &pb.Backfill{Id: "bf"}
+	matches[1].match.Backfill = &pb.Backfill{Id: "bf"}
+
+	require.Equal(t, []string{"a", "c"}, selectedIDs(matches, selectOptimal(matches)))
+}
+
+// TestSelectOptimalExact compares the selection of small random groups of
+// matches against trying every subset.
+func TestSelectOptimalExact(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for run := 0; run < 200; run++ {
+		var matches []*matchInp
+		for i := 0; i < 1+r.Intn(14); i++ {
+			matches = append(matches, newMatchInp(fmt.Sprint(i), float64(1+r.Intn(20)), randomTickets(r, 3, 10)...))
+		}
+		matches = sortedByScore(matches...)
+
+		best := 0.0
+		for set := 0; set < 1<<len(matches); set++ {
+			used := map[string]bool{}
+			total := 0.0
+			ok := true
+			for i := range matches {
+				if set&(1<<i) == 0 {
+					continue
+				}
+				for _, tk := range matches[i].match.GetTickets() {
+					if used[tk.GetId()] {
+						ok = false
+					}
+					used[tk.GetId()] = true
+				}
+				total += matches[i].inp.GetScore()
+			}
+			if ok && total > best {
+				best = total
+			}
+		}
+
+		require.Equal(t, best, requirePacking(t, matches, selectOptimal(matches)), "run %d", run)
+	}
+}
+
+// TestSelectOptimalLocalSearch covers groups of colliding matches too large to
+// search exactly.
+func TestSelectOptimalLocalSearch(t *testing.T) {
+	// A match with every ticket, worth less than the matches of each ticket.
+	matches := []*matchInp{}
+	var all []string
+	for i := 0; i < 2*exactSelectionLimit; i++ {
+		id := fmt.Sprint(i)
+		all = append(all, id)
+		matches = append(matches, newMatchInp("single-"+id, 5, id))
+	}
+	matches = sortedByScore(append(matches, newMatchInp("all", 100, all...))...)
+
+	selected := selectOptimal(matches)
+	require.Equal(t, float64(5*2*exactSelectionLimit), requirePacking(t, matches, selected))
+
+	r := rand.New(rand.NewSource(1))
+	for run := 0; run < 20; run++ {
+		matches = nil
+		for i := 0; i < 300; i++ {
+			matches = append(matches, newMatchInp(fmt.Sprint(i), r.Float64()*10, randomTickets(r, 4, 100)...))
+		}
+		matches = sortedByScore(matches...)
+
+		total := requirePacking(t, matches, selectOptimal(matches))
+		require.GreaterOrEqual(t, total, greedyTotal(matches), "run %d", run)
+	}
+}
+
+func TestSelectOptimalByPriority(t *testing.T) {
+	match := func(id string, priority int32, score float64, backfill string, ticketIDs ...string) *matchInp {
+		m := newMatchInp(id, score, ticketIDs...)
+		m.priority = priority
+		if backfill != "" {
+			m.match.Backfill = &pb.Backfill{Id: backfill}
+		}
+		return m
+	}
+	matches := sortedByScore(
+		match("casual-1", 0, 10, "", "1"),
+		match("casual-3", 0, 10, "", "3"),
+		match("ranked", 1, 1, "", "1", "2"),
+		match("ranked-backfill", 1, 1, "bf", "4"),
+		match("casual-backfill", 0, 10, "bf", "5"),
+	)
+
+	// Higher priorities sort first, regardless of score.
+	require.ElementsMatch(t, []string{"ranked", "ranked-backfill"}, selectedIDs(matches, []int{0, 1}))
+
+	got := selectedIDs(matches, selectOptimalByPriority(matches))
+	require.ElementsMatch(t, []string{"ranked", "ranked-backfill", "casual-3"}, got)
+
+	// Without priorities, the casual matches are worth more.
+	for _, m := range matches {
+		m.priority = 0
+	}
+	matches = sortedByScore(matches...)
+	got = selectedIDs(matches, selectOptimalByPriority(matches))
+	require.ElementsMatch(t, []string{"casual-1", "casual-3", "casual-backfill"}, got)
+}
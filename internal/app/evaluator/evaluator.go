@@ -16,10 +16,14 @@
 package evaluator
 
 import (
+	"context"
+
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/appmain"
+	"open-match.dev/open-match/internal/rpc"
 	"open-match.dev/open-match/internal/telemetry"
 	"open-match.dev/open-match/pkg/pb"
 )
@@ -44,10 +48,48 @@ var (
 
 // BindServiceFor creates the evaluator service and binds it to the serving harness.
 func BindServiceFor(eval Evaluator) appmain.Bind {
+	return BindServiceForExplaining(func(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+		return evaluateMatches(ctx, eval, in, out)
+	})
+}
+
+// evaluateMatches runs an Evaluator, which only takes the matches of the
+// requests.
+func evaluateMatches(ctx context.Context, eval Evaluator, in <-chan *pb.EvaluateRequest, out chan<- string) error {
+	g, ctx := errgroup.WithContext(ctx)
+	matches := make(chan *pb.Match)
+
+	g.Go(func() error {
+		defer close(matches)
+		for req := range in {
+			select {
+			case matches <- req.GetMatch():
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+	g.Go(func() error {
+		// The evaluator may return without reading every match.
+		defer func() {
+			for range matches {
+			}
+		}()
+		return eval(ctx, matches, out)
+	})
+	return g.Wait()
+}
+
+// BindServiceForExplaining creates the evaluator service for an evaluator which
+// explains its rejections, and binds it to the serving harness.
+func BindServiceForExplaining(eval ExplainingEvaluator) appmain.Bind {
 	return func(p *appmain.Params, b *appmain.Bindings) error {
+		service := &evaluatorService{evaluate: eval}
 		b.AddHandleFunc(func(s *grpc.Server) {
-			pb.RegisterEvaluatorServer(s, &evaluatorService{evaluate: eval})
+			pb.RegisterEvaluatorServer(s, service)
 		}, pb.RegisterEvaluatorHandlerFromEndpoint)
+		b.AddWebSocketHandler("/v1/evaluator/matches:evaluate", rpc.NewWebSocketHandler(service, pb.Evaluator_ServiceDesc.Streams[0].Handler))
 		b.RegisterViews(
 			matchesPerEvaluateRequestView,
 			matchesPerEvaluateResponseView,
@@ -30,10 +30,17 @@ import (
 // and the Evaluator will return an accepted list of Matches.
 type Evaluator func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error
 
+// ExplainingEvaluator is an Evaluator which can also explain why it didn't
+// accept a match, by passing a MatchRejection to rejected.  Explanations are
+// optional, and are aggregated by the synchronizer per match profile.  Each
+// request carries a match along with the priority of the FetchMatches call
+// which proposed it, in the order the synchronizer sent them.
+type ExplainingEvaluator func(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error
+
 // evaluatorService implements pb.EvaluatorServer, the server generated by
 // compiling the protobuf, by fulfilling the pb.EvaluatorServer interface.
 type evaluatorService struct {
-	evaluate Evaluator
+	evaluate ExplainingEvaluator
 }
 
 // Evaluate is this harness's implementation of the gRPC call defined in
@@ -41,8 +48,9 @@ type evaluatorService struct {
 func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 	g, ctx := errgroup.WithContext(stream.Context())
 
-	in := make(chan *pb.Match)
+	in := make(chan *pb.EvaluateRequest)
 	out := make(chan string)
+	rejected := make(chan *pb.MatchRejection)
 
 	g.Go(func() error {
 		defer close(in)
@@ -56,7 +64,7 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 				return err
 			}
 			select {
-			case in <- req.Match:
+			case in <- req:
 				count++
 			case <-ctx.Done():
 				return ctx.Err()
@@ -67,21 +75,50 @@ func (s *evaluatorService) Evaluate(stream pb.Evaluator_EvaluateServer) error {
 	})
 	g.Go(func() error {
 		defer close(out)
-		return s.evaluate(ctx, in, out)
+		defer close(rejected)
+		return s.evaluate(ctx, in, out, rejected)
 	})
 	g.Go(func() error {
+		// Closed channels are set to nil, so they aren't selected again.
+		outc, rejectedc := out, rejected
+		// If sending fails, the evaluator may be blocked on either channel, so
+		// both are drained together until closed.
 		defer func() {
-			for range out {
+			for outc != nil || rejectedc != nil {
+				select {
+				case _, ok := <-outc:
+					if !ok {
+						outc = nil
+					}
+				case _, ok := <-rejectedc:
+					if !ok {
+						rejectedc = nil
+					}
+				}
 			}
 		}()
 
 		count := 0
-		for id := range out {
-			err := stream.Send(&pb.EvaluateResponse{MatchId: id})
-			if err != nil {
+		for outc != nil || rejectedc != nil {
+			resp := &pb.EvaluateResponse{}
+			select {
+			case id, ok := <-outc:
+				if !ok {
+					outc = nil
+					continue
+				}
+				resp.MatchId = id
+				count++
+			case r, ok := <-rejectedc:
+				if !ok {
+					rejectedc = nil
+					continue
+				}
+				resp.Rejection = r
+			}
+			if err := stream.Send(resp); err != nil {
 				return err
 			}
-			count++
 		}
 		stats.Record(ctx, matchesPerEvaluateResponse.M(int64(count)))
 		return nil
@@ -0,0 +1,132 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package evaluator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+type fakeEvaluateStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	reqs []*pb.EvaluateRequest
+	sent []*pb.EvaluateResponse
+	// sendErr is returned by Send if set.
+	sendErr error
+}
+
+func (s *fakeEvaluateStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeEvaluateStream) Recv() (*pb.EvaluateRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *fakeEvaluateStream) Send(resp *pb.EvaluateResponse) error {
+	if s.sendErr != nil {
+		return s.sendErr
+	}
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestEvaluatePriorityAndRejections(t *testing.T) {
+	stream := &fakeEvaluateStream{
+		ctx: context.Background(),
+		reqs: []*pb.EvaluateRequest{
+			{Match: &pb.Match{MatchId: "ranked"}, Priority: 2},
+			{Match: &pb.Match{MatchId: "casual"}},
+		},
+	}
+
+	priorities := map[string]int32{}
+	s := &evaluatorService{evaluate: func(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+		for req := range in {
+			id := req.GetMatch().GetMatchId()
+			priorities[id] = req.GetPriority()
+			if req.GetPriority() > 0 {
+				out <- id
+			} else {
+				rejected <- &pb.MatchRejection{MatchId: id, WinningMatchId: "ranked"}
+			}
+		}
+		return nil
+	}}
+
+	require.NoError(t, s.Evaluate(stream))
+	require.Equal(t, map[string]int32{"ranked": 2, "casual": 0}, priorities)
+	require.Len(t, stream.sent, 2)
+	require.Equal(t, "ranked", stream.sent[0].GetMatchId())
+	require.Nil(t, stream.sent[0].GetRejection())
+	require.Empty(t, stream.sent[1].GetMatchId())
+	require.Equal(t, "casual", stream.sent[1].GetRejection().GetMatchId())
+}
+
+func TestEvaluateSendError(t *testing.T) {
+	stream := &fakeEvaluateStream{
+		ctx:     context.Background(),
+		sendErr: errors.New("connection lost"),
+	}
+
+	// The evaluator ignores ctx, so it only returns once both channels were
+	// drained.
+	s := &evaluatorService{evaluate: func(ctx context.Context, in <-chan *pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+		out <- "a"
+		rejected <- &pb.MatchRejection{MatchId: "b"}
+		out <- "c"
+		rejected <- &pb.MatchRejection{MatchId: "d"}
+		return nil
+	}}
+
+	err := s.Evaluate(stream)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "connection lost")
+}
+
+func TestEvaluateMatches(t *testing.T) {
+	in := make(chan *pb.EvaluateRequest, 3)
+	for _, id := range []string{"a", "b", "c"} {
+		in <- &pb.EvaluateRequest{Match: &pb.Match{MatchId: id}, Priority: 1}
+	}
+	close(in)
+
+	// The evaluator only reads the first match, the others are drained.
+	out := make(chan string, 3)
+	err := evaluateMatches(context.Background(), func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		m := <-in
+		out <- m.GetMatchId()
+		return nil
+	}, in, out)
+	require.NoError(t, err)
+	close(out)
+	var got []string
+	for id := range out {
+		got = append(got, id)
+	}
+	require.Equal(t, []string{"a"}, got)
+}
@@ -72,6 +72,7 @@ func BindService(p *appmain.Params, b *appmain.Bindings) error {
 		searchFieldsPerTicketView,
 		totalBytesPerBackfillView,
 		searchFieldsPerBackfillView,
+		statestore.BackfillEventsView,
 	)
 	return nil
 }
@@ -110,6 +110,9 @@ func (s *frontendService) CreateBackfill(ctx context.Context, req *pb.CreateBack
 	if req.Backfill.CreateTime != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "backfills cannot be created with create time set")
 	}
+	if err := validateBackfillCapacity(req.Backfill.Capacity); err != nil {
+		return nil, err
+	}
 
 	return doCreateBackfill(ctx, req, s.store)
 }
@@ -145,8 +148,9 @@ func doCreateBackfill(ctx context.Context, req *pb.CreateBackfillRequest, store
 
 // UpdateBackfill updates a Backfill object, if present.
 // Update would increment generation in Redis.
-// Only Extensions and SearchFields would be updated.
+// Only Extensions, SearchFields, PersistentField and Capacity would be updated.
 // CreateTime is not changed on Update
+// If ExpectedGeneration is set, the Backfill is only updated if it still has this generation.
 func (s *frontendService) UpdateBackfill(ctx context.Context, req *pb.UpdateBackfillRequest) (*pb.Backfill, error) {
 	if req == nil {
 		return nil, status.Errorf(codes.InvalidArgument, "request is nil")
@@ -164,30 +168,27 @@ func (s *frontendService) UpdateBackfill(ctx context.Context, req *pb.UpdateBack
 	if bfID == "" {
 		return nil, status.Error(codes.InvalidArgument, "backfill ID should exist")
 	}
-	m := s.store.NewMutex(bfID)
-
-	err := m.Lock(ctx)
-	if err != nil {
+	if err := validateBackfillCapacity(backfill.Capacity); err != nil {
 		return nil, err
 	}
-	defer func() {
-		if _, err = m.Unlock(context.Background()); err != nil {
-			logger.WithError(err).Error("error on mutex unlock")
+
+	var associatedTickets []string
+	bfStored, err := s.store.ModifyBackfill(ctx, bfID, func(bfStored *pb.Backfill, ticketIDs []string) ([]string, error) {
+		if req.ExpectedGeneration != 0 && req.ExpectedGeneration != bfStored.Generation {
+			return nil, status.Errorf(codes.FailedPrecondition, "backfill %s has generation %d, expected %d", bfID, bfStored.Generation, req.ExpectedGeneration)
 		}
-	}()
-	bfStored, associatedTickets, err := s.store.GetBackfill(ctx, bfID)
-	if err != nil {
-		return nil, err
-	}
 
-	// Update generation here, because Frontend is used by GameServer only
-	bfStored.SearchFields = backfill.SearchFields
-	bfStored.Extensions = backfill.Extensions
-	bfStored.PersistentField = backfill.PersistentField
-	// Autoincrement generation, input backfill generation validation is performed
-	// on Backend only (after MMF round)
-	bfStored.Generation++
-	err = s.store.UpdateBackfill(ctx, bfStored, []string{})
+		// Update generation here, because Frontend is used by GameServer only
+		bfStored.SearchFields = backfill.SearchFields
+		bfStored.Extensions = backfill.Extensions
+		bfStored.PersistentField = backfill.PersistentField
+		bfStored.Capacity = backfill.Capacity
+		// Autoincrement generation, input backfill generation validation is performed
+		// on Backend only (after MMF round), unless an expected generation is requested
+		bfStored.Generation++
+		associatedTickets = ticketIDs
+		return []string{}, nil
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -208,6 +209,23 @@ func (s *frontendService) UpdateBackfill(ctx context.Context, req *pb.UpdateBack
 	return bfStored, nil
 }
 
+// validateBackfillCapacity checks that the open slots of the capacity are
+// within the maximum slots of the GameServer.
+func validateBackfillCapacity(c *pb.BackfillCapacity) error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxSlots < 0 || c.OpenSlots < 0 || c.OpenSlots > c.MaxSlots {
+		return status.Errorf(codes.InvalidArgument, ".capacity open slots %d must be between 0 and max slots %d", c.OpenSlots, c.MaxSlots)
+	}
+	for team, slots := range c.TeamOpenSlots {
+		if slots < 0 || slots > c.MaxSlots {
+			return status.Errorf(codes.InvalidArgument, ".capacity open slots %d of team %q must be between 0 and max slots %d", slots, team, c.MaxSlots)
+		}
+	}
+	return nil
+}
+
 // DeleteBackfill deletes a Backfill by its ID.
 func (s *frontendService) DeleteBackfill(ctx context.Context, req *pb.DeleteBackfillRequest) (*emptypb.Empty, error) {
 	bfID := req.GetBackfillId()
@@ -315,6 +333,29 @@ func doWatchAssignments(ctx context.Context, id string, sender func(*pb.Assignme
 	return store.GetAssignments(ctx, id, callback)
 }
 
+// AcceptAssignment accepts the tentative Assignment of the specified TicketId.
+func (s *frontendService) AcceptAssignment(ctx context.Context, req *pb.AcceptAssignmentRequest) (*emptypb.Empty, error) {
+	if req.GetTicketId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, ".TicketId is required")
+	}
+	if err := s.store.AcceptAssignment(ctx, req.GetTicketId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeclineAssignment declines the tentative Assignment of the specified TicketId,
+// deleting the Ticket and returning the other Tickets of its assignment group to the active pool.
+func (s *frontendService) DeclineAssignment(ctx context.Context, req *pb.DeclineAssignmentRequest) (*emptypb.Empty, error) {
+	if req.GetTicketId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, ".TicketId is required")
+	}
+	if err := s.store.DeclineAssignment(ctx, req.GetTicketId()); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
 // AcknowledgeBackfill is used to notify OpenMatch about GameServer connection info.
 // This triggers an assignment process.
 func (s *frontendService) AcknowledgeBackfill(ctx context.Context, req *pb.AcknowledgeBackfillRequest) (*pb.AcknowledgeBackfillResponse, error) {
@@ -325,24 +366,20 @@ func (s *frontendService) AcknowledgeBackfill(ctx context.Context, req *pb.Ackno
 		return nil, status.Errorf(codes.InvalidArgument, ".Assignment is required")
 	}
 
-	m := s.store.NewMutex(req.GetBackfillId())
+	return doAcknowledgeBackfill(ctx, req.GetBackfillId(), req.GetAssignment(), s.store)
+}
 
-	err := m.Lock(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if _, err = m.Unlock(context.Background()); err != nil {
-			logger.WithError(err).Error("error on mutex unlock")
-		}
-	}()
+// maxAssignBackfillAttempts bounds how many times the tickets of a backfill
+// are read again when it changes before they could be assigned.
+const maxAssignBackfillAttempts = 5
 
-	bf, associatedTickets, err := s.store.GetBackfill(ctx, req.GetBackfillId())
+func doAcknowledgeBackfill(ctx context.Context, id string, assignment *pb.Assignment, store statestore.Service) (*pb.AcknowledgeBackfillResponse, error) {
+	bf, associatedTickets, err := store.GetBackfill(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.store.UpdateAcknowledgmentTimestamp(ctx, req.GetBackfillId())
+	err = store.UpdateAcknowledgmentTimestamp(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -352,38 +389,209 @@ func (s *frontendService) AcknowledgeBackfill(ctx context.Context, req *pb.Ackno
 		Tickets:  make([]*pb.Ticket, 0),
 	}
 
-	if len(associatedTickets) != 0 {
-		setResp, tickets, err := s.store.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
-			Assignments: []*pb.AssignmentGroup{{TicketIds: associatedTickets, Assignment: req.GetAssignment()}},
-		})
+	// Compare and swap: the tickets are only assigned if the backfill still
+	// has the generation and tickets it was read with, otherwise it is read
+	// again, a bounded number of times.
+	for attempt := 0; len(associatedTickets) != 0; attempt++ {
+		if attempt == maxAssignBackfillAttempts {
+			return nil, status.Errorf(codes.Aborted, "backfill %s kept changing while its tickets were assigned", id)
+		}
+
+		// Assign the tickets, deindex them and remove them from the backfill in
+		// one atomic step, so that a ticket is never left deindexed without an
+		// assignment, nor assigned while still associated with the backfill.
+		setResp, tickets, err := store.AssignBackfillTickets(ctx, bf, associatedTickets, assignment)
+		if status.Code(err) == codes.Aborted {
+			// The backfill changed in the meantime, assign the tickets associated with it now
+			bf, associatedTickets, err = store.GetBackfill(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			resp.Backfill = bf
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
 
 		resp.Tickets = tickets
 
-		// log errors returned from UpdateAssignments to track tickets with NotFound errors
+		// log errors returned from AssignBackfillTickets to track tickets with NotFound errors
 		for _, f := range setResp.Failures {
 			logger.Errorf("failed to assign ticket %s, cause %d", f.TicketId, f.Cause)
 		}
-		for _, id := range associatedTickets {
-			err = s.store.DeindexTicket(ctx, id)
-			// Try to deindex all input tickets. Log without returning an error if the deindexing operation failed.
-			if err != nil {
-				logger.WithError(err).Errorf("failed to deindex ticket %s after updating the assignments", id)
-			}
+		break
+	}
+
+	return resp, nil
+}
+
+// WatchBackfill streams back the Backfill each time Tickets are matched to it or it is updated.
+// The Backfill is kept acknowledged while the stream is open, and matched Tickets are assigned
+// like in AcknowledgeBackfill.
+func (s *frontendService) WatchBackfill(req *pb.WatchBackfillRequest, stream pb.FrontendService_WatchBackfillServer) error {
+	if req.GetBackfillId() == "" {
+		return status.Errorf(codes.InvalidArgument, ".BackfillId is required")
+	}
+	if req.GetAssignment() == nil {
+		return status.Errorf(codes.InvalidArgument, ".Assignment is required")
+	}
+
+	ctx := stream.Context()
+	sender := func(resp *pb.WatchBackfillResponse) error {
+		return stream.Send(resp)
+	}
+	return doWatchBackfill(ctx, req.GetBackfillId(), req.GetAssignment(), sender, s.store)
+}
+
+func doWatchBackfill(ctx context.Context, id string, assignment *pb.Assignment, sender func(*pb.WatchBackfillResponse) error, store statestore.Service) error {
+	// The generation sent last, 0 before the first response.
+	var generation int64
+	callback := func(backfill *pb.Backfill, ticketIDs []string) error {
+		if ctx.Err() != nil {
+			return status.Errorf(codes.Aborted, ctx.Err().Error())
 		}
 
-		// Remove all tickets associated with backfill, because unassigned tickets are not found only
-		err = s.store.UpdateBackfill(ctx, bf, []string{})
+		if len(ticketIDs) == 0 && backfill.GetGeneration() == generation {
+			return store.UpdateAcknowledgmentTimestamp(ctx, id)
+		}
+
+		resp, err := doAcknowledgeBackfill(ctx, id, assignment, store)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		generation = resp.GetBackfill().GetGeneration()
+
+		err = sender(&pb.WatchBackfillResponse{Backfill: resp.GetBackfill(), Tickets: resp.GetTickets()})
+		if err != nil {
+			return status.Errorf(codes.Aborted, err.Error())
+		}
+		return nil
+	}
+
+	return store.WatchBackfill(ctx, id, callback)
+}
+
+// WatchBackfillEvents streams back the life-cycle events of Backfills, such as the
+// expiration and deletion of Backfills which were not acknowledged in time.
+func (s *frontendService) WatchBackfillEvents(req *pb.WatchBackfillEventsRequest, stream pb.FrontendService_WatchBackfillEventsServer) error {
+	ctx := stream.Context()
+	callback := func(event *pb.BackfillEvent) error {
+		if ctx.Err() != nil {
+			return status.Errorf(codes.Aborted, ctx.Err().Error())
+		}
+
+		err := stream.Send(&pb.WatchBackfillEventsResponse{Event: event})
+		if err != nil {
+			return status.Errorf(codes.Aborted, err.Error())
+		}
+		return nil
+	}
+	return s.store.WatchBackfillEvents(ctx, req.GetBackfillId(), callback)
+}
+
+// RemoveBackfillTickets reopens the slots of Tickets assigned to a Backfill whose players
+// never joined the GameServer, and optionally returns the Tickets to the pool.
+func (s *frontendService) RemoveBackfillTickets(ctx context.Context, req *pb.RemoveBackfillTicketsRequest) (*pb.RemoveBackfillTicketsResponse, error) {
+	if req.GetBackfillId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, ".BackfillId is required")
+	}
+	if len(req.GetTicketIds()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, ".TicketIds is required")
+	}
+
+	return doRemoveBackfillTickets(ctx, req, s.store)
+}
+
+func doRemoveBackfillTickets(ctx context.Context, req *pb.RemoveBackfillTicketsRequest, store statestore.Service) (*pb.RemoveBackfillTicketsResponse, error) {
+	// Only the Tickets assigned to the Backfill and not removed yet reopen slots
+	// or are requeued, so unknown and repeated Tickets are ignored.
+	bf, removed, err := store.RemoveBackfillTickets(ctx, req.GetBackfillId(), req.GetTicketIds(), func(bf *pb.Backfill, removed []string) error {
+		var err error
+		bf.Capacity, err = reopenCapacity(bf.Capacity, removed, req.GetTicketTeams())
+		if err != nil {
+			return err
+		}
+		// Matches proposed against the previous capacity must be rejected by the Backend
+		bf.Generation++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.RemoveBackfillTicketsResponse{
+		Backfill:          bf,
+		RequeuedTicketIds: make([]string, 0),
+	}
+	if len(removed) == 0 {
+		return resp, nil
+	}
+
+	err = store.IndexBackfill(ctx, bf)
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error": err.Error(),
+			"id":    bf.Id,
+		}).Error("failed to index the backfill")
+		return nil, err
+	}
+
+	if !req.GetRequeue() {
+		return resp, nil
+	}
+
+	revokeResp, err := store.RevokeAssignments(ctx, removed)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(map[string]struct{}, len(revokeResp.GetFailures()))
+	for _, f := range revokeResp.GetFailures() {
+		failed[f.TicketId] = struct{}{}
+		logger.Errorf("failed to requeue ticket %s, cause %d", f.TicketId, f.Cause)
+	}
+	for _, ticketID := range removed {
+		if _, ok := failed[ticketID]; !ok {
+			resp.RequeuedTicketIds = append(resp.RequeuedTicketIds, ticketID)
 		}
 	}
 
 	return resp, nil
 }
 
+// reopenCapacity gives the slots of the tickets back to the capacity, up to its
+// maximum slots.  The input capacity is not modified.  A nil capacity is not
+// tracked, and is returned as is.
+func reopenCapacity(capacity *pb.BackfillCapacity, ticketIDs []string, ticketTeams map[string]string) (*pb.BackfillCapacity, error) {
+	if capacity == nil {
+		return nil, nil
+	}
+
+	reopened, ok := proto.Clone(capacity).(*pb.BackfillCapacity)
+	if !ok {
+		return nil, status.Error(codes.Internal, "failed to clone backfill capacity proto")
+	}
+
+	reopened.OpenSlots = min(reopened.OpenSlots+int32(len(ticketIDs)), reopened.MaxSlots)
+
+	if len(reopened.TeamOpenSlots) == 0 {
+		return reopened, nil
+	}
+	for _, ticketID := range ticketIDs {
+		team, ok := ticketTeams[ticketID]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, ".TicketTeams is missing the team of ticket %s", ticketID)
+		}
+		slots, ok := reopened.TeamOpenSlots[team]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown team %q of ticket %s", team, ticketID)
+		}
+		reopened.TeamOpenSlots[team] = min(slots+1, reopened.MaxSlots)
+	}
+	return reopened, nil
+}
+
 // GetBackfill fetches a Backfill object by its ID.
 func (s *frontendService) GetBackfill(ctx context.Context, req *pb.GetBackfillRequest) (*pb.Backfill, error) {
 	bf, _, err := s.store.GetBackfill(ctx, req.GetBackfillId())
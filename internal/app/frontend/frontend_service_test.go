@@ -26,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"open-match.dev/open-match/internal/statestore"
 	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
@@ -228,6 +229,36 @@ func TestUpdateBackfill(t *testing.T) {
 			expectedCode:    codes.OK,
 			expectedMessage: "",
 		},
+		{
+			description: "backfill with more open slots than max slots",
+			request: &pb.UpdateBackfillRequest{
+				Backfill: &pb.Backfill{
+					Id:       res.Id,
+					Capacity: &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 5},
+				}},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: ".capacity open slots 5 must be between 0 and max slots 4",
+		},
+		{
+			description: "backfill with negative team open slots",
+			request: &pb.UpdateBackfillRequest{
+				Backfill: &pb.Backfill{
+					Id:       res.Id,
+					Capacity: &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 2, TeamOpenSlots: map[string]int32{"red": -1}},
+				}},
+			expectedCode:    codes.InvalidArgument,
+			expectedMessage: `.capacity open slots -1 of team "red" must be between 0 and max slots 4`,
+		},
+		{
+			description: "backfill with capacity",
+			request: &pb.UpdateBackfillRequest{
+				Backfill: &pb.Backfill{
+					Id:       res.Id,
+					Capacity: &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 2},
+				}},
+			expectedCode:    codes.OK,
+			expectedMessage: "",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -237,7 +268,8 @@ func TestUpdateBackfill(t *testing.T) {
 			if tc.expectedCode == codes.OK {
 				require.NoError(t, err)
 				require.NotNil(t, res)
-				require.Equal(t, tc.request.Backfill.SearchFields.DoubleArgs, res.SearchFields.DoubleArgs)
+				require.Equal(t, tc.request.Backfill.SearchFields.GetDoubleArgs(), res.SearchFields.GetDoubleArgs())
+				require.True(t, proto.Equal(tc.request.Backfill.Capacity, res.Capacity))
 			} else {
 				require.Error(t, err)
 				require.Equal(t, tc.expectedCode.String(), status.Convert(err).Code().String())
@@ -246,7 +278,28 @@ func TestUpdateBackfill(t *testing.T) {
 		})
 	}
 
-	// expect error with canceled context
+	// expect an update conditioned on a stale generation to fail
+	stored, err := fs.GetBackfill(ctx, &pb.GetBackfillRequest{BackfillId: res.Id})
+	require.NoError(t, err)
+	_, err = fs.UpdateBackfill(ctx, &pb.UpdateBackfillRequest{
+		Backfill:           &pb.Backfill{Id: res.Id},
+		ExpectedGeneration: stored.Generation - 1,
+	})
+	require.Equal(t, codes.FailedPrecondition.String(), status.Convert(err).Code().String())
+	unchanged, err := fs.GetBackfill(ctx, &pb.GetBackfillRequest{BackfillId: res.Id})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(stored, unchanged))
+
+	res, err = fs.UpdateBackfill(ctx, &pb.UpdateBackfillRequest{
+		Backfill:           &pb.Backfill{Id: res.Id},
+		ExpectedGeneration: stored.Generation,
+	})
+	require.NoError(t, err)
+	require.Equal(t, stored.Generation+1, res.Generation)
+
+	// expect error with canceled context. Like every other statestore call, the
+	// update fails to get a redis connection and returns Unavailable; Unknown
+	// only came from the backfill mutex, which updates no longer take.
 	store, closer = statestoreTesting.NewStoreServiceForTesting(t, cfg)
 	fs = frontendService{cfg: cfg, store: store}
 	defer closer()
@@ -262,10 +315,37 @@ func TestUpdateBackfill(t *testing.T) {
 		},
 	}})
 	require.NotNil(t, err)
-	require.Equal(t, codes.Unknown.String(), status.Convert(err).Code().String())
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
 	require.Nil(t, res)
 }
 
+func TestUpdateBackfillConcurrently(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := utilTesting.NewContext(t)
+	fs := frontendService{cfg: cfg, store: store}
+	bf, err := fs.CreateBackfill(ctx, &pb.CreateBackfillRequest{Backfill: &pb.Backfill{}})
+	require.NoError(t, err)
+
+	// concurrent updates are all applied, none of them is overwritten
+	const updates = 10
+	var wg sync.WaitGroup
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := fs.UpdateBackfill(ctx, &pb.UpdateBackfillRequest{Backfill: &pb.Backfill{Id: bf.Id}})
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	stored, err := fs.GetBackfill(ctx, &pb.GetBackfillRequest{BackfillId: bf.Id})
+	require.NoError(t, err)
+	require.Equal(t, bf.Generation+updates, stored.Generation)
+}
+
 func TestDoWatchAssignments(t *testing.T) {
 	testTicket := &pb.Ticket{
 		Id: "test-id",
@@ -317,6 +397,34 @@ func TestDoWatchAssignments(t *testing.T) {
 			wantCode:        codes.Aborted,
 			wantAssignments: []*pb.Assignment{{Connection: "1"}, {Connection: "2"}},
 		},
+		{
+			description: "expect an empty assignment read after the assignment is revoked",
+			preAction: func(ctx context.Context, t *testing.T, store statestore.Service, wantAssignments []*pb.Assignment, wg *sync.WaitGroup) {
+				require.Nil(t, store.CreateTicket(ctx, testTicket))
+
+				go func(wg *sync.WaitGroup) {
+					time.Sleep(50 * time.Millisecond)
+					_, _, err := store.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+						Assignments: []*pb.AssignmentGroup{
+							{
+								TicketIds:  []string{testTicket.GetId()},
+								Assignment: wantAssignments[0],
+							},
+						},
+					})
+					require.NoError(t, err)
+					wg.Done()
+
+					time.Sleep(50 * time.Millisecond)
+					resp, err := store.RevokeAssignments(ctx, []string{testTicket.GetId()})
+					require.NoError(t, err)
+					require.Empty(t, resp.GetFailures())
+					wg.Done()
+				}(wg)
+			},
+			wantCode:        codes.Aborted,
+			wantAssignments: []*pb.Assignment{{Connection: "1"}, nil},
+		},
 	}
 
 	for _, test := range tests {
@@ -343,6 +451,44 @@ func TestDoWatchAssignments(t *testing.T) {
 	}
 }
 
+func TestAcceptDeclineAssignment(t *testing.T) {
+	cfg := viper.New()
+	ctx := context.Background()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	fs := frontendService{cfg: cfg, store: store}
+
+	_, err := fs.AcceptAssignment(ctx, &pb.AcceptAssignmentRequest{})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+	_, err = fs.DeclineAssignment(ctx, &pb.DeclineAssignmentRequest{})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+
+	for _, id := range []string{"1", "2"} {
+		ticket := &pb.Ticket{Id: id}
+		require.NoError(t, store.CreateTicket(ctx, ticket))
+		require.NoError(t, store.IndexTicket(ctx, ticket))
+	}
+	_, err = fs.AcceptAssignment(ctx, &pb.AcceptAssignmentRequest{TicketId: "1"})
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	_, _, err = store.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"1", "2"}, Assignment: &pb.Assignment{Connection: "10.0.0.1"}}},
+		Tentative:   true,
+	})
+	require.NoError(t, err)
+
+	_, err = fs.AcceptAssignment(ctx, &pb.AcceptAssignmentRequest{TicketId: "1"})
+	require.NoError(t, err)
+	_, err = fs.DeclineAssignment(ctx, &pb.DeclineAssignmentRequest{TicketId: "2"})
+	require.NoError(t, err)
+
+	ticket, err := store.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.Nil(t, ticket.GetAssignment())
+	_, err = store.GetTicket(ctx, "2")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+}
+
 // TestAcknowledgeBackfillValidation - test input validation only
 func TestAcknowledgeBackfillValidation(t *testing.T) {
 	cfg := viper.New()
@@ -426,6 +572,170 @@ func TestAcknowledgeBackfill(t *testing.T) {
 
 }
 
+func TestDoWatchBackfill(t *testing.T) {
+	cfg := viper.New()
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	fs := frontendService{cfg: cfg, store: store}
+	assignment := &pb.Assignment{Connection: "10.0.0.1"}
+
+	err := fs.WatchBackfill(&pb.WatchBackfillRequest{Assignment: assignment}, nil)
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+	err = fs.WatchBackfill(&pb.WatchBackfillRequest{BackfillId: "1"}, nil)
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+
+	err = doWatchBackfill(ctx, "missing", assignment, func(*pb.WatchBackfillResponse) error { return nil }, store)
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	backfill := &pb.Backfill{Id: "1", Generation: 1}
+	require.NoError(t, store.CreateBackfill(ctx, backfill, []string{}))
+	ticket := &pb.Ticket{Id: "ticket"}
+	require.NoError(t, store.CreateTicket(ctx, ticket))
+	require.NoError(t, store.IndexTicket(ctx, ticket))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		time.Sleep(50 * time.Millisecond)
+		// Match the ticket to the backfill, like the backend does.
+		require.NoError(t, store.UpdateBackfill(ctx, &pb.Backfill{Id: "1", Generation: 2}, []string{ticket.Id}))
+	}()
+
+	var got []*pb.WatchBackfillResponse
+	sender := func(resp *pb.WatchBackfillResponse) error {
+		got = append(got, resp)
+		if len(got) == 2 {
+			return errors.New("some error")
+		}
+		return nil
+	}
+	err = doWatchBackfill(ctx, backfill.Id, assignment, sender, store)
+	require.Equal(t, codes.Aborted.String(), status.Convert(err).Code().String())
+	wg.Wait()
+
+	require.Len(t, got, 2)
+	require.Equal(t, int64(1), got[0].GetBackfill().GetGeneration())
+	require.Empty(t, got[0].GetTickets())
+	require.Equal(t, int64(2), got[1].GetBackfill().GetGeneration())
+	require.Len(t, got[1].GetTickets(), 1)
+	require.Equal(t, ticket.Id, got[1].GetTickets()[0].GetId())
+	require.True(t, proto.Equal(assignment, got[1].GetTickets()[0].GetAssignment()))
+
+	_, ids, err := store.GetBackfill(ctx, backfill.Id)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestRemoveBackfillTickets(t *testing.T) {
+	cfg := viper.New()
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	fs := frontendService{cfg: cfg, store: store}
+
+	_, err := fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{TicketIds: []string{"t1"}})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+	_, err = fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{BackfillId: "1"})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+	_, err = fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{BackfillId: "missing", TicketIds: []string{"t1"}})
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	backfill := &pb.Backfill{
+		Id:         "1",
+		Generation: 1,
+		Capacity: &pb.BackfillCapacity{
+			MaxSlots:      4,
+			OpenSlots:     1,
+			TeamOpenSlots: map[string]int32{"red": 0, "blue": 1},
+		},
+	}
+	require.NoError(t, store.CreateBackfill(ctx, backfill, []string{"t1", "t2"}))
+	for _, id := range []string{"t1", "t2", "t3"} {
+		require.NoError(t, store.CreateTicket(ctx, &pb.Ticket{Id: id}))
+	}
+	_, _, err = store.AssignBackfillTickets(ctx, backfill, []string{"t1", "t2"}, &pb.Assignment{Connection: "10.0.0.1"})
+	require.NoError(t, err)
+	// t3 is matched to the backfill, but not acknowledged yet
+	require.NoError(t, store.UpdateBackfill(ctx, backfill, []string{"t3"}))
+
+	// the team of the tickets is required to reopen the team slots
+	_, err = fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{BackfillId: "1", TicketIds: []string{"t1"}})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+	_, err = fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{
+		BackfillId:  "1",
+		TicketIds:   []string{"t1"},
+		TicketTeams: map[string]string{"t1": "green"},
+	})
+	require.Equal(t, codes.InvalidArgument.String(), status.Convert(err).Code().String())
+
+	resp, err := fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{
+		BackfillId:  "1",
+		TicketIds:   []string{"t1", "t2", "missing"},
+		TicketTeams: map[string]string{"t1": "red", "t2": "blue", "missing": "blue"},
+		Requeue:     true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.GetBackfill().GetGeneration())
+	// only the tickets assigned to the backfill reopen their slots
+	require.Equal(t, int32(3), resp.GetBackfill().GetCapacity().GetOpenSlots())
+	require.Equal(t, map[string]int32{"red": 1, "blue": 2}, resp.GetBackfill().GetCapacity().GetTeamOpenSlots())
+	require.ElementsMatch(t, []string{"t1", "t2"}, resp.GetRequeuedTicketIds())
+
+	stored, ids, err := store.GetBackfill(ctx, "1")
+	require.NoError(t, err)
+	require.True(t, proto.Equal(resp.GetBackfill(), stored))
+	require.Equal(t, []string{"t3"}, ids)
+
+	indexed, err := store.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	for _, id := range []string{"t1", "t2"} {
+		ticket, err := store.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.Nil(t, ticket.GetAssignment())
+		require.Contains(t, indexed, id)
+	}
+
+	// removed tickets and tickets only matched to the backfill are ignored
+	resp, err = fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{
+		BackfillId:  "1",
+		TicketIds:   []string{"t1", "t3"},
+		TicketTeams: map[string]string{"t1": "red", "t3": "red"},
+		Requeue:     true,
+	})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(stored, resp.GetBackfill()))
+	require.Empty(t, resp.GetRequeuedTicketIds())
+}
+
+func TestRemoveBackfillTicketsWithoutRequeue(t *testing.T) {
+	cfg := viper.New()
+	ctx := utilTesting.NewContext(t)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	fs := frontendService{cfg: cfg, store: store}
+
+	backfill := &pb.Backfill{Id: "1", Generation: 1, Capacity: &pb.BackfillCapacity{MaxSlots: 2}}
+	require.NoError(t, store.CreateBackfill(ctx, backfill, []string{"t1"}))
+	require.NoError(t, store.CreateTicket(ctx, &pb.Ticket{Id: "t1"}))
+	_, _, err := store.AssignBackfillTickets(ctx, backfill, []string{"t1"}, &pb.Assignment{Connection: "10.0.0.1"})
+	require.NoError(t, err)
+
+	// without requeue, the tickets are left untouched
+	resp, err := fs.RemoveBackfillTickets(ctx, &pb.RemoveBackfillTicketsRequest{
+		BackfillId: "1",
+		TicketIds:  []string{"t1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.GetBackfill().GetGeneration())
+	require.Equal(t, int32(1), resp.GetBackfill().GetCapacity().GetOpenSlots())
+	require.Empty(t, resp.GetRequeuedTicketIds())
+	ticket, err := store.GetTicket(ctx, "t1")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ticket.GetAssignment().GetConnection())
+}
+
 func TestDoDeleteTicket(t *testing.T) {
 	fakeTicket := &pb.Ticket{
 		Id: "1",
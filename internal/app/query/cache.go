@@ -251,3 +251,48 @@ func updateBackfillCache(store statestore.Service, value interface{}) error {
 	logger.Debugf("Backfill Cache update: Previous %d, Deleted %d, Fetched %d, Current %d", previousCount, deletedCount, len(toFetch), len(backfills))
 	return nil
 }
+
+func newReservationCache(b *appmain.Bindings, store statestore.Service) *cache {
+	c := &cache{
+		store:           store,
+		requests:        make(chan *cacheRequest),
+		startRunRequest: make(chan struct{}, 1),
+		value:           make(map[string]string),
+		update:          updateReservationCache,
+	}
+
+	c.startRunRequest <- struct{}{}
+	b.AddHealthCheckFunc(c.store.HealthCheck)
+
+	return c
+}
+
+func updateReservationCache(store statestore.Service, value interface{}) error {
+	if value == nil {
+		return status.Error(codes.InvalidArgument, "value is required")
+	}
+
+	reservations, ok := value.(map[string]string)
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "expecting value type map[string]string, but got: %T", value)
+	}
+
+	current, err := store.GetTicketReservations(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for id := range reservations {
+		if _, ok := current[id]; !ok {
+			delete(reservations, id)
+		}
+	}
+	for id, profile := range current {
+		reservations[id] = profile
+	}
+
+	stats.Record(context.Background(), totalReservedTickets.M(int64(len(reservations))))
+
+	logger.Debugf("Reservation Cache update: Current %d", len(reservations))
+	return nil
+}
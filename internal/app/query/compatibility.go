@@ -0,0 +1,204 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// compatibility finds the Backfills compatible with a Ticket, as specified by
+// a pb.BackfillCompatibility.
+type compatibility struct {
+	stringArgs []string
+	doubleArgs []*pb.DoubleArgDistance
+	// buckets groups the candidate Backfills by the values of their stringArgs,
+	// so that only Backfills with the same values as a Ticket are ranked. If
+	// there are doubleArgs, each bucket is sorted by the first of them, so that
+	// only the Backfills within its max_distance of a Ticket are ranked.
+	buckets map[string][]*pb.Backfill
+}
+
+// defaultMaxBackfills is the number of compatible Backfills returned per
+// Ticket when the request doesn't set max_backfills.
+const defaultMaxBackfills = 100
+
+// newCompatibility validates the compatibility spec and groups the candidate
+// Backfills by it. Backfills missing a stringArg or the first doubleArg, or
+// with a NaN value of it, are compatible with no Ticket and left out.
+func newCompatibility(spec *pb.BackfillCompatibility, backfills []*pb.Backfill) (*compatibility, error) {
+	for _, d := range spec.GetDoubleArgs() {
+		if d.GetDoubleArg() == "" {
+			return nil, status.Error(codes.InvalidArgument, ".compatibility.double_args.double_arg is required")
+		}
+		if !(d.GetMaxDistance() >= 0) {
+			return nil, status.Errorf(codes.InvalidArgument, ".compatibility.double_args.max_distance of %s must not be negative, got %v", d.GetDoubleArg(), d.GetMaxDistance())
+		}
+	}
+
+	c := &compatibility{
+		stringArgs: spec.GetStringArgs(),
+		doubleArgs: spec.GetDoubleArgs(),
+		buckets:    make(map[string][]*pb.Backfill),
+	}
+	for _, b := range backfills {
+		key, ok := c.bucketKey(b.GetSearchFields())
+		if !ok {
+			continue
+		}
+		if len(c.doubleArgs) > 0 {
+			if v, ok := c.firstDoubleArg(b.GetSearchFields()); !ok || math.IsNaN(v) {
+				continue
+			}
+		}
+		c.buckets[key] = append(c.buckets[key], b)
+	}
+
+	if len(c.doubleArgs) > 0 {
+		for _, bucket := range c.buckets {
+			sort.Slice(bucket, func(i, j int) bool {
+				a, _ := c.firstDoubleArg(bucket[i].GetSearchFields())
+				b, _ := c.firstDoubleArg(bucket[j].GetSearchFields())
+				return a < b
+			})
+		}
+	}
+	return c, nil
+}
+
+// firstDoubleArg returns the value of the first doubleArg of the search
+// fields, or false if it is missing.
+func (c *compatibility) firstDoubleArg(s *pb.SearchFields) (float64, bool) {
+	v, ok := s.GetDoubleArgs()[c.doubleArgs[0].GetDoubleArg()]
+	return v, ok
+}
+
+// window returns the Backfills of the bucket whose first doubleArg is within
+// its max_distance of the Ticket's, or the whole bucket if there are no
+// doubleArgs.
+func (c *compatibility) window(bucket []*pb.Backfill, ticket *pb.SearchFields) []*pb.Backfill {
+	if len(c.doubleArgs) == 0 {
+		return bucket
+	}
+	t, ok := c.firstDoubleArg(ticket)
+	if !ok {
+		return nil
+	}
+
+	// A NaN value of the Ticket selects nothing, as no comparison holds.
+	maxDistance := c.doubleArgs[0].GetMaxDistance()
+	value := func(i int) float64 {
+		v, _ := c.firstDoubleArg(bucket[i].GetSearchFields())
+		return v
+	}
+	start := sort.Search(len(bucket), func(i int) bool { return value(i) >= t-maxDistance })
+	end := sort.Search(len(bucket), func(i int) bool { return value(i) > t+maxDistance })
+	if end < start {
+		return nil
+	}
+	return bucket[start:end]
+}
+
+// bucketKey returns the values of the stringArgs of the search fields,
+// or false if any of them is missing.
+func (c *compatibility) bucketKey(s *pb.SearchFields) (string, bool) {
+	values := make([]string, len(c.stringArgs))
+	for i, arg := range c.stringArgs {
+		v, ok := s.GetStringArgs()[arg]
+		if !ok {
+			return "", false
+		}
+		values[i] = v
+	}
+	// Search field values are UTF-8 strings, so they can't contain the separator.
+	return strings.Join(values, "\xff"), true
+}
+
+// distance returns the sum of the distances between the double_args of the
+// Ticket and the Backfill, each divided by its max_distance, or false if the
+// Backfill is not compatible with the Ticket.
+func (c *compatibility) distance(ticket, backfill *pb.SearchFields) (float64, bool) {
+	var sum float64
+	for _, d := range c.doubleArgs {
+		t, ok := ticket.GetDoubleArgs()[d.GetDoubleArg()]
+		if !ok {
+			return 0, false
+		}
+		b, ok := backfill.GetDoubleArgs()[d.GetDoubleArg()]
+		if !ok {
+			return 0, false
+		}
+
+		dist := math.Abs(t - b)
+		// Not simplified so that NaN cases are handled correctly.
+		if !(dist <= d.GetMaxDistance()) {
+			return 0, false
+		}
+		if d.GetMaxDistance() > 0 {
+			sum += dist / d.GetMaxDistance()
+		}
+	}
+	return sum, true
+}
+
+// rank returns the Backfills compatible with the Ticket, closest first and
+// oldest first among equally close ones, keeping at most limit of them.
+//
+// Only the Backfills of the Ticket's bucket within the max_distance of its
+// first doubleArg are checked. Without stringArgs or doubleArgs all of them
+// are, so ranking every Ticket costs O(tickets × backfills).
+func (c *compatibility) rank(ticket *pb.Ticket, limit int) []*pb.Backfill {
+	key, ok := c.bucketKey(ticket.GetSearchFields())
+	if !ok {
+		return nil
+	}
+
+	type candidate struct {
+		backfill *pb.Backfill
+		distance float64
+	}
+	var candidates []candidate
+	for _, b := range c.window(c.buckets[key], ticket.GetSearchFields()) {
+		if d, ok := c.distance(ticket.GetSearchFields(), b.GetSearchFields()); ok {
+			candidates = append(candidates, candidate{b, d})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		at, bt := a.backfill.GetCreateTime().AsTime(), b.backfill.GetCreateTime().AsTime()
+		if !at.Equal(bt) {
+			return at.Before(bt)
+		}
+		return a.backfill.GetId() < b.backfill.GetId()
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	backfills := make([]*pb.Backfill, len(candidates))
+	for i, cand := range candidates {
+		backfills[i] = cand.backfill
+	}
+	return backfills
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestCompatibilityRank(t *testing.T) {
+	now := time.Now()
+	newBackfill := func(id, mode string, skill float64, age time.Duration) *pb.Backfill {
+		return &pb.Backfill{
+			Id: id,
+			SearchFields: &pb.SearchFields{
+				StringArgs: map[string]string{"mode": mode},
+				DoubleArgs: map[string]float64{"skill": skill},
+			},
+			CreateTime: timestamppb.New(now.Add(-age)),
+		}
+	}
+	backfills := []*pb.Backfill{
+		newBackfill("far", "ctf", 18, 0),
+		newBackfill("tooFar", "ctf", 21, 0),
+		newBackfill("closeNew", "ctf", 12, 0),
+		newBackfill("closeOld", "ctf", 8, time.Minute),
+		newBackfill("exact", "ctf", 10, 0),
+		newBackfill("otherMode", "dm", 10, 0),
+		newBackfill("nan", "ctf", math.NaN(), 0),
+		{Id: "noFields", CreateTime: timestamppb.New(now)},
+	}
+	spec := &pb.BackfillCompatibility{
+		StringArgs: []string{"mode"},
+		DoubleArgs: []*pb.DoubleArgDistance{{DoubleArg: "skill", MaxDistance: 10}},
+	}
+	ticket := &pb.Ticket{
+		Id: "t",
+		SearchFields: &pb.SearchFields{
+			StringArgs: map[string]string{"mode": "ctf"},
+			DoubleArgs: map[string]float64{"skill": 10},
+		},
+	}
+
+	ids := func(backfills []*pb.Backfill) []string {
+		var ids []string
+		for _, b := range backfills {
+			ids = append(ids, b.GetId())
+		}
+		return ids
+	}
+
+	c, err := newCompatibility(spec, backfills)
+	require.NoError(t, err)
+	require.Equal(t, []string{"exact", "closeOld", "closeNew", "far"}, ids(c.rank(ticket, defaultMaxBackfills)))
+	require.Equal(t, []string{"exact", "closeOld"}, ids(c.rank(ticket, 2)))
+
+	// Tickets missing a field of the spec are compatible with no Backfill.
+	require.Empty(t, c.rank(&pb.Ticket{Id: "noFields"}, defaultMaxBackfills))
+	require.Empty(t, c.rank(&pb.Ticket{Id: "noSkill", SearchFields: &pb.SearchFields{
+		StringArgs: map[string]string{"mode": "ctf"},
+	}}, defaultMaxBackfills))
+	require.Empty(t, c.rank(&pb.Ticket{Id: "nanSkill", SearchFields: &pb.SearchFields{
+		StringArgs: map[string]string{"mode": "ctf"},
+		DoubleArgs: map[string]float64{"skill": math.NaN()},
+	}}, defaultMaxBackfills))
+
+	// Without a spec, every Backfill is compatible, oldest first.
+	c, err = newCompatibility(nil, backfills)
+	require.NoError(t, err)
+	require.Len(t, c.rank(ticket, defaultMaxBackfills), len(backfills))
+	require.Equal(t, "closeOld", c.rank(ticket, 1)[0].GetId())
+
+	// A zero max distance requires equal values.
+	c, err = newCompatibility(&pb.BackfillCompatibility{
+		DoubleArgs: []*pb.DoubleArgDistance{{DoubleArg: "skill"}},
+	}, backfills)
+	require.NoError(t, err)
+	require.Equal(t, []string{"exact", "otherMode"}, ids(c.rank(ticket, defaultMaxBackfills)))
+}
+
+func TestNewCompatibilityInvalid(t *testing.T) {
+	for _, d := range []*pb.DoubleArgDistance{
+		{MaxDistance: 1},
+		{DoubleArg: "skill", MaxDistance: -1},
+		{DoubleArg: "skill", MaxDistance: math.NaN()},
+	} {
+		_, err := newCompatibility(&pb.BackfillCompatibility{DoubleArgs: []*pb.DoubleArgDistance{d}}, nil)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), d.String())
+	}
+}
@@ -15,9 +15,14 @@
 package query
 
 import (
+	"context"
+	"net"
+
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 	"open-match.dev/open-match/internal/appmain"
 	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/internal/telemetry"
@@ -33,6 +38,8 @@ var (
 	cacheTotalItems       = stats.Int64("open-match.dev/query/total_cache_items", "Total number of items query service cached", stats.UnitDimensionless)
 	cacheFetchedItems     = stats.Int64("open-match.dev/query/fetched_items", "Number of fetched items in total", stats.UnitDimensionless)
 	cacheWaitingQueries   = stats.Int64("open-match.dev/query/waiting_queries", "Number of waiting queries in the last update", stats.UnitDimensionless)
+	totalReservedTickets  = stats.Int64("open-match.dev/query/total_reserved_tickets", "Number of tickets reserved for a profile", stats.UnitDimensionless)
+	ticketsReserved       = stats.Int64("open-match.dev/query/tickets_reserved", "Number of tickets reserved per request", stats.UnitDimensionless)
 	cacheUpdateLatency    = stats.Float64("open-match.dev/query/update_latency", "Time elapsed of each query cache update", stats.UnitMilliseconds)
 
 	ticketsPerQueryView = &view.View{
@@ -89,6 +96,18 @@ var (
 		Description: "Number of waiting requests in total",
 		Aggregation: telemetry.DefaultCountDistribution,
 	}
+	reservedTotalTicketsView = &view.View{
+		Measure:     totalReservedTickets,
+		Name:        "open-match.dev/query/total_reserved_tickets",
+		Description: "Total number of tickets reserved for a profile",
+		Aggregation: view.LastValue(),
+	}
+	ticketsReservedView = &view.View{
+		Measure:     ticketsReserved,
+		Name:        "open-match.dev/query/tickets_reserved",
+		Description: "Number of tickets reserved per request",
+		Aggregation: view.Sum(),
+	}
 	cacheUpdateLatencyView = &view.View{
 		Measure:     cacheUpdateLatency,
 		Name:        "open-match.dev/query/update_latency",
@@ -99,12 +118,7 @@ var (
 
 // BindService creates the query service and binds it to the serving harness.
 func BindService(p *appmain.Params, b *appmain.Bindings) error {
-	store := statestore.New(p.Config())
-	service := &queryService{
-		cfg: p.Config(),
-		tc:  newTicketCache(b, store),
-		bc:  newBackfillCache(b, store),
-	}
+	service := newQueryService(p, b)
 
 	b.AddHandleFunc(func(s *grpc.Server) {
 		pb.RegisterQueryServiceServer(s, service)
@@ -120,6 +134,58 @@ func BindService(p *appmain.Params, b *appmain.Bindings) error {
 		cacheFetchedItemsView,
 		cacheWaitingQueriesView,
 		cacheUpdateLatencyView,
+		reservedTotalTicketsView,
+		ticketsReservedView,
 	)
 	return nil
 }
+
+// localBufferSize is the size of the in-memory connection to a local query
+// service.
+const localBufferSize = 1 << 20
+
+// NewLocalClient creates a query service in the calling process, and returns
+// a client connected to it in memory, without going through the network.  The
+// backend uses it for the match functions it runs itself.
+func NewLocalClient(p *appmain.Params, b *appmain.Bindings) (pb.QueryServiceClient, error) {
+	service := newQueryService(p, b)
+
+	lis := bufconn.Listen(localBufferSize)
+	s := grpc.NewServer()
+	pb.RegisterQueryServiceServer(s, service)
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			logger.WithError(err).Error("local query service stopped serving")
+		}
+	}()
+
+	conn, err := grpc.Dial("passthrough:///local-query",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		s.Stop()
+		return nil, err
+	}
+	b.AddCloser(func() {
+		if err := conn.Close(); err != nil {
+			logger.WithError(err).Warning("Error closing local query client.")
+		}
+		s.Stop()
+	})
+
+	return pb.NewQueryServiceClient(conn), nil
+}
+
+func newQueryService(p *appmain.Params, b *appmain.Bindings) *queryService {
+	store := statestore.New(p.Config())
+	return &queryService{
+		cfg:   p.Config(),
+		store: store,
+		tc:    newTicketCache(b, store),
+		bc:    newBackfillCache(b, store),
+		rc:    newReservationCache(b, store),
+	}
+}
@@ -15,6 +15,8 @@
 package query
 
 import (
+	"context"
+
 	"go.opencensus.io/stats"
 
 	"github.com/pkg/errors"
@@ -23,6 +25,7 @@ import (
 	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/filter"
+	"open-match.dev/open-match/internal/statestore"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -36,9 +39,12 @@ var (
 // queryService API provides utility functions for common MMF functionality such
 // as retrieving Tickets from state storage.
 type queryService struct {
-	cfg config.View
-	tc  *cache
-	bc  *cache
+	cfg   config.View
+	store statestore.Service
+	tc    *cache
+	bc    *cache
+	// rc caches the profile reserving each reserved ticket.
+	rc *cache
 }
 
 func (s *queryService) QueryTickets(req *pb.QueryTicketsRequest, responseServer pb.QueryService_QueryTicketsServer) error {
@@ -53,6 +59,11 @@ func (s *queryService) QueryTickets(req *pb.QueryTicketsRequest, responseServer
 		return err
 	}
 
+	reservations, err := s.reservations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "QueryTickets: failed to get reservations")
+	}
+
 	var results []*pb.Ticket
 	err = s.tc.request(ctx, func(value interface{}) {
 		tickets, ok := value.(map[string]*pb.Ticket)
@@ -62,7 +73,7 @@ func (s *queryService) QueryTickets(req *pb.QueryTicketsRequest, responseServer
 		}
 
 		for _, ticket := range tickets {
-			if pf.In(ticket) {
+			if pf.In(ticket) && visible(reservations, ticket.GetId(), req.GetProfileName()) {
 				results = append(results, ticket)
 			}
 		}
@@ -103,6 +114,11 @@ func (s *queryService) QueryTicketIds(req *pb.QueryTicketIdsRequest, responseSer
 		return err
 	}
 
+	reservations, err := s.reservations(ctx)
+	if err != nil {
+		return errors.Wrap(err, "QueryTicketIds: failed to get reservations")
+	}
+
 	var results []string
 	err = s.tc.request(ctx, func(value interface{}) {
 		tickets, ok := value.(map[string]*pb.Ticket)
@@ -112,7 +128,7 @@ func (s *queryService) QueryTicketIds(req *pb.QueryTicketIdsRequest, responseSer
 		}
 
 		for id, ticket := range tickets {
-			if pf.In(ticket) {
+			if pf.In(ticket) && visible(reservations, id, req.GetProfileName()) {
 				results = append(results, id)
 			}
 		}
@@ -141,6 +157,53 @@ func (s *queryService) QueryTicketIds(req *pb.QueryTicketIdsRequest, responseSer
 	return nil
 }
 
+func (s *queryService) ReserveTickets(ctx context.Context, req *pb.ReserveTicketsRequest) (*pb.ReserveTicketsResponse, error) {
+	if req.GetProfileName() == "" {
+		return nil, status.Error(codes.InvalidArgument, ".profile_name is required")
+	}
+	if len(req.GetTicketIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ".ticket_ids is required")
+	}
+	maxCycles := getMaxReservationCycles(s.cfg)
+	if req.GetCycles() < 1 || int(req.GetCycles()) > maxCycles {
+		return nil, status.Errorf(codes.InvalidArgument, ".cycles must be between 1 and %d, got %d", maxCycles, req.GetCycles())
+	}
+
+	reserved, err := s.store.ReserveTickets(ctx, req.GetProfileName(), req.GetTicketIds(), int(req.GetCycles()))
+	if err != nil {
+		return nil, err
+	}
+	stats.Record(ctx, ticketsReserved.M(int64(len(reserved))))
+
+	return &pb.ReserveTicketsResponse{TicketIds: reserved}, nil
+}
+
+// reservations returns a copy of the cached profile reserving each reserved
+// ticket.
+func (s *queryService) reservations(ctx context.Context) (map[string]string, error) {
+	var r map[string]string
+	err := s.rc.request(ctx, func(value interface{}) {
+		reservations, ok := value.(map[string]string)
+		if !ok {
+			logger.Errorf("expecting value type map[string]string, but got: %T", value)
+			return
+		}
+
+		r = make(map[string]string, len(reservations))
+		for id, profile := range reservations {
+			r[id] = profile
+		}
+	})
+	return r, err
+}
+
+// visible reports whether a ticket can be returned to a query for the
+// profile, which is the case unless another profile reserved it.
+func visible(reservations map[string]string, id, profile string) bool {
+	reservedBy, ok := reservations[id]
+	return !ok || reservedBy == profile
+}
+
 func (s *queryService) QueryBackfills(req *pb.QueryBackfillsRequest, responseServer pb.QueryService_QueryBackfillsServer) error {
 	ctx := responseServer.Context()
 	pool := req.GetPool()
@@ -191,6 +254,125 @@ func (s *queryService) QueryBackfills(req *pb.QueryBackfillsRequest, responseSer
 	return nil
 }
 
+func (s *queryService) QueryCompatibleBackfills(req *pb.QueryCompatibleBackfillsRequest, responseServer pb.QueryService_QueryCompatibleBackfillsServer) error {
+	ctx := responseServer.Context()
+	if len(req.GetTickets()) == 0 && req.GetTicketPool() == nil {
+		return status.Error(codes.InvalidArgument, ".tickets or .ticket_pool is required")
+	}
+	if len(req.GetTickets()) > 0 && req.GetTicketPool() != nil {
+		return status.Error(codes.InvalidArgument, "only one of .tickets and .ticket_pool can be set")
+	}
+	if req.GetMaxBackfills() < 0 {
+		return status.Errorf(codes.InvalidArgument, ".max_backfills must not be negative, got %d", req.GetMaxBackfills())
+	}
+
+	bf, err := filter.NewPoolFilter(req.GetBackfillPool())
+	if err != nil {
+		return err
+	}
+
+	tickets := req.GetTickets()
+	if req.GetTicketPool() != nil {
+		tf, err := filter.NewPoolFilter(req.GetTicketPool())
+		if err != nil {
+			return err
+		}
+
+		reservations, err := s.reservations(ctx)
+		if err != nil {
+			return errors.Wrap(err, "QueryCompatibleBackfills: failed to get reservations")
+		}
+
+		err = s.tc.request(ctx, func(value interface{}) {
+			cached, ok := value.(map[string]*pb.Ticket)
+			if !ok {
+				logger.Errorf("expecting value type map[string]*pb.Ticket, but got: %T", value)
+				return
+			}
+
+			for id, ticket := range cached {
+				if tf.In(ticket) && visible(reservations, id, req.GetProfileName()) {
+					tickets = append(tickets, ticket)
+				}
+			}
+		})
+		if err != nil {
+			return errors.Wrap(err, "QueryCompatibleBackfills: failed to run tickets request")
+		}
+		stats.Record(ctx, ticketsPerQuery.M(int64(len(tickets))))
+	}
+
+	var backfills []*pb.Backfill
+	err = s.bc.request(ctx, func(value interface{}) {
+		cached, ok := value.(map[string]*pb.Backfill)
+		if !ok {
+			logger.Errorf("expecting value type map[string]*pb.Backfill, but got: %T", value)
+			return
+		}
+
+		for _, backfill := range cached {
+			if bf.In(backfill) {
+				backfills = append(backfills, backfill)
+			}
+		}
+	})
+	if err != nil {
+		return errors.Wrap(err, "QueryCompatibleBackfills: failed to run backfills request")
+	}
+	stats.Record(ctx, backfillsPerQuery.M(int64(len(backfills))))
+
+	c, err := newCompatibility(req.GetCompatibility(), backfills)
+	if err != nil {
+		return err
+	}
+
+	limit := int(req.GetMaxBackfills())
+	if limit == 0 {
+		limit = defaultMaxBackfills
+	}
+
+	var results []*pb.TicketBackfills
+	for _, ticket := range tickets {
+		if compatible := c.rank(ticket, limit); len(compatible) > 0 {
+			results = append(results, &pb.TicketBackfills{
+				TicketId:  ticket.GetId(),
+				Backfills: compatible,
+			})
+		}
+	}
+
+	pSize := getPageSize(s.cfg)
+	for start := 0; start < len(results); start += pSize {
+		end := start + pSize
+		if end > len(results) {
+			end = len(results)
+		}
+
+		err := responseServer.Send(&pb.QueryCompatibleBackfillsResponse{
+			TicketBackfills: results[start:end],
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getMaxReservationCycles(cfg config.View) int {
+	const (
+		name = "maxReservationCycles"
+		// Default bound on the number of synchronizer cycles a ticket reservation
+		// lasts.
+		defaultMaxCycles = 10
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMaxCycles
+	}
+	return cfg.GetInt(name)
+}
+
 func getPageSize(cfg config.View) int {
 	const (
 		name = "queryPageSize"
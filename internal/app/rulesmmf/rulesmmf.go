@@ -0,0 +1,589 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rulesmmf provides a match function configured by MatchRules in the
+// match profile, which covers game modes filling teams from pools.
+package rulesmmf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"open-match.dev/open-match/internal/appmain"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/pkg/matchfunction"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// RulesExtension is the MatchProfile extension holding MatchRules.
+	RulesExtension = "rules"
+	// TeamsExtension is the Match extension holding MatchTeams.
+	TeamsExtension = "teams"
+
+	matchFunctionName = "rules"
+)
+
+var (
+	logger = logrus.WithFields(logrus.Fields{
+		"app":       "openmatch",
+		"component": "app.rulesmmf",
+	})
+)
+
+// BindService creates the rule based match function service and binds it to
+// the serving harness.
+func BindService(p *appmain.Params, b *appmain.Bindings) error {
+	conn, err := rpc.GRPCClientFromConfig(p.Config(), "api.query")
+	if err != nil {
+		return err
+	}
+	b.AddCloserErr(conn.Close)
+	query := pb.NewQueryServiceClient(conn)
+
+	service := matchfunction.NewServer(func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		return Run(ctx, query, profile, out)
+	})
+	b.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterMatchFunctionServer(s, service)
+	}, pb.RegisterMatchFunctionHandlerFromEndpoint)
+	b.AddWebSocketHandler("/v1/matchfunction:run", rpc.NewWebSocketHandler(service, pb.MatchFunction_ServiceDesc.Streams[0].Handler))
+	return nil
+}
+
+// Function runs the rule based match function in the backend, see
+// matchfunction.Register.
+var Function matchfunction.InProcessFunction = matchfunction.InProcessFunctionFunc(Run)
+
+// Run makes matches for the profile following its MatchRules.
+func Run(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+	rules, err := rulesFromProfile(profile)
+	if err != nil {
+		return err
+	}
+
+	poolTickets, err := matchfunction.QueryProfilePools(ctx, query, profile)
+	if err != nil {
+		return err
+	}
+
+	matches, err := makeMatches(profile, rules, poolTickets, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, m := range matches {
+		select {
+		case out <- m:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func rulesFromProfile(profile *pb.MatchProfile) (*pb.MatchRules, error) {
+	a, ok := profile.GetExtensions()[RulesExtension]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "profile %q is missing the %q extension", profile.GetName(), RulesExtension)
+	}
+	rules := &pb.MatchRules{}
+	if err := a.UnmarshalTo(rules); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to unmarshal the %q extension of profile %q: %s", RulesExtension, profile.GetName(), err.Error())
+	}
+
+	pools := map[string]struct{}{}
+	for _, pool := range profile.GetPools() {
+		pools[pool.GetName()] = struct{}{}
+	}
+
+	if len(rules.GetTeams()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "rules must have at least one team")
+	}
+	for _, team := range rules.GetTeams() {
+		if len(team.GetPoolSlots()) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "team %q must have at least one slot", team.GetName())
+		}
+		for pool, slots := range team.GetPoolSlots() {
+			if _, ok := pools[pool]; !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "team %q has slots for pool %q, which is not in profile %q", team.GetName(), pool, profile.GetName())
+			}
+			if slots <= 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "team %q must have a positive number of slots for pool %q, got %d", team.GetName(), pool, slots)
+			}
+		}
+	}
+
+	if b := rules.GetBalance(); b != nil {
+		if b.GetDoubleArg() == "" {
+			return nil, status.Error(codes.InvalidArgument, "balance.double_arg is required")
+		}
+		if b.GetMaxDifference() < 0 || math.IsNaN(b.GetMaxDifference()) {
+			return nil, status.Errorf(codes.InvalidArgument, "balance.max_difference must not be negative, got %v", b.GetMaxDifference())
+		}
+	}
+
+	if rules.MaxWait != nil {
+		if err := rules.MaxWait.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid max_wait: %s", err.Error())
+		}
+		if rules.MaxWait.AsDuration() < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "max_wait must not be negative, got %v", rules.MaxWait.AsDuration())
+		}
+	}
+
+	return rules, nil
+}
+
+// matchMaker holds the state of filling matches from the pools.
+type matchMaker struct {
+	rules *pb.MatchRules
+	now   time.Time
+
+	// Pools with slots, in the order they are filled.
+	pools []string
+	// Number of tickets a match takes from each pool.
+	needed map[string]int
+	// Tickets of each pool, oldest first.
+	tickets map[string][]*pb.Ticket
+	// Index in tickets of each pool before which all tickets are in a match
+	// or passed over.
+	cursor map[string]int
+	// Buckets of each pool, in the order tickets are picked from them.
+	buckets map[string][]*bucket
+	// Tickets already in a match.
+	used map[string]bool
+}
+
+// makeMatches greedily makes matches, each time starting from the oldest
+// ticket which isn't in a match, and filling the rest of the match with the
+// tickets closest to it.  A ticket whose match can't be balanced is passed
+// over, but can still fill matches started from other tickets.
+func makeMatches(profile *pb.MatchProfile, rules *pb.MatchRules, poolTickets map[string][]*pb.Ticket, now time.Time) ([]*pb.Match, error) {
+	mm := &matchMaker{
+		rules:   rules,
+		now:     now,
+		needed:  map[string]int{},
+		tickets: map[string][]*pb.Ticket{},
+		cursor:  map[string]int{},
+		buckets: map[string][]*bucket{},
+		used:    map[string]bool{},
+	}
+	for _, team := range rules.GetTeams() {
+		for pool, slots := range team.GetPoolSlots() {
+			if _, ok := mm.needed[pool]; !ok {
+				mm.pools = append(mm.pools, pool)
+			}
+			mm.needed[pool] += int(slots)
+		}
+	}
+	sort.Strings(mm.pools)
+
+	for _, pool := range mm.pools {
+		tickets := append([]*pb.Ticket(nil), poolTickets[pool]...)
+		sort.SliceStable(tickets, func(i, j int) bool {
+			return olderThan(tickets[i], tickets[j])
+		})
+		mm.tickets[pool] = tickets
+		mm.buckets[pool] = mm.newBuckets(tickets)
+	}
+
+	var matches []*pb.Match
+	passed := map[string]bool{}
+	for {
+		anchor, anchorPool := mm.oldest(passed)
+		if anchor == nil {
+			return matches, nil
+		}
+
+		picked, ok := mm.pick(anchor, anchorPool)
+		if !ok {
+			// Some pool has run out of tickets.
+			return matches, nil
+		}
+
+		teams := mm.assign(picked)
+		if !mm.balanced(teams) && !mm.anyOverdue(picked) {
+			passed[anchor.GetId()] = true
+			continue
+		}
+
+		m, err := mm.newMatch(profile, len(matches), teams)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range m.GetTickets() {
+			mm.used[t.GetId()] = true
+			for _, pool := range mm.pools {
+				for _, b := range mm.buckets[pool] {
+					b.remove(t.GetId())
+				}
+			}
+		}
+		matches = append(matches, m)
+	}
+}
+
+func olderThan(a, b *pb.Ticket) bool {
+	ta, tb := a.GetCreateTime().AsTime(), b.GetCreateTime().AsTime()
+	if !ta.Equal(tb) {
+		return ta.Before(tb)
+	}
+	return a.GetId() < b.GetId()
+}
+
+func (mm *matchMaker) overdue(t *pb.Ticket) bool {
+	return mm.rules.MaxWait != nil && mm.now.Sub(t.GetCreateTime().AsTime()) >= mm.rules.MaxWait.AsDuration()
+}
+
+func (mm *matchMaker) anyOverdue(picked map[string][]*pb.Ticket) bool {
+	for _, tickets := range picked {
+		for _, t := range tickets {
+			if mm.overdue(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (mm *matchMaker) value(t *pb.Ticket) float64 {
+	return t.GetSearchFields().GetDoubleArgs()[mm.rules.GetBalance().GetDoubleArg()]
+}
+
+// oldest returns the oldest ticket which isn't in a match or passed over, and
+// its pool.
+func (mm *matchMaker) oldest(passed map[string]bool) (*pb.Ticket, string) {
+	var oldest *pb.Ticket
+	var oldestPool string
+	for _, pool := range mm.pools {
+		tickets := mm.tickets[pool]
+		// Tickets never leave a match or stop being passed over, so the cursor
+		// only moves forward.
+		i := mm.cursor[pool]
+		for i < len(tickets) && (mm.used[tickets[i].GetId()] || passed[tickets[i].GetId()]) {
+			i++
+		}
+		mm.cursor[pool] = i
+		if i < len(tickets) && (oldest == nil || olderThan(tickets[i], oldest)) {
+			oldest, oldestPool = tickets[i], pool
+		}
+	}
+	return oldest, oldestPool
+}
+
+// newBuckets groups the tickets of a pool, oldest first, in the order they are
+// picked.  When balancing, overdue tickets are picked before the others, and
+// each bucket is ordered by the balanced double_arg.  Otherwise overdue tickets
+// are the oldest anyway, so a single bucket keeps the tickets oldest first.
+func (mm *matchMaker) newBuckets(tickets []*pb.Ticket) []*bucket {
+	if mm.rules.GetBalance() == nil {
+		return []*bucket{newBucket(tickets, nil)}
+	}
+
+	var overdue, rest []*pb.Ticket
+	for _, t := range tickets {
+		if mm.overdue(t) {
+			overdue = append(overdue, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return []*bucket{newBucket(overdue, mm.value), newBucket(rest, mm.value)}
+}
+
+// pick chooses the tickets of a match including anchor, by pool.  Overdue
+// tickets are picked first, then the tickets closest to anchor on the
+// balanced double_arg, or else the oldest.
+func (mm *matchMaker) pick(anchor *pb.Ticket, anchorPool string) (map[string][]*pb.Ticket, bool) {
+	picked := map[string][]*pb.Ticket{anchorPool: {anchor}}
+	inMatch := map[string]bool{anchor.GetId(): true}
+
+	var target float64
+	if mm.rules.GetBalance() != nil {
+		target = mm.value(anchor)
+	}
+
+	for _, pool := range mm.pools {
+		missing := mm.needed[pool] - len(picked[pool])
+		for _, b := range mm.buckets[pool] {
+			if missing <= 0 {
+				break
+			}
+			tickets := b.closest(target, missing, inMatch)
+			for _, t := range tickets {
+				inMatch[t.GetId()] = true
+			}
+			picked[pool] = append(picked[pool], tickets...)
+			missing -= len(tickets)
+		}
+		if missing > 0 {
+			return nil, false
+		}
+	}
+	return picked, true
+}
+
+// bucket holds tickets which can still be picked, sorted once so that picking
+// the tickets of a match doesn't rescan the whole pool.
+type bucket struct {
+	// Tickets ordered by values, or oldest first if there are no values.
+	// Tickets with the same value are oldest first.
+	tickets []*pb.Ticket
+	values  []float64
+	// Index of each ticket in tickets.
+	index map[string]int
+
+	// next[i] leads to the first ticket at or after i which is still in the
+	// bucket, and prev[i+1] to the last one at or before i.  Removed tickets
+	// are skipped by following them, compressing the paths taken.
+	next []int
+	prev []int
+}
+
+// newBucket creates a bucket of tickets, which are oldest first, ordered by
+// value if it is not nil.
+func newBucket(tickets []*pb.Ticket, value func(*pb.Ticket) float64) *bucket {
+	b := &bucket{
+		tickets: append([]*pb.Ticket(nil), tickets...),
+		index:   make(map[string]int, len(tickets)),
+		next:    make([]int, len(tickets)+1),
+		prev:    make([]int, len(tickets)+1),
+	}
+	if value != nil {
+		sort.SliceStable(b.tickets, func(i, j int) bool {
+			vi, vj := value(b.tickets[i]), value(b.tickets[j])
+			// NaNs first, as sort.SearchFloat64s expects.
+			return vi < vj || (math.IsNaN(vi) && !math.IsNaN(vj))
+		})
+		b.values = make([]float64, len(b.tickets))
+		for i, t := range b.tickets {
+			b.values[i] = value(t)
+		}
+	}
+	for i, t := range b.tickets {
+		b.index[t.GetId()] = i
+	}
+	for i := range b.next {
+		b.next[i] = i
+		b.prev[i] = i
+	}
+	return b
+}
+
+// remove takes the ticket out of the bucket, if it is in it.
+func (b *bucket) remove(id string) {
+	i, ok := b.index[id]
+	if !ok {
+		return
+	}
+	delete(b.index, id)
+	b.next[i] = i + 1
+	b.prev[i+1] = i
+}
+
+// after returns the index of the first ticket at or after i, or
+// len(b.tickets) if there is none.
+func (b *bucket) after(i int) int {
+	return find(b.next, i)
+}
+
+// before returns the index of the last ticket at or before i, or -1 if there
+// is none.
+func (b *bucket) before(i int) int {
+	return find(b.prev, i+1) - 1
+}
+
+func find(links []int, i int) int {
+	root := i
+	for links[root] != root {
+		root = links[root]
+	}
+	for links[i] != root {
+		links[i], i = root, links[i]
+	}
+	return root
+}
+
+// closest returns up to n tickets of the bucket which aren't in skip: the
+// closest to target by value and oldest first among equally close ones, or the
+// oldest if the bucket has no values.
+func (b *bucket) closest(target float64, n int, skip map[string]bool) []*pb.Ticket {
+	var picked []*pb.Ticket
+	if b.values == nil {
+		for i := b.after(0); i < len(b.tickets) && len(picked) < n; i = b.after(i + 1) {
+			if !skip[b.tickets[i].GetId()] {
+				picked = append(picked, b.tickets[i])
+			}
+		}
+		return picked
+	}
+
+	// Walk outwards from target, so tickets come in order of distance.  Ties
+	// on the left come youngest first, so all tickets as close as the nth one
+	// are collected and then sorted.
+	type candidate struct {
+		index    int
+		distance float64
+	}
+	var candidates []candidate
+	var nth float64
+	start := sort.SearchFloat64s(b.values, target)
+	right, left := b.after(start), b.before(start-1)
+	for right < len(b.tickets) || left >= 0 {
+		var c candidate
+		if right < len(b.tickets) && (left < 0 || math.Abs(b.values[right]-target) <= math.Abs(b.values[left]-target)) {
+			c = candidate{right, math.Abs(b.values[right] - target)}
+			right = b.after(right + 1)
+		} else {
+			c = candidate{left, math.Abs(b.values[left] - target)}
+			left = b.before(left - 1)
+		}
+
+		if len(candidates) >= n && c.distance > nth {
+			break
+		}
+		if skip[b.tickets[c.index].GetId()] {
+			continue
+		}
+		candidates = append(candidates, c)
+		if len(candidates) == n {
+			nth = c.distance
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return olderThan(b.tickets[candidates[i].index], b.tickets[candidates[j].index])
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	for _, c := range candidates {
+		picked = append(picked, b.tickets[c.index])
+	}
+	return picked
+}
+
+// assign splits the picked tickets into teams.  When balancing, tickets are
+// taken highest double_arg first, each going to the team with the lowest
+// projected average which still has a slot for it.
+func (mm *matchMaker) assign(picked map[string][]*pb.Ticket) [][]*pb.Ticket {
+	teamRules := mm.rules.GetTeams()
+	teams := make([][]*pb.Ticket, len(teamRules))
+	sums := make([]float64, len(teamRules))
+	sizes := make([]float64, len(teamRules))
+	for i, team := range teamRules {
+		for _, slots := range team.GetPoolSlots() {
+			sizes[i] += float64(slots)
+		}
+	}
+
+	for _, pool := range mm.pools {
+		tickets := picked[pool]
+		if mm.rules.GetBalance() != nil {
+			tickets = append([]*pb.Ticket(nil), tickets...)
+			sort.SliceStable(tickets, func(i, j int) bool {
+				return mm.value(tickets[i]) > mm.value(tickets[j])
+			})
+		}
+
+		free := make([]int, len(teamRules))
+		for i, team := range teamRules {
+			free[i] = int(team.GetPoolSlots()[pool])
+		}
+
+		for _, t := range tickets {
+			best := -1
+			for i := range teamRules {
+				if free[i] == 0 {
+					continue
+				}
+				if best == -1 || (mm.rules.GetBalance() != nil && sums[i]/sizes[i] < sums[best]/sizes[best]) {
+					best = i
+				}
+			}
+			free[best]--
+			teams[best] = append(teams[best], t)
+			sums[best] += mm.value(t)
+		}
+	}
+	return teams
+}
+
+func (mm *matchMaker) balanced(teams [][]*pb.Ticket) bool {
+	if mm.rules.GetBalance() == nil {
+		return true
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, team := range teams {
+		sum := 0.0
+		for _, t := range team {
+			sum += mm.value(t)
+		}
+		avg := sum / float64(len(team))
+		low = math.Min(low, avg)
+		high = math.Max(high, avg)
+	}
+	return high-low <= mm.rules.GetBalance().GetMaxDifference()
+}
+
+// newMatch creates the match for teams.  Its score for the default evaluator
+// is the total time its tickets have waited, so that matches with the longest
+// waiting tickets are preferred.
+func (mm *matchMaker) newMatch(profile *pb.MatchProfile, num int, teams [][]*pb.Ticket) (*pb.Match, error) {
+	var tickets []*pb.Ticket
+	matchTeams := &pb.MatchTeams{}
+	waited := 0.0
+	for i, team := range teams {
+		mt := &pb.MatchTeams_Team{Name: mm.rules.GetTeams()[i].GetName()}
+		for _, t := range team {
+			tickets = append(tickets, t)
+			mt.TicketIds = append(mt.TicketIds, t.GetId())
+			waited += mm.now.Sub(t.GetCreateTime().AsTime()).Seconds()
+		}
+		matchTeams.Teams = append(matchTeams.Teams, mt)
+	}
+
+	teamsAny, err := anypb.New(matchTeams)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal match teams: %s", err.Error())
+	}
+	evaluationInput, err := anypb.New(&pb.DefaultEvaluationCriteria{Score: waited})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal evaluation input: %s", err.Error())
+	}
+
+	return &pb.Match{
+		MatchId:       fmt.Sprintf("profile-%s-time-%s-num-%d", profile.GetName(), mm.now.Format("2006-01-02T15:04:05.00"), num),
+		MatchProfile:  profile.GetName(),
+		MatchFunction: matchFunctionName,
+		Tickets:       tickets,
+		Extensions: map[string]*anypb.Any{
+			TeamsExtension:     teamsAny,
+			"evaluation_input": evaluationInput,
+		},
+	}, nil
+}
@@ -0,0 +1,322 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rulesmmf
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"open-match.dev/open-match/examples/scale/scenarios/battleroyal"
+	"open-match.dev/open-match/examples/scale/scenarios/firstmatch"
+	"open-match.dev/open-match/examples/scale/scenarios/teamshooter"
+	"open-match.dev/open-match/internal/filter"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func profileWithRules(t *testing.T, profile *pb.MatchProfile, rules *pb.MatchRules) *pb.MatchProfile {
+	profile = proto.Clone(profile).(*pb.MatchProfile)
+	a, err := anypb.New(rules)
+	require.NoError(t, err)
+	if profile.Extensions == nil {
+		profile.Extensions = map[string]*anypb.Any{}
+	}
+	profile.Extensions[RulesExtension] = a
+	return profile
+}
+
+func TestRulesFromProfile(t *testing.T) {
+	profile := &pb.MatchProfile{
+		Name:  "profile",
+		Pools: []*pb.Pool{{Name: "tanks"}, {Name: "healers"}},
+	}
+	twoTeams := []*pb.MatchRules_Team{
+		{Name: "red", PoolSlots: map[string]int32{"tanks": 1, "healers": 1}},
+		{Name: "blue", PoolSlots: map[string]int32{"tanks": 1, "healers": 1}},
+	}
+
+	tests := []struct {
+		description string
+		rules       *pb.MatchRules
+		err         string
+	}{
+		{
+			description: "valid",
+			rules: &pb.MatchRules{
+				Teams:   twoTeams,
+				Balance: &pb.MatchRules_Balance{DoubleArg: "skill", MaxDifference: 1},
+				MaxWait: durationpb.New(time.Minute),
+			},
+		},
+		{
+			description: "no teams",
+			rules:       &pb.MatchRules{},
+			err:         "rules must have at least one team",
+		},
+		{
+			description: "team without slots",
+			rules:       &pb.MatchRules{Teams: []*pb.MatchRules_Team{{Name: "red"}}},
+			err:         `team "red" must have at least one slot`,
+		},
+		{
+			description: "unknown pool",
+			rules:       &pb.MatchRules{Teams: []*pb.MatchRules_Team{{Name: "red", PoolSlots: map[string]int32{"dps": 1}}}},
+			err:         `team "red" has slots for pool "dps", which is not in profile "profile"`,
+		},
+		{
+			description: "zero slots",
+			rules:       &pb.MatchRules{Teams: []*pb.MatchRules_Team{{Name: "red", PoolSlots: map[string]int32{"tanks": 0}}}},
+			err:         `team "red" must have a positive number of slots for pool "tanks", got 0`,
+		},
+		{
+			description: "balance without double_arg",
+			rules:       &pb.MatchRules{Teams: twoTeams, Balance: &pb.MatchRules_Balance{MaxDifference: 1}},
+			err:         "balance.double_arg is required",
+		},
+		{
+			description: "negative max_difference",
+			rules:       &pb.MatchRules{Teams: twoTeams, Balance: &pb.MatchRules_Balance{DoubleArg: "skill", MaxDifference: -1}},
+			err:         "balance.max_difference must not be negative",
+		},
+		{
+			description: "negative max_wait",
+			rules:       &pb.MatchRules{Teams: twoTeams, MaxWait: durationpb.New(-time.Second)},
+			err:         "max_wait must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			rules, err := rulesFromProfile(profileWithRules(t, profile, test.rules))
+			if test.err == "" {
+				require.NoError(t, err)
+				require.True(t, proto.Equal(test.rules, rules))
+				return
+			}
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+
+	_, err := rulesFromProfile(profile)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Contains(t, err.Error(), `profile "profile" is missing the "rules" extension`)
+}
+
+// scenarioTickets creates tickets with the scenario, which waited up to two
+// minutes, and returns the ones in each pool of the profile.
+func scenarioTickets(t *testing.T, ticket func() *pb.Ticket, profile *pb.MatchProfile, count int, now time.Time) map[string][]*pb.Ticket {
+	poolTickets := map[string][]*pb.Ticket{}
+	filters := map[string]*filter.PoolFilter{}
+	for _, pool := range profile.GetPools() {
+		pf, err := filter.NewPoolFilter(pool)
+		require.NoError(t, err)
+		filters[pool.GetName()] = pf
+	}
+
+	for i := 0; i < count; i++ {
+		tk := ticket()
+		tk.Id = fmt.Sprintf("ticket-%d", i)
+		tk.CreateTime = timestamppb.New(now.Add(-time.Duration(rand.Int63n(int64(2 * time.Minute)))))
+		for name, pf := range filters {
+			if pf.In(tk) {
+				poolTickets[name] = append(poolTickets[name], tk)
+			}
+		}
+	}
+	return poolTickets
+}
+
+// TestMakeMatchesScenarios configures the rule based match function for the
+// game modes of the scale scenarios, and checks its matches follow the rules.
+func TestMakeMatchesScenarios(t *testing.T) {
+	tests := []struct {
+		description string
+		profile     *pb.MatchProfile
+		ticket      func() *pb.Ticket
+		count       int
+		rules       *pb.MatchRules
+	}{
+		{
+			description: "firstmatch 1v1",
+			profile:     firstmatch.Scenario().Profiles()[0],
+			ticket:      firstmatch.Scenario().Ticket,
+			count:       101,
+			rules: &pb.MatchRules{
+				Teams: []*pb.MatchRules_Team{
+					{Name: "a", PoolSlots: map[string]int32{"all": 1}},
+					{Name: "b", PoolSlots: map[string]int32{"all": 1}},
+				},
+			},
+		},
+		{
+			description: "battleroyal free for all",
+			profile:     battleroyal.Scenario().Profiles()[0],
+			ticket:      battleroyal.Scenario().Ticket,
+			count:       5000,
+			rules: &pb.MatchRules{
+				Teams: []*pb.MatchRules_Team{
+					{Name: "all", PoolSlots: map[string]int32{"all": 100}},
+				},
+			},
+		},
+		{
+			description: "teamshooter balanced 6v6",
+			profile:     teamshooter.Scenario().Profiles()[0],
+			ticket:      teamshooter.Scenario().Ticket,
+			count:       2000,
+			rules: &pb.MatchRules{
+				Teams: []*pb.MatchRules_Team{
+					{Name: "red", PoolSlots: map[string]int32{"all": 6}},
+					{Name: "blue", PoolSlots: map[string]int32{"all": 6}},
+				},
+				Balance: &pb.MatchRules_Balance{DoubleArg: "skill", MaxDifference: 0.05},
+				MaxWait: durationpb.New(90 * time.Second),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.description, func(t *testing.T) {
+			now := time.Now()
+			profile := profileWithRules(t, test.profile, test.rules)
+			rules, err := rulesFromProfile(profile)
+			require.NoError(t, err)
+			poolTickets := scenarioTickets(t, test.ticket, profile, test.count, now)
+
+			matches, err := makeMatches(profile, rules, poolTickets, now)
+			require.NoError(t, err)
+			require.NotEmpty(t, matches)
+
+			tickets := map[string]*pb.Ticket{}
+			for _, pt := range poolTickets {
+				for _, tk := range pt {
+					tickets[tk.GetId()] = tk
+				}
+			}
+
+			used := map[string]bool{}
+			for _, m := range matches {
+				require.Equal(t, profile.GetName(), m.GetMatchProfile())
+				require.Equal(t, "rules", m.GetMatchFunction())
+				require.Contains(t, m.GetExtensions(), "evaluation_input")
+
+				teams := &pb.MatchTeams{}
+				require.NoError(t, m.GetExtensions()[TeamsExtension].UnmarshalTo(teams))
+				require.Len(t, teams.GetTeams(), len(rules.GetTeams()))
+
+				overdue := false
+				low, high := math.Inf(1), math.Inf(-1)
+				for i, team := range teams.GetTeams() {
+					require.Equal(t, rules.GetTeams()[i].GetName(), team.GetName())
+					size := 0
+					for _, slots := range rules.GetTeams()[i].GetPoolSlots() {
+						size += int(slots)
+					}
+					require.Len(t, team.GetTicketIds(), size)
+
+					sum := 0.0
+					for _, id := range team.GetTicketIds() {
+						require.False(t, used[id], "ticket %s is in two matches", id)
+						used[id] = true
+						tk := tickets[id]
+						require.NotNil(t, tk)
+						sum += tk.GetSearchFields().GetDoubleArgs()[rules.GetBalance().GetDoubleArg()]
+						if rules.MaxWait != nil && now.Sub(tk.GetCreateTime().AsTime()) >= rules.MaxWait.AsDuration() {
+							overdue = true
+						}
+					}
+					avg := sum / float64(len(team.GetTicketIds()))
+					low, high = math.Min(low, avg), math.Max(high, avg)
+				}
+
+				if rules.GetBalance() != nil && !overdue {
+					require.LessOrEqual(t, high-low, rules.GetBalance().GetMaxDifference())
+				}
+			}
+
+			if rules.GetBalance() == nil {
+				// Without balance, matches are made until the pool runs out.
+				size := 0
+				for _, team := range rules.GetTeams() {
+					size += int(team.GetPoolSlots()["all"])
+				}
+				require.Less(t, len(tickets)-len(used), size)
+			}
+		})
+	}
+}
+
+func TestMakeMatchesOldestFirst(t *testing.T) {
+	now := time.Now()
+	ticket := func(id string, age time.Duration, skill float64) *pb.Ticket {
+		return &pb.Ticket{
+			Id:           id,
+			CreateTime:   timestamppb.New(now.Add(-age)),
+			SearchFields: &pb.SearchFields{DoubleArgs: map[string]float64{"skill": skill}},
+		}
+	}
+	profile := &pb.MatchProfile{Name: "profile", Pools: []*pb.Pool{{Name: "all"}}}
+	rules := &pb.MatchRules{
+		Teams: []*pb.MatchRules_Team{
+			{Name: "a", PoolSlots: map[string]int32{"all": 1}},
+			{Name: "b", PoolSlots: map[string]int32{"all": 1}},
+		},
+		Balance: &pb.MatchRules_Balance{DoubleArg: "skill", MaxDifference: 1},
+		MaxWait: durationpb.New(time.Minute),
+	}
+	poolTickets := map[string][]*pb.Ticket{"all": {
+		ticket("old", 30*time.Second, 5),
+		ticket("outlier", 40*time.Second, 100),
+		ticket("close", 0, 5.2),
+		ticket("mid", time.Second, 5.5),
+		ticket("far", time.Second, 9),
+	}}
+
+	matchIds := func(matches []*pb.Match) [][]string {
+		var ids [][]string
+		for _, m := range matches {
+			var match []string
+			for _, tk := range m.GetTickets() {
+				match = append(match, tk.GetId())
+			}
+			ids = append(ids, match)
+		}
+		return ids
+	}
+
+	// The outlier can't be balanced, so it is passed over for the next oldest
+	// ticket, which is matched with the closest ticket rather than the oldest.
+	matches, err := makeMatches(profile, rules, poolTickets, now)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"close", "old"}}, matchIds(matches))
+
+	// Once overdue, the outlier is matched regardless of balance.
+	poolTickets["all"][1] = ticket("outlier", 2*time.Minute, 100)
+	matches, err = makeMatches(profile, rules, poolTickets, now)
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"outlier", "far"}, {"close", "old"}}, matchIds(matches))
+}
@@ -0,0 +1,203 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	cyclesEndpoint = "/cyclez"
+	cyclesPage     = `<!DOCTYPE html>
+<head>
+	<title>Open Match Synchronizer Cycles</title>
+</head>
+<body>
+<table>
+<tr><th>Start</th><th>State</th><th>Duration</th><th>Registrations</th><th>Proposals</th><th>Accepted</th><th>Rejected</th><th>Evaluator Latency</th><th>Cancel Cause</th></tr>
+{{ range . }}
+<tr><td>{{ .Start.Format "2006-01-02T15:04:05.000Z07:00" }}</td><td>{{ .State }}</td><td>{{ .Duration }}</td><td>{{ .Registrations }}</td><td>{{ range .Proposals }}{{ .Profile }}: {{ .Count }}<br>{{ end }}</td><td>{{ .Accepted }}</td><td>{{ .Rejected }}</td><td>{{ .EvaluatorLatency }}</td><td>{{ .CancelCause }}</td></tr>
+{{ end }}
+</table>
+</body>
+`
+)
+
+var cyclesPageTemplate = template.Must(template.New("cyclez").Parse(cyclesPage))
+
+// cycleHistory keeps the running synchronizer cycle and the last finished
+// ones, so that stalls can be debugged from the cycles page.
+type cycleHistory struct {
+	mu   sync.Mutex
+	size int
+	// cycles holds the most recent cycles, newest first.
+	cycles []*cycleRecord
+}
+
+// cycleRecord is what the synchronizer remembers of a cycle.
+type cycleRecord struct {
+	mu               sync.Mutex
+	start            time.Time
+	registrationEnd  time.Time
+	end              time.Time
+	registrations    int
+	proposals        map[string]int
+	accepted         int
+	rejected         int
+	evaluatorLatency time.Duration
+	cancelCause      string
+}
+
+func newCycleHistory(size int) *cycleHistory {
+	if size < 0 {
+		size = 0
+	}
+	return &cycleHistory{size: size}
+}
+
+// start records a new cycle, forgetting the oldest one if there are more
+// than size.
+func (h *cycleHistory) start() *cycleRecord {
+	c := &cycleRecord{
+		start:     time.Now(),
+		proposals: map[string]int{},
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.cycles = append([]*cycleRecord{c}, h.cycles...)
+	if len(h.cycles) > h.size+1 {
+		h.cycles = h.cycles[:h.size+1]
+	}
+	return c
+}
+
+func (c *cycleRecord) registered() {
+	c.mu.Lock()
+	c.registrations++
+	c.mu.Unlock()
+}
+
+func (c *cycleRecord) closeRegistration() {
+	c.mu.Lock()
+	c.registrationEnd = time.Now()
+	c.mu.Unlock()
+}
+
+func (c *cycleRecord) proposed(profile string) {
+	c.mu.Lock()
+	c.proposals[profile]++
+	c.mu.Unlock()
+}
+
+func (c *cycleRecord) acceptedMatch() {
+	c.mu.Lock()
+	c.accepted++
+	c.mu.Unlock()
+}
+
+func (c *cycleRecord) rejectedMatch() {
+	c.mu.Lock()
+	c.rejected++
+	c.mu.Unlock()
+}
+
+func (c *cycleRecord) evaluated(latency time.Duration) {
+	c.mu.Lock()
+	c.evaluatorLatency = latency
+	c.mu.Unlock()
+}
+
+// finish records the end of the cycle, and why it was canceled if it was.
+func (c *cycleRecord) finish(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.end = time.Now()
+	if err != nil {
+		c.cancelCause = err.Error()
+	}
+}
+
+type profileProposals struct {
+	Profile string
+	Count   int
+}
+
+// cycleView is a cycle as shown on the cycles page.
+type cycleView struct {
+	Start            time.Time
+	State            string
+	Duration         time.Duration
+	Registrations    int
+	Proposals        []profileProposals
+	Accepted         int
+	Rejected         int
+	EvaluatorLatency time.Duration
+	CancelCause      string
+}
+
+func (c *cycleRecord) view() cycleView {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	v := cycleView{
+		Start:            c.start,
+		Registrations:    c.registrations,
+		Accepted:         c.accepted,
+		Rejected:         c.rejected,
+		EvaluatorLatency: c.evaluatorLatency,
+		CancelCause:      c.cancelCause,
+	}
+	switch {
+	case !c.end.IsZero():
+		v.State = "done"
+		v.Duration = c.end.Sub(c.start)
+	case !c.registrationEnd.IsZero():
+		v.State = "evaluating"
+		v.Duration = time.Since(c.start)
+	default:
+		v.State = "registering"
+		v.Duration = time.Since(c.start)
+	}
+	for profile, count := range c.proposals {
+		v.Proposals = append(v.Proposals, profileProposals{Profile: profile, Count: count})
+	}
+	sort.Slice(v.Proposals, func(i, j int) bool {
+		return v.Proposals[i].Profile < v.Proposals[j].Profile
+	})
+	return v
+}
+
+// ServeHTTP serves the /cyclez page listing the running and recent cycles.
+func (h *cycleHistory) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	h.mu.Lock()
+	cycles := append([]*cycleRecord(nil), h.cycles...)
+	h.mu.Unlock()
+
+	views := make([]cycleView, 0, len(cycles))
+	for _, c := range cycles {
+		views = append(views, c.view())
+	}
+
+	err := cyclesPageTemplate.Execute(w, views)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("cannot render HTML template, %s", err), http.StatusInternalServerError)
+	}
+}
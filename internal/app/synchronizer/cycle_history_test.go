@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycleHistory(t *testing.T) {
+	h := newCycleHistory(2)
+
+	oldest := h.start()
+	oldest.finish(nil)
+
+	done := h.start()
+	done.registered()
+	done.registered()
+	done.closeRegistration()
+	done.proposed("1v1")
+	done.proposed("1v1")
+	done.proposed("ffa")
+	done.acceptedMatch()
+	done.rejectedMatch()
+	done.evaluated(25 * time.Millisecond)
+	done.finish(errors.New("error calling evaluator: boom"))
+
+	h.start().finish(nil)
+	running := h.start()
+	running.registered()
+
+	// The running cycle is kept along with the last two finished ones.
+	require.Len(t, h.cycles, 3)
+	require.Same(t, running, h.cycles[0])
+	require.Same(t, done, h.cycles[2])
+
+	v := done.view()
+	require.Equal(t, "done", v.State)
+	require.Equal(t, 2, v.Registrations)
+	require.Equal(t, []profileProposals{{"1v1", 2}, {"ffa", 1}}, v.Proposals)
+	require.Equal(t, 1, v.Accepted)
+	require.Equal(t, 1, v.Rejected)
+	require.Equal(t, 25*time.Millisecond, v.EvaluatorLatency)
+	require.Equal(t, "error calling evaluator: boom", v.CancelCause)
+	require.Equal(t, "registering", running.view().State)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, cyclesEndpoint, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "<td>registering</td>")
+	require.Contains(t, rec.Body.String(), "1v1: 2<br>ffa: 1<br>")
+	require.Contains(t, rec.Body.String(), "<td>25ms</td>")
+	require.Contains(t, rec.Body.String(), "error calling evaluator: boom")
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	rejectionsEndpoint = "/rejectionz"
+
+	// otherProfiles stands for the profiles which aren't tracked, because
+	// maxProfiles profiles already are.  Parentheses keep it apart from
+	// profile names in practice.
+	otherProfiles = "(other)"
+)
+
+// evaluationStats aggregates the evaluator's results per match profile, so
+// that profiles which systematically lose to others can be found.  At most
+// maxProfiles profiles are tracked, both on /rejectionz and as metric tags, and
+// profiles without results for ttl are dropped to make room for new ones.
+type evaluationStats struct {
+	maxProfiles int
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	profiles map[string]*profileEvaluations
+}
+
+// profileEvaluations counts the evaluation results of a profile's matches since
+// the profile was last tracked.
+type profileEvaluations struct {
+	Evaluated int64 `json:"evaluated"`
+	Accepted  int64 `json:"accepted"`
+	Rejected  int64 `json:"rejected"`
+	// Rejections by MatchRejection.Reason.
+	Reasons map[string]int64 `json:"reasons"`
+	// Rejections by the profile of the winning match.
+	LostTo map[string]int64 `json:"lostTo"`
+
+	lastSeen time.Time
+}
+
+func newEvaluationStats(maxProfiles int, ttl time.Duration) *evaluationStats {
+	return &evaluationStats{
+		maxProfiles: maxProfiles,
+		ttl:         ttl,
+		now:         time.Now,
+		profiles:    map[string]*profileEvaluations{},
+	}
+}
+
+// track returns the name the profile is counted under, which is otherProfiles
+// if it isn't tracked and there is no room for it, along with its counts.  mu
+// must be held.
+func (es *evaluationStats) track(name string) (string, *profileEvaluations) {
+	now := es.now()
+	p, ok := es.profiles[name]
+	if !ok && name != otherProfiles {
+		if es.trackedLen() >= es.maxProfiles {
+			es.evictStale(now)
+		}
+		if es.trackedLen() < es.maxProfiles {
+			p = newProfileEvaluations()
+			es.profiles[name] = p
+			ok = true
+		}
+	}
+	if !ok {
+		name = otherProfiles
+		p, ok = es.profiles[name]
+		if !ok {
+			p = newProfileEvaluations()
+			es.profiles[name] = p
+		}
+	}
+	p.lastSeen = now
+	return name, p
+}
+
+func newProfileEvaluations() *profileEvaluations {
+	return &profileEvaluations{
+		Reasons: map[string]int64{},
+		LostTo:  map[string]int64{},
+	}
+}
+
+// trackedLen is the number of tracked profiles, mu must be held.
+func (es *evaluationStats) trackedLen() int {
+	if _, ok := es.profiles[otherProfiles]; ok {
+		return len(es.profiles) - 1
+	}
+	return len(es.profiles)
+}
+
+// evictStale drops the profiles without results for ttl, mu must be held.
+func (es *evaluationStats) evictStale(now time.Time) {
+	var evicted []string
+	for name, p := range es.profiles {
+		if name != otherProfiles && now.Sub(p.lastSeen) >= es.ttl {
+			delete(es.profiles, name)
+			evicted = append(evicted, name)
+		}
+	}
+	// Losses to evicted profiles are now counted as losses to other profiles.
+	for _, p := range es.profiles {
+		for _, name := range evicted {
+			if n, ok := p.LostTo[name]; ok {
+				delete(p.LostTo, name)
+				p.LostTo[otherProfiles] += n
+			}
+		}
+	}
+}
+
+// tagged returns the name a profile which isn't being counted is reported
+// under, mu must be held.
+func (es *evaluationStats) tagged(name string) string {
+	if _, ok := es.profiles[name]; ok || name == "" {
+		return name
+	}
+	return otherProfiles
+}
+
+func (es *evaluationStats) evaluated(ctx context.Context, profile string) {
+	es.mu.Lock()
+	profile, p := es.track(profile)
+	p.Evaluated++
+	es.mu.Unlock()
+
+	recordWithTags(ctx, []tag.Mutator{tag.Upsert(keyProfile, profile)}, evaluatedMatches.M(1))
+}
+
+func (es *evaluationStats) accepted(ctx context.Context, profile string) {
+	es.mu.Lock()
+	profile, p := es.track(profile)
+	p.Accepted++
+	es.mu.Unlock()
+
+	recordWithTags(ctx, []tag.Mutator{tag.Upsert(keyProfile, profile)}, acceptedMatches.M(1))
+}
+
+func (es *evaluationStats) rejected(ctx context.Context, profile string, r *pb.MatchRejection, winningProfile string) {
+	reason := r.GetReason().String()
+
+	es.mu.Lock()
+	profile, p := es.track(profile)
+	winningProfile = es.tagged(winningProfile)
+	p.Rejected++
+	p.Reasons[reason]++
+	if r.GetWinningMatchId() != "" {
+		p.LostTo[winningProfile]++
+	}
+	es.mu.Unlock()
+
+	recordWithTags(ctx, []tag.Mutator{
+		tag.Upsert(keyProfile, profile),
+		tag.Upsert(keyReason, reason),
+		tag.Upsert(keyWinningProfile, winningProfile),
+	}, rejectedMatches.M(1))
+}
+
+func recordWithTags(ctx context.Context, mutators []tag.Mutator, ms ...stats.Measurement) {
+	if err := stats.RecordWithTags(ctx, mutators, ms...); err != nil {
+		logger.WithError(err).Warning("failed to record evaluation stats")
+	}
+}
+
+// ServeHTTP serves the evaluation results of each profile as JSON.
+func (es *evaluationStats) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	es.mu.Lock()
+	body, err := json.MarshalIndent(struct {
+		Profiles map[string]*profileEvaluations `json:"profiles"`
+	}{es.profiles}, "", "  ")
+	es.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(body); err != nil {
+		logger.WithError(err).Debug("failed to write evaluation stats")
+	}
+}
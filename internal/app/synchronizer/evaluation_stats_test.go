@@ -0,0 +1,116 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestEvaluationStats(t *testing.T) {
+	ctx := context.Background()
+	es := newEvaluationStats(10, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		es.evaluated(ctx, "1v1")
+	}
+	es.evaluated(ctx, "ffa")
+	es.accepted(ctx, "ffa")
+	es.accepted(ctx, "1v1")
+	es.rejected(ctx, "1v1", &pb.MatchRejection{
+		MatchId:        "a",
+		Reason:         pb.MatchRejection_TICKET_COLLISION,
+		WinningMatchId: "b",
+	}, "ffa")
+	es.rejected(ctx, "1v1", &pb.MatchRejection{
+		MatchId: "c",
+		Reason:  pb.MatchRejection_INVALID_EVALUATION_INPUT,
+	}, "")
+
+	rec := httptest.NewRecorder()
+	es.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, rejectionsEndpoint, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var got struct {
+		Profiles map[string]*profileEvaluations `json:"profiles"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Equal(t, map[string]*profileEvaluations{
+		"1v1": {
+			Evaluated: 3,
+			Accepted:  1,
+			Rejected:  2,
+			Reasons: map[string]int64{
+				"TICKET_COLLISION":         1,
+				"INVALID_EVALUATION_INPUT": 1,
+			},
+			LostTo: map[string]int64{"ffa": 1},
+		},
+		"ffa": {
+			Evaluated: 1,
+			Accepted:  1,
+			Reasons:   map[string]int64{},
+			LostTo:    map[string]int64{},
+		},
+	}, got.Profiles)
+}
+
+func TestEvaluationStatsMaxProfiles(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	es := newEvaluationStats(2, time.Hour)
+	es.now = func() time.Time { return now }
+
+	es.evaluated(ctx, "a")
+	es.evaluated(ctx, "b")
+	// There is no room left for c, so it is counted with the other profiles.
+	es.evaluated(ctx, "c")
+	es.rejected(ctx, "a", &pb.MatchRejection{
+		Reason:         pb.MatchRejection_TICKET_COLLISION,
+		WinningMatchId: "m",
+	}, "d")
+	es.rejected(ctx, "a", &pb.MatchRejection{
+		Reason:         pb.MatchRejection_TICKET_COLLISION,
+		WinningMatchId: "m",
+	}, "b")
+
+	es.mu.Lock()
+	require.Len(t, es.profiles, 3)
+	require.Equal(t, int64(1), es.profiles[otherProfiles].Evaluated)
+	require.Equal(t, map[string]int64{otherProfiles: 1, "b": 1}, es.profiles["a"].LostTo)
+	es.mu.Unlock()
+
+	// Once b hasn't had results for the ttl, it is dropped to make room for c.
+	now = now.Add(30 * time.Minute)
+	es.evaluated(ctx, "a")
+	now = now.Add(31 * time.Minute)
+	es.evaluated(ctx, "c")
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	require.Len(t, es.profiles, 3)
+	require.NotContains(t, es.profiles, "b")
+	require.Equal(t, int64(1), es.profiles["c"].Evaluated)
+	require.Equal(t, int64(2), es.profiles["a"].Evaluated)
+	require.Equal(t, map[string]int64{otherProfiles: 2}, es.profiles["a"].LostTo)
+}
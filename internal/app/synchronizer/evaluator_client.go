@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/jsonpb"
@@ -39,22 +40,36 @@ var (
 	})
 )
 
+// evaluator passes the IDs of the matches the evaluator accepts on the string
+// channel, and its explanations of matches it rejects on the MatchRejection
+// channel.
 type evaluator interface {
-	evaluate(context.Context, <-chan []*pb.Match, chan<- string) error
+	evaluate(context.Context, <-chan []*pb.EvaluateRequest, chan<- string, chan<- *pb.MatchRejection) error
 }
 
 var errNoEvaluatorType = status.Errorf(codes.FailedPrecondition, "unable to determine evaluator type, either api.evaluator.grpcport or api.evaluator.httpport must be specified in the config")
 
 func newEvaluator(cfg config.View) evaluator {
 	newInstance := func(cfg config.View) (interface{}, func(), error) {
-		// grpc is preferred over http.
-		if cfg.IsSet("api.evaluator.grpcport") {
+		switch t := cfg.GetString("api.evaluator.type"); strings.ToUpper(t) {
+		case "":
+			// grpc is preferred over http.
+			if cfg.IsSet("api.evaluator.grpcport") {
+				return newGrpcEvaluator(cfg)
+			}
+			if cfg.IsSet("api.evaluator.httpport") {
+				return newHTTPEvaluator(cfg)
+			}
+			return nil, nil, errNoEvaluatorType
+		case pb.FunctionConfig_GRPC.String():
 			return newGrpcEvaluator(cfg)
-		}
-		if cfg.IsSet("api.evaluator.httpport") {
+		case pb.FunctionConfig_REST.String():
 			return newHTTPEvaluator(cfg)
+		case pb.FunctionConfig_WEBSOCKET.String():
+			return newWebSocketEvaluator(cfg)
+		default:
+			return nil, nil, status.Errorf(codes.FailedPrecondition, "unknown api.evaluator.type %q, must be one of %s, %s or %s", t, pb.FunctionConfig_GRPC, pb.FunctionConfig_REST, pb.FunctionConfig_WEBSOCKET)
 		}
-		return nil, nil, errNoEvaluatorType
 	}
 
 	return &deferredEvaluator{
@@ -66,13 +81,13 @@ type deferredEvaluator struct {
 	cacher *config.Cacher
 }
 
-func (de *deferredEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.Match, acceptedIds chan<- string) error {
+func (de *deferredEvaluator) evaluate(ctx context.Context, pc <-chan []*pb.EvaluateRequest, acceptedIds chan<- string, rejections chan<- *pb.MatchRejection) error {
 	e, err := de.cacher.Get()
 	if err != nil {
 		return err
 	}
 
-	err = e.(evaluator).evaluate(ctx, pc, acceptedIds)
+	err = e.(evaluator).evaluate(ctx, pc, acceptedIds, rejections)
 	if err != nil {
 		de.cacher.ForceReset()
 	}
@@ -106,7 +121,7 @@ func newGrpcEvaluator(cfg config.View) (evaluator, func(), error) {
 	}, close, nil
 }
 
-func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match, acceptedIds chan<- string) error {
+func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.EvaluateRequest, acceptedIds chan<- string, rejections chan<- *pb.MatchRejection) error {
 	eg, ctx := errgroup.WithContext(ctx)
 
 	var stream pb.Evaluator_EvaluateClient
@@ -120,13 +135,12 @@ func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 
 	matchIDs := &sync.Map{}
 	eg.Go(func() error {
-		for proposals := range pc {
-			for _, proposal := range proposals {
-
-				if _, ok := matchIDs.LoadOrStore(proposal.GetMatchId(), true); ok {
-					return fmt.Errorf("multiple match functions used same match_id: \"%s\"", proposal.GetMatchId())
+		for reqs := range pc {
+			for _, req := range reqs {
+				if err := markSent(matchIDs, req.GetMatch()); err != nil {
+					return err
 				}
-				if err := stream.Send(&pb.EvaluateRequest{Match: proposal}); err != nil {
+				if err := stream.Send(req); err != nil {
 					return fmt.Errorf("failed to send request to evaluator, desc: %w", err)
 				}
 			}
@@ -148,15 +162,16 @@ func (ec *grcpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 			if err != nil {
 				return fmt.Errorf("failed to get response from evaluator client, desc: %w", err)
 			}
-
-			v, ok := matchIDs.Load(resp.GetMatchId())
-			if !ok {
-				return fmt.Errorf("evaluator returned match_id \"%s\" which does not correspond to its any match in its input", resp.GetMatchId())
+			if resp.GetRejection() != nil {
+				if err := sendRejection(ctx, matchIDs, resp.GetRejection(), rejections); err != nil {
+					return err
+				}
+				continue
 			}
-			if !v.(bool) {
-				return fmt.Errorf("evaluator returned same match_id twice: \"%s\"", resp.GetMatchId())
+
+			if err := markAccepted(matchIDs, resp.GetMatchId()); err != nil {
+				return err
 			}
-			matchIDs.Store(resp.GetMatchId(), false)
 			acceptedIds <- resp.GetMatchId()
 		}
 	})
@@ -194,11 +209,12 @@ func newHTTPEvaluator(cfg config.View) (evaluator, func(), error) {
 	}, close, nil
 }
 
-func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Match, acceptedIds chan<- string) error {
+func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.EvaluateRequest, acceptedIds chan<- string, rejections chan<- *pb.MatchRejection) error {
 	reqr, reqw := io.Pipe()
 	var wg sync.WaitGroup
 	wg.Add(1)
 
+	matchIDs := &sync.Map{}
 	sc := make(chan error, 1)
 	defer close(sc)
 	go func() {
@@ -209,9 +225,13 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 				logger.Warning("failed to close response body read closer")
 			}
 		}()
-		for proposals := range pc {
-			for _, proposal := range proposals {
-				buf, err := m.MarshalToString(&pb.EvaluateRequest{Match: proposal})
+		for reqs := range pc {
+			for _, req := range reqs {
+				if err := markSent(matchIDs, req.GetMatch()); err != nil {
+					sc <- err
+					return
+				}
+				buf, err := m.MarshalToString(req)
 				if err != nil {
 					sc <- status.Errorf(codes.FailedPrecondition, "failed to marshal proposal to string: %s", err.Error())
 					return
@@ -251,8 +271,8 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 		dec := json.NewDecoder(resp.Body)
 		for {
 			var item struct {
-				Result json.RawMessage        `json:"result"`
-				Error  map[string]interface{} `json:"error"`
+				Result json.RawMessage `json:"result"`
+				Error  json.RawMessage `json:"error"`
 			}
 			err := dec.Decode(&item)
 			if err == io.EOF {
@@ -263,7 +283,7 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 				return
 			}
 			if len(item.Error) != 0 {
-				rc <- status.Errorf(codes.Unavailable, "failed to execute evaluator.Evaluate: %v", item.Error)
+				rc <- rpc.StatusFromJSON(item.Error)
 				return
 			}
 			resp := &pb.EvaluateResponse{}
@@ -271,7 +291,23 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 				rc <- status.Errorf(codes.Unavailable, "failed to execute jsonpb.UnmarshalString(%s, &proposal): %v.", item.Result, err)
 				return
 			}
-			acceptedIds <- resp.GetMatchId()
+			if resp.GetRejection() != nil {
+				if err := sendRejection(ctx, matchIDs, resp.GetRejection(), rejections); err != nil {
+					rc <- err
+					return
+				}
+				continue
+			}
+			if err := markAccepted(matchIDs, resp.GetMatchId()); err != nil {
+				rc <- err
+				return
+			}
+			select {
+			case acceptedIds <- resp.GetMatchId():
+			case <-ctx.Done():
+				rc <- ctx.Err()
+				return
+			}
 		}
 	}()
 
@@ -284,3 +320,153 @@ func (ec *httpEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.Mat
 	}
 	return nil
 }
+
+type webSocketEvaluatorClient struct {
+	cfg     config.View
+	address string
+}
+
+func newWebSocketEvaluator(cfg config.View) (evaluator, func(), error) {
+	// The websocket is served on the evaluator's HTTP port.
+	address := fmt.Sprintf("%s:%d", cfg.GetString("api.evaluator.hostname"), cfg.GetInt64("api.evaluator.httpport"))
+
+	evaluatorClientLogger.WithFields(logrus.Fields{
+		"endpoint": address,
+	}).Info("Created a websocket client for evaluator endpoint.")
+
+	// Each evaluation opens its own websocket, so there is nothing to close.
+	return &webSocketEvaluatorClient{
+		cfg:     cfg,
+		address: address,
+	}, func() {}, nil
+}
+
+func (ec *webSocketEvaluatorClient) evaluate(ctx context.Context, pc <-chan []*pb.EvaluateRequest, acceptedIds chan<- string, rejections chan<- *pb.MatchRejection) error {
+	stream, err := rpc.DialWebSocket(ctx, ec.cfg, ec.address, "/v1/evaluator/matches:evaluate")
+	if err != nil {
+		return fmt.Errorf("error starting evaluator call: %w", err)
+	}
+	defer func() {
+		if err := stream.Close(); err != nil {
+			evaluatorClientLogger.WithError(err).Debug("failed to close evaluator websocket")
+		}
+	}()
+
+	matchIDs := &sync.Map{}
+
+	// inputErr is a problem with the proposals themselves, which takes
+	// precedence over whatever the evaluator makes of the broken stream.
+	// sendErr is only reported if the evaluator didn't fail the call itself.
+	var inputErr, sendErr error
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for reqs := range pc {
+			for _, req := range reqs {
+				if inputErr != nil || sendErr != nil {
+					continue
+				}
+				if err := markSent(matchIDs, req.GetMatch()); err != nil {
+					inputErr = err
+					// Stop the evaluator, its results can't be used anymore.
+					_ = stream.Close()
+					continue
+				}
+				if err := stream.Send(req); err != nil {
+					sendErr = fmt.Errorf("failed to send request to evaluator, desc: %w", err)
+				}
+			}
+		}
+
+		if inputErr == nil && sendErr == nil {
+			if err := stream.CloseSend(); err != nil {
+				sendErr = fmt.Errorf("failed to close the send direction of evaluator stream, desc: %w", err)
+			}
+		}
+	}()
+
+	recvErr := func() error {
+		for {
+			resp := &pb.EvaluateResponse{}
+			err := stream.Recv(resp)
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get response from evaluator client, desc: %w", err)
+			}
+			if resp.GetRejection() != nil {
+				if err := sendRejection(ctx, matchIDs, resp.GetRejection(), rejections); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := markAccepted(matchIDs, resp.GetMatchId()); err != nil {
+				return err
+			}
+
+			select {
+			case acceptedIds <- resp.GetMatchId():
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}()
+	if recvErr != nil {
+		// Fail any further sends quickly, proposals are still drained from pc.
+		_ = stream.Close()
+	}
+
+	<-sent
+	if inputErr != nil {
+		return inputErr
+	}
+	if recvErr != nil {
+		return recvErr
+	}
+	return sendErr
+}
+
+// markSent records that the proposal is sent to the evaluator, failing if
+// another proposal used the same match_id.
+func markSent(matchIDs *sync.Map, proposal *pb.Match) error {
+	if _, ok := matchIDs.LoadOrStore(proposal.GetMatchId(), true); ok {
+		return fmt.Errorf("multiple match functions used same match_id: \"%s\"", proposal.GetMatchId())
+	}
+	return nil
+}
+
+// markAccepted records that the evaluator accepted the match, failing if it
+// wasn't sent to the evaluator or was already accepted.
+func markAccepted(matchIDs *sync.Map, matchID string) error {
+	v, ok := matchIDs.Load(matchID)
+	if !ok {
+		return fmt.Errorf("evaluator returned match_id \"%s\" which does not correspond to its any match in its input", matchID)
+	}
+	if !v.(bool) {
+		return fmt.Errorf("evaluator returned same match_id twice: \"%s\"", matchID)
+	}
+	matchIDs.Store(matchID, false)
+	return nil
+}
+
+// sendRejection passes on the evaluator's explanation of a rejected match.
+// Explanations are informational, so ones for matches which weren't sent to
+// the evaluator, or which it accepted, are dropped rather than failing the
+// cycle.
+func sendRejection(ctx context.Context, matchIDs *sync.Map, r *pb.MatchRejection, rejections chan<- *pb.MatchRejection) error {
+	if v, ok := matchIDs.Load(r.GetMatchId()); !ok || !v.(bool) {
+		evaluatorClientLogger.WithFields(logrus.Fields{
+			"matchId": r.GetMatchId(),
+		}).Warning("evaluator explained the rejection of a match it wasn't sent or accepted, ignoring it")
+		return nil
+	}
+
+	select {
+	case rejections <- r:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestHTTPEvaluatorMatchIDs(t *testing.T) {
+	tests := []struct {
+		name     string
+		sent     []string
+		returned []string
+		wantErr  string
+	}{
+		{name: "accepted", sent: []string{"a", "b"}, returned: []string{"b"}},
+		{name: "unknown", sent: []string{"a"}, returned: []string{"c"}, wantErr: `evaluator returned match_id "c" which does not correspond to its any match in its input`},
+		{name: "twice", sent: []string{"a"}, returned: []string{"a", "a"}, wantErr: `evaluator returned same match_id twice: "a"`},
+		{name: "same input", sent: []string{"a", "a"}, wantErr: `multiple match functions used same match_id: "a"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				for _, id := range tt.returned {
+					fmt.Fprintf(w, `{"result":{"matchId":%q}}`+"\n", id)
+				}
+			}))
+			defer server.Close()
+			ec := &httpEvaluatorClient{httpClient: server.Client(), baseURL: server.URL}
+
+			pc := make(chan []*pb.EvaluateRequest, 1)
+			var reqs []*pb.EvaluateRequest
+			for _, id := range tt.sent {
+				reqs = append(reqs, &pb.EvaluateRequest{Match: &pb.Match{MatchId: id}})
+			}
+			pc <- reqs
+			close(pc)
+
+			accepted := make(chan string, len(tt.returned))
+			err := ec.evaluate(context.Background(), pc, accepted, make(chan *pb.MatchRejection, 1))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			close(accepted)
+			var got []string
+			for id := range accepted {
+				got = append(got, id)
+			}
+			require.Equal(t, tt.returned, got)
+		})
+	}
+}
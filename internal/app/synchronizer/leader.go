@@ -0,0 +1,285 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/appmain/contextcause"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/statestore"
+)
+
+const (
+	// leaseDurationName configures how long the leading replica holds its
+	// lease without renewing it.  Unset disables leader election, and every
+	// replica accepts synchronize calls.
+	leaseDurationName = "synchronizerLeaseDuration"
+	// partitionName configures the ticket partition owned by a synchronizer
+	// shard, empty for the default synchronizer.
+	partitionName = "synchronizerPartition"
+)
+
+// leaderElector keeps the synchronizer lease for this replica while it can,
+// so that only one replica of a synchronizer runs cycles at a time and the
+// others stand by to take over.
+type leaderElector struct {
+	store statestore.Service
+	lease string
+	// id is the address the backend reaches this replica on.
+	id  string
+	ttl time.Duration
+
+	m      sync.Mutex
+	expiry time.Time
+	// expire ends the term once the lease expires without being renewed.
+	expire *time.Timer
+	// term is canceled when this replica stops leading, fencing the cycles
+	// started during it.  It is nil while not leading.
+	term    context.Context
+	endTerm contextcause.CancelErrFunc
+	// draining is set once stepping down, so that no cycle starts while the
+	// running ones finish.
+	draining bool
+	running  int
+	drained  chan struct{}
+}
+
+var (
+	errLostLease = status.Error(codes.Unavailable, "synchronizer replica lost its lease")
+	errNotLeader = status.Error(codes.Unavailable, "synchronizer replica is not the leader")
+)
+
+// newLeaderElector returns the elector configured by synchronizerLeaseDuration,
+// or nil if leader election is disabled.
+func newLeaderElector(cfg config.View, store statestore.Service) *leaderElector {
+	ttl := cfg.GetDuration(leaseDurationName)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &leaderElector{
+		store: store,
+		lease: statestore.SynchronizerLease(cfg.GetString(partitionName)),
+		id:    net.JoinHostPort(advertisedHost(), strconv.Itoa(cfg.GetInt("api.synchronizer.grpcport"))),
+		ttl:   ttl,
+	}
+}
+
+// advertisedHost is the pod IP when running in Kubernetes, and the hostname
+// otherwise.
+func advertisedHost() string {
+	if ip := os.Getenv("POD_IP"); ip != "" {
+		return ip
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		logger.WithError(err).Warning("failed to get hostname for the synchronizer lease")
+		return "localhost"
+	}
+	return host
+}
+
+// run renews the lease three times per ttl until ctx is done, then steps down.
+func (le *leaderElector) run(ctx context.Context) {
+	ticker := time.NewTicker(le.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		le.renew(ctx)
+		select {
+		case <-ctx.Done():
+			le.stepDown(ticker.C)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (le *leaderElector) renew(ctx context.Context) {
+	st := time.Now()
+	acquired, err := le.store.AcquireLease(ctx, le.lease, le.id, le.ttl)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.WithError(err).Warning("failed to renew the synchronizer lease")
+		}
+		return
+	}
+
+	le.m.Lock()
+	defer le.m.Unlock()
+	// The lease may expire before the reply arrived, so it counts from the
+	// request.
+	wasLeader := st.Before(le.expiry)
+	if acquired {
+		le.expiry = st.Add(le.ttl)
+		if le.term == nil {
+			le.term, le.endTerm = contextcause.WithCancelCause(context.Background())
+		}
+		if le.expire == nil {
+			le.expire = time.AfterFunc(time.Until(le.expiry), le.expired)
+		} else {
+			le.expire.Reset(time.Until(le.expiry))
+		}
+	} else {
+		le.stopLeading(errLostLease)
+	}
+
+	if acquired != wasLeader {
+		logger.WithFields(logrus.Fields{
+			"lease":  le.lease,
+			"id":     le.id,
+			"leader": acquired,
+		}).Info("synchronizer leadership changed")
+	}
+}
+
+// expired ends the term if the lease wasn't renewed in time, as another
+// replica may acquire it from now on.
+func (le *leaderElector) expired() {
+	le.m.Lock()
+	defer le.m.Unlock()
+	if le.term != nil && !time.Now().Before(le.expiry) {
+		logger.WithField("lease", le.lease).Warning("synchronizer lease expired without being renewed")
+		le.stopLeading(errLostLease)
+	}
+}
+
+// stopLeading cancels the term with err, failing the cycles still running.
+// le.m must be held.
+func (le *leaderElector) stopLeading(err error) {
+	le.expiry = time.Time{}
+	if le.expire != nil {
+		le.expire.Stop()
+	}
+	if le.term != nil {
+		le.endTerm(err)
+		le.term = nil
+	}
+}
+
+// stepDown stops starting cycles, and keeps renewing the lease while the
+// running cycles finish, for up to a ttl.  Cycles still running then are
+// fenced, and given another ttl to stop, during which the lease expires.
+// Finally the lease is released so that a standby replica can take over
+// without waiting for it to expire.
+func (le *leaderElector) stepDown(renew <-chan time.Time) {
+	drained := le.drain()
+	timeout := time.NewTimer(le.ttl)
+	defer timeout.Stop()
+
+Drain:
+	for {
+		select {
+		case <-drained:
+			break Drain
+		case <-renew:
+			le.renew(context.Background())
+		case <-timeout.C:
+			logger.WithField("lease", le.lease).Warning("synchronizer cycles didn't finish before stepping down, failing them")
+			le.m.Lock()
+			le.stopLeading(errLostLease)
+			le.m.Unlock()
+			timeout.Reset(le.ttl)
+			select {
+			case <-drained:
+			case <-timeout.C:
+			}
+			break Drain
+		}
+	}
+	le.release()
+}
+
+// drain marks the elector as stepping down, returning a channel closed once
+// no cycle is running.
+func (le *leaderElector) drain() <-chan struct{} {
+	le.m.Lock()
+	defer le.m.Unlock()
+	le.draining = true
+	le.drained = make(chan struct{})
+	if le.running == 0 {
+		close(le.drained)
+	}
+	return le.drained
+}
+
+func (le *leaderElector) release() {
+	le.m.Lock()
+	le.stopLeading(errLostLease)
+	le.m.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), le.ttl)
+	defer cancel()
+	if err := le.store.ReleaseLease(ctx, le.lease, le.id); err != nil {
+		logger.WithError(err).Warning("failed to release the synchronizer lease")
+	}
+}
+
+// startCycle returns the context to run a cycle in, which is canceled with an
+// Unavailable error once this replica stops leading, and a func to call when
+// the cycle is over.  The context is already canceled if this replica isn't
+// leading.  Without leader election the context is never canceled.
+func (le *leaderElector) startCycle() (context.Context, func()) {
+	if le == nil {
+		return context.Background(), func() {}
+	}
+	le.m.Lock()
+	defer le.m.Unlock()
+
+	if le.term == nil || le.draining {
+		ctx, cancel := contextcause.WithCancelCause(context.Background())
+		cancel(errNotLeader)
+		return ctx, func() {}
+	}
+
+	le.running++
+	return le.term, func() {
+		le.m.Lock()
+		defer le.m.Unlock()
+		le.running--
+		if le.running == 0 && le.draining {
+			close(le.drained)
+		}
+	}
+}
+
+// isLeader returns whether this replica should run cycles, which is always
+// the case without leader election.
+func (le *leaderElector) isLeader() bool {
+	if le == nil {
+		return true
+	}
+	le.m.Lock()
+	defer le.m.Unlock()
+	return !le.draining && time.Now().Before(le.expiry)
+}
+
+// holder returns the id this replica holds the lease under, or "" without
+// leader election.
+func (le *leaderElector) holder() string {
+	if le == nil {
+		return ""
+	}
+	return le.id
+}
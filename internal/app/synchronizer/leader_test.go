@@ -0,0 +1,150 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/statestore"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+)
+
+func TestLeaderElection(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := context.Background()
+
+	// Without a lease duration every replica leads.
+	require.Nil(t, newLeaderElector(cfg, store))
+	require.True(t, (*leaderElector)(nil).isLeader())
+
+	cfg.Set(leaseDurationName, time.Minute)
+	cfg.Set(partitionName, "eu")
+	a := newLeaderElector(cfg, store)
+	require.Equal(t, statestore.SynchronizerLease("eu"), a.lease)
+	b := &leaderElector{store: store, lease: a.lease, id: "standby:50506", ttl: a.ttl}
+
+	require.False(t, a.isLeader())
+	a.renew(ctx)
+	b.renew(ctx)
+	require.True(t, a.isLeader())
+	require.False(t, b.isLeader())
+
+	holder, err := store.GetLeaseHolder(ctx, a.lease)
+	require.NoError(t, err)
+	require.Equal(t, a.id, holder)
+
+	// The standby takes over once the leader steps down.
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		a.run(runCtx)
+		close(done)
+	}()
+	cancel()
+	<-done
+	require.False(t, a.isLeader())
+
+	b.renew(ctx)
+	require.True(t, b.isLeader())
+	a.renew(ctx)
+	require.False(t, a.isLeader())
+}
+
+func TestLeaderFencesCycles(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := context.Background()
+
+	// Without leader election cycles are never fenced.
+	cycleCtx, endCycle := (*leaderElector)(nil).startCycle()
+	require.NoError(t, cycleCtx.Err())
+	endCycle()
+
+	lease := statestore.SynchronizerLease("")
+	a := &leaderElector{store: store, lease: lease, id: "a:50506", ttl: time.Minute}
+	b := &leaderElector{store: store, lease: lease, id: "b:50506", ttl: time.Minute}
+	a.renew(ctx)
+	b.renew(ctx)
+
+	// A standby's cycles fail straight away.
+	cycleCtx, endCycle = b.startCycle()
+	require.Equal(t, codes.Unavailable, status.Code(cycleCtx.Err()))
+	endCycle()
+
+	// The leader's cycles fail once it finds out it lost the lease.
+	cycleCtx, endCycle = a.startCycle()
+	defer endCycle()
+	require.NoError(t, cycleCtx.Err())
+	require.NoError(t, store.ReleaseLease(ctx, lease, a.id))
+	b.renew(ctx)
+	a.renew(ctx)
+	<-cycleCtx.Done()
+	require.Equal(t, errLostLease, cycleCtx.Err())
+
+	// Or once the lease expires without being renewed.
+	c := &leaderElector{store: store, lease: statestore.SynchronizerLease("eu"), id: "c:50506", ttl: 50 * time.Millisecond}
+	c.renew(ctx)
+	cycleCtx, endCycle = c.startCycle()
+	defer endCycle()
+	require.NoError(t, cycleCtx.Err())
+	<-cycleCtx.Done()
+	require.Equal(t, errLostLease, cycleCtx.Err())
+	require.False(t, c.isLeader())
+}
+
+func TestLeaderStepDownDrainsCycles(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := context.Background()
+
+	lease := statestore.SynchronizerLease("")
+	a := &leaderElector{store: store, lease: lease, id: "a:50506", ttl: time.Minute}
+	a.renew(ctx)
+	cycleCtx, endCycle := a.startCycle()
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		a.run(runCtx)
+		close(done)
+	}()
+	cancel()
+
+	// No cycle starts while stepping down, but the running one keeps the lease.
+	require.Eventually(t, func() bool { return !a.isLeader() }, time.Second, time.Millisecond)
+	next, _ := a.startCycle()
+	require.Equal(t, errNotLeader, next.Err())
+	holder, err := store.GetLeaseHolder(ctx, lease)
+	require.NoError(t, err)
+	require.Equal(t, a.id, holder)
+	require.NoError(t, cycleCtx.Err())
+
+	// The lease is released once the cycle is over.
+	endCycle()
+	<-done
+	holder, err = store.GetLeaseHolder(ctx, lease)
+	require.NoError(t, err)
+	require.Equal(t, "", holder)
+}
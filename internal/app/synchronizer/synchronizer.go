@@ -15,8 +15,11 @@
 package synchronizer
 
 import (
+	"context"
+
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/appmain"
 	"open-match.dev/open-match/internal/ipb"
@@ -27,7 +30,15 @@ import (
 var (
 	iterationLatency        = stats.Float64("open-match.dev/synchronizer/iteration_latency", "Time elapsed of each synchronizer iteration", stats.UnitMilliseconds)
 	registrationWaitTime    = stats.Float64("open-match.dev/synchronizer/registration_wait_time", "Time elapsed of registration wait time", stats.UnitMilliseconds)
+	registrationWindowTime  = stats.Float64("open-match.dev/synchronizer/registration_window_time", "Time elapsed of each registration window", stats.UnitMilliseconds)
 	registrationMMFDoneTime = stats.Float64("open-match.dev/synchronizer/registration_mmf_done_time", "Time elapsed wasted in registration window with done MMFs", stats.UnitMilliseconds)
+	evaluatedMatches        = stats.Int64("open-match.dev/synchronizer/evaluated_matches", "Number of matches sent to the evaluator", stats.UnitDimensionless)
+	acceptedMatches         = stats.Int64("open-match.dev/synchronizer/accepted_matches", "Number of matches accepted by the evaluator", stats.UnitDimensionless)
+	rejectedMatches         = stats.Int64("open-match.dev/synchronizer/rejected_matches", "Number of matches the evaluator explained rejecting", stats.UnitDimensionless)
+
+	keyProfile        = tag.MustNewKey("profile")
+	keyReason         = tag.MustNewKey("reason")
+	keyWinningProfile = tag.MustNewKey("winning_profile")
 
 	iterationLatencyView = &view.View{
 		Measure:     iterationLatency,
@@ -41,26 +52,84 @@ var (
 		Description: "Time elapsed of registration wait time",
 		Aggregation: telemetry.DefaultMillisecondsDistribution,
 	}
+	registrationWindowTimeView = &view.View{
+		Measure:     registrationWindowTime,
+		Name:        "open-match.dev/synchronizer/registration_window_time",
+		Description: "Time elapsed of each registration window",
+		Aggregation: telemetry.DefaultMillisecondsDistribution,
+	}
 	registrationMMFDoneTimeView = &view.View{
 		Measure:     registrationMMFDoneTime,
 		Name:        "open-match.dev/synchronizer/registration_mmf_done_time",
 		Description: "Time elapsed wasted in registration window with done MMFs",
 		Aggregation: telemetry.DefaultMillisecondsDistribution,
 	}
+	evaluatedMatchesView = &view.View{
+		Measure:     evaluatedMatches,
+		Name:        "open-match.dev/synchronizer/evaluated_matches",
+		Description: "Number of matches sent to the evaluator",
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{keyProfile},
+	}
+	acceptedMatchesView = &view.View{
+		Measure:     acceptedMatches,
+		Name:        "open-match.dev/synchronizer/accepted_matches",
+		Description: "Number of matches accepted by the evaluator",
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{keyProfile},
+	}
+	rejectedMatchesView = &view.View{
+		Measure:     rejectedMatches,
+		Name:        "open-match.dev/synchronizer/rejected_matches",
+		Description: "Number of matches the evaluator explained rejecting",
+		Aggregation: view.Sum(),
+		TagKeys:     []tag.Key{keyProfile, keyReason, keyWinningProfile},
+	}
 )
 
 // BindService creates the synchronizer service and binds it to the serving harness.
 func BindService(p *appmain.Params, b *appmain.Bindings) error {
 	store := statestore.New(p.Config())
 	service := newSynchronizerService(p.Config(), newEvaluator(p.Config()), store)
+	if service.leader = newLeaderElector(p.Config(), store); service.leader != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			service.leader.run(ctx)
+			close(done)
+		}()
+		b.AddCloser(func() {
+			cancel()
+			<-done
+		})
+	}
+	if interval := tentativeAssignmentCleanupInterval(p.Config()); interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			service.cleanupTentativeAssignments(ctx, interval)
+			close(done)
+		}()
+		b.AddCloser(func() {
+			cancel()
+			<-done
+		})
+	}
 	b.AddHealthCheckFunc(store.HealthCheck)
 	b.AddHandleFunc(func(s *grpc.Server) {
 		ipb.RegisterSynchronizerServer(s, service)
 	}, nil)
+	b.TelemetryHandle(rejectionsEndpoint, service.evaluations)
+	b.TelemetryHandle(cyclesEndpoint, service.cycles)
 	b.RegisterViews(
 		iterationLatencyView,
 		registrationWaitTimeView,
+		registrationWindowTimeView,
 		registrationMMFDoneTimeView,
+		evaluatedMatchesView,
+		acceptedMatchesView,
+		rejectedMatchesView,
+		statestore.BackfillEventsView,
 	)
 	return nil
 }
@@ -16,6 +16,7 @@ package synchronizer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -24,6 +25,8 @@ import (
 	"go.opencensus.io/stats"
 
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"open-match.dev/open-match/internal/appmain/contextcause"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/ipb"
@@ -36,6 +39,9 @@ var (
 		"app":       "openmatch",
 		"component": "app.synchronizer",
 	})
+	// errCallersDone cancels a cycle once every synchronize call registered in
+	// it returned, which is how cycles normally end.
+	errCallersDone = errors.New("canceled because all callers were done")
 )
 
 // Matches flow through channels in the synchronizer.  Channel variable names
@@ -51,7 +57,7 @@ var (
 //   -> m2c ->
 // remember return channel m7c for match | fanInFanOut
 //   -> m3c ->
-// set mappings from matchIDs to ticketIDs| cacheMatchIDToTicketIDs
+// set mappings from matchIDs to matches | cacheMatches
 //   -> m4c -> (buffered)
 // send to evaluator                     | wrapEvaluator
 //   -> m5c -> (buffered)
@@ -62,9 +68,14 @@ var (
 // return to backend                     | Synchronize
 
 type synchronizerService struct {
-	cfg   config.View
-	store statestore.Service
-	eval  evaluator
+	cfg         config.View
+	store       statestore.Service
+	eval        evaluator
+	evaluations *evaluationStats
+	cycles      *cycleHistory
+	// leader is nil when every replica accepts synchronize calls.
+	leader *leaderElector
+	clock  clock
 
 	synchronizeRegistration chan *registrationRequest
 
@@ -75,9 +86,12 @@ type synchronizerService struct {
 
 func newSynchronizerService(cfg config.View, eval evaluator, store statestore.Service) *synchronizerService {
 	s := &synchronizerService{
-		cfg:   cfg,
-		store: store,
-		eval:  eval,
+		cfg:         cfg,
+		store:       store,
+		eval:        eval,
+		evaluations: newEvaluationStats(evaluationStatsMaxProfiles(cfg), evaluationStatsTTL(cfg)),
+		cycles:      newCycleHistory(cycleHistorySize(cfg)),
+		clock:       realClock{},
 
 		synchronizeRegistration: make(chan *registrationRequest),
 		startCycle:              make(chan struct{}, 1),
@@ -94,6 +108,12 @@ func (s *synchronizerService) Synchronize(stream ipb.Synchronizer_SynchronizeSer
 	// 1. Receive proposals from backend, send them to cycle.
 	// 2. Receive matches and signals from cycle, send them to backend.
 
+	// Backends call the replica holding the lease, so this only rejects calls
+	// racing a change of leader.
+	if !s.leader.isLeader() {
+		return errNotLeader
+	}
+
 	registration := s.register(stream.Context())
 	m6cBuffer := bufferStringChannel(registration.m7c)
 	defer func() {
@@ -113,7 +133,7 @@ func (s *synchronizerService) Synchronize(stream ipb.Synchronizer_SynchronizeSer
 				registration.allM1cSent.Done()
 				return
 			}
-			registration.m1c.send(mAndM7c{m: req.Proposal, m7c: registration.m7c})
+			registration.m1c.send(mAndM7c{m: req.Proposal, priority: req.GetPriority(), m7c: registration.m7c})
 		}
 	}()
 
@@ -190,7 +210,9 @@ func (s synchronizerService) register(ctx context.Context) *registration {
 	}
 
 	st := time.Now()
-	defer stats.Record(ctx, registrationWaitTime.M(float64(time.Since(st))/float64(time.Millisecond)))
+	defer func() {
+		stats.Record(ctx, registrationWaitTime.M(float64(time.Since(st))/float64(time.Millisecond)))
+	}()
 	for {
 		select {
 		case s.synchronizeRegistration <- req:
@@ -211,10 +233,25 @@ func (s *synchronizerService) runCycle() {
 	cst := time.Now()
 	/////////////////////////////////////// Initialize cycle
 	ctx, cancel := contextcause.WithCancelCause(context.Background())
+	cycle := s.cycles.start()
+
+	// The cycle is fenced by the leader's term: losing the lease fails its
+	// registrations and stops its writes, as another replica may now run cycles.
+	term, endCycle := s.leader.startCycle()
+	defer endCycle()
+	cycleOver := make(chan struct{})
+	defer close(cycleOver)
+	go func() {
+		select {
+		case <-term.Done():
+			cancel(term.Err())
+		case <-cycleOver:
+		}
+	}()
 
 	m2c := make(chan mAndM7c)
-	m3c := make(chan *pb.Match)
-	m4c := make(chan *pb.Match)
+	m3c := make(chan *pb.EvaluateRequest)
+	m4c := make(chan *pb.EvaluateRequest)
 	m5c := make(chan string)
 	m6c := make(chan string)
 
@@ -236,19 +273,23 @@ func (s *synchronizerService) runCycle() {
 		}
 	}()
 
-	matchTickets := &sync.Map{}
-	go s.cacheMatchIDToTicketIDs(matchTickets, m3c, m4c)
-	go s.wrapEvaluator(ctx, cancel, bufferMatchChannel(m4c), m5c)
+	matches := &sync.Map{}
+	go s.cacheMatches(ctx, cycle, matches, m3c, m4c)
+	go s.wrapEvaluator(ctx, cancel, cycle, matches, bufferMatchChannel(m4c), m5c)
 	go func() {
-		s.addMatchesToPendingRelease(ctx, matchTickets, cancel, bufferStringChannel(m5c), m6c)
+		s.addMatchesToPendingRelease(ctx, cycle, matches, cancel, bufferStringChannel(m5c), m6c)
 		// Wait for pending release, but not all matches returned, the next cycle
 		// can start now.
 		close(closedOnCycleEnd)
 	}()
 
 	/////////////////////////////////////// Run Registration Period
-	rst := time.Now()
-	closeRegistration := time.After(s.registrationInterval())
+	rst := s.clock.Now()
+	closeRegistration := s.clock.After(s.registrationInterval())
+	// In adaptive mode, registration closes early once every expected director
+	// has registered, but not before the minimum interval.
+	expectedRegistrations := s.expectedRegistrations()
+	var allRegistered <-chan time.Time
 Registration:
 	for {
 		select {
@@ -264,23 +305,32 @@ Registration:
 			}
 			registrations = append(registrations, r)
 			req.resp <- r
+			cycle.registered()
+			if expectedRegistrations > 0 && len(registrations) == expectedRegistrations {
+				allRegistered = s.clock.After(s.minRegistrationInterval() - s.clock.Now().Sub(rst))
+			}
+		case <-allRegistered:
+			break Registration
 		case <-closeRegistration:
 			break Registration
 		}
 	}
+	registrationWindow := s.clock.Now().Sub(rst)
+	cycle.closeRegistration()
+	stats.Record(ctx, registrationWindowTime.M(float64(registrationWindow/time.Millisecond)))
 	/////////////////////////////////////// Wait for cycle completion.
 
 	go func() {
 		for _, ctx := range callingCtx {
 			<-ctx.Done()
 		}
-		cancel(fmt.Errorf("canceled because all callers were done"))
+		cancel(errCallersDone)
 	}()
 
 	go func() {
 		allM1cSent.Wait()
 		m1c.cutoff()
-		stats.Record(ctx, registrationMMFDoneTime.M(float64((s.registrationInterval()-time.Since(rst))/time.Millisecond)))
+		stats.Record(ctx, registrationMMFDoneTime.M(float64((s.clock.Now().Sub(rst)-registrationWindow)/time.Millisecond)))
 	}()
 
 	cancelProposalCollection := time.AfterFunc(s.proposalCollectionInterval(), func() {
@@ -291,6 +341,12 @@ Registration:
 	})
 
 	<-closedOnCycleEnd
+	// Only cycles which ended abnormally have a cancel cause in the history.
+	cause := ctx.Err()
+	if errors.Is(cause, errCallersDone) {
+		cause = nil
+	}
+	cycle.finish(cause)
 
 	stats.Record(ctx, iterationLatency.M(float64(time.Since(cst)/time.Millisecond)))
 
@@ -301,14 +357,53 @@ Registration:
 	if err != nil {
 		logger.Errorf("Failed to clean up backfills, %s", err.Error())
 	}
+
+	err = s.store.CleanupTentativeAssignments(ctx)
+	if err != nil {
+		logger.Errorf("Failed to clean up tentative assignments, %s", err.Error())
+	}
+
+	// Ticket reservations last for a number of cycles of the synchronizer owning
+	// their partition.  Only the leader's cycles count, so a replica which lost
+	// its lease doesn't advance the cycle too.  The cycle's context may already
+	// be canceled by its callers finishing, but the cycle still counts.
+	err = s.store.AdvanceReservationCycle(context.Background(), s.cfg.GetString(partitionName), s.leader.holder())
+	if status.Code(err) == codes.FailedPrecondition {
+		logger.Warningf("Not advancing the ticket reservation cycle, %s", err.Error())
+	} else if err != nil {
+		logger.Errorf("Failed to advance the ticket reservation cycle, %s", err.Error())
+	}
+}
+
+// cleanupTentativeAssignments cancels the expired tentative assignments every
+// interval until ctx is done.  Standby replicas leave it to the leader.
+func (s *synchronizerService) cleanupTentativeAssignments(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if !s.leader.isLeader() {
+			continue
+		}
+		if err := s.store.CleanupTentativeAssignments(ctx); err != nil && ctx.Err() == nil {
+			logger.Errorf("Failed to clean up tentative assignments, %s", err.Error())
+		}
+	}
 }
 
 ///////////////////////////////////////
 ///////////////////////////////////////
 
 type mAndM7c struct {
-	m   *pb.Match
-	m7c chan string
+	m        *pb.Match
+	priority int32
+	m7c      chan string
 }
 
 // fanInFanOut routes evaluated matches back to it's source synchronize call.
@@ -316,7 +411,7 @@ type mAndM7c struct {
 // This channel is remembered in a map, and the match is passed to be evaluated.
 // When a match returns from evaluation, it's ID is looked up in the map and the
 // match is returned on that channel.
-func fanInFanOut(m2c <-chan mAndM7c, m3c chan<- *pb.Match, m6c <-chan string) {
+func fanInFanOut(m2c <-chan mAndM7c, m3c chan<- *pb.EvaluateRequest, m6c <-chan string) {
 	m7cMap := make(map[string]chan<- string)
 
 	defer func(m2c <-chan mAndM7c) {
@@ -329,7 +424,7 @@ func fanInFanOut(m2c <-chan mAndM7c, m3c chan<- *pb.Match, m6c <-chan string) {
 		case m2, ok := <-m2c:
 			if ok {
 				m7cMap[m2.m.GetMatchId()] = m2.m7c
-				m3c <- m2.m
+				m3c <- &pb.EvaluateRequest{Match: m2.m, Priority: m2.priority}
 			} else {
 				close(m3c)
 				// No longer select on m2c
@@ -407,9 +502,48 @@ func (c *cutoffSender) cutoff() {
 ///////////////////////////////////////
 ///////////////////////////////////////
 
-// Calls the evaluator with the matches.
-func (s *synchronizerService) wrapEvaluator(ctx context.Context, cancel contextcause.CancelErrFunc, m4c <-chan []*pb.Match, m5c chan<- string) {
-	err := s.eval.evaluate(ctx, m4c, m5c)
+// Calls the evaluator with the matches, and records its explanations of the
+// matches it rejects.
+func (s *synchronizerService) wrapEvaluator(ctx context.Context, cancel contextcause.CancelErrFunc, cycle *cycleRecord, m *sync.Map, m4c <-chan []*pb.EvaluateRequest, m5c chan<- string) {
+	rejections := make(chan *pb.MatchRejection)
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		for r := range rejections {
+			s.evaluations.rejected(ctx, matchProfile(m, r.GetMatchId()), r, matchProfile(m, r.GetWinningMatchId()))
+			cycle.rejectedMatch()
+		}
+	}()
+
+	// The evaluator is timed from when it receives the first batch of
+	// matches, as it waits for proposals until then.
+	received := make(chan []*pb.EvaluateRequest)
+	started := make(chan time.Time, 1)
+	go func() {
+		defer close(received)
+		timed := false
+		for batch := range m4c {
+			if !timed {
+				started <- time.Now()
+				timed = true
+			}
+			// Matches left when the cycle is canceled are dropped.
+			select {
+			case received <- batch:
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	err := s.eval.evaluate(ctx, received, m5c, rejections)
+	select {
+	case est := <-started:
+		cycle.evaluated(time.Since(est))
+	default:
+		// The evaluator had nothing to evaluate.
+	}
+	close(rejections)
+	<-recorded
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"error": err,
@@ -422,14 +556,36 @@ func (s *synchronizerService) wrapEvaluator(ctx context.Context, cancel contextc
 ///////////////////////////////////////
 ///////////////////////////////////////
 
-func (s *synchronizerService) cacheMatchIDToTicketIDs(m *sync.Map, m3c <-chan *pb.Match, m4c chan<- *pb.Match) {
-	for match := range m3c {
-		m.Store(match.GetMatchId(), getTicketIds(match.GetTickets()))
-		m4c <- match
+// cachedMatch is what the synchronizer remembers of a match while it is
+// evaluated.
+type cachedMatch struct {
+	ticketIDs []string
+	profile   string
+}
+
+func (s *synchronizerService) cacheMatches(ctx context.Context, cycle *cycleRecord, m *sync.Map, m3c <-chan *pb.EvaluateRequest, m4c chan<- *pb.EvaluateRequest) {
+	for req := range m3c {
+		match := req.GetMatch()
+		m.Store(match.GetMatchId(), &cachedMatch{
+			ticketIDs: getTicketIds(match.GetTickets()),
+			profile:   match.GetMatchProfile(),
+		})
+		s.evaluations.evaluated(ctx, match.GetMatchProfile())
+		cycle.proposed(match.GetMatchProfile())
+		m4c <- req
 	}
 	close(m4c)
 }
 
+// matchProfile returns the profile of a cached match, or "" if the match isn't
+// known.
+func matchProfile(m *sync.Map, matchID string) string {
+	if cm, ok := m.Load(matchID); ok {
+		return cm.(*cachedMatch).profile
+	}
+	return ""
+}
+
 func getTicketIds(tickets []*pb.Ticket) []string {
 	tids := []string{}
 	for _, ticket := range tickets {
@@ -445,16 +601,18 @@ func getTicketIds(tickets []*pb.Ticket) []string {
 // pendingRelease list.  If it partially fails for whatever reason (not all tickets will
 // necessarily be in the same call), only the matches which can be safely
 // returned to the Synchronize calls are.
-func (s *synchronizerService) addMatchesToPendingRelease(ctx context.Context, m *sync.Map, cancel contextcause.CancelErrFunc, m5c <-chan []string, m6c chan<- string) {
+func (s *synchronizerService) addMatchesToPendingRelease(ctx context.Context, cycle *cycleRecord, m *sync.Map, cancel contextcause.CancelErrFunc, m5c <-chan []string, m6c chan<- string) {
 	totalMatches := 0
 	successfulMatches := 0
 	var lastErr error
 	for mIDs := range m5c {
 		ids := []string{}
 		for _, mID := range mIDs {
-			tids, ok := m.Load(mID)
+			cm, ok := m.Load(mID)
 			if ok {
-				ids = append(ids, tids.([]string)...)
+				ids = append(ids, cm.(*cachedMatch).ticketIDs...)
+				s.evaluations.accepted(ctx, cm.(*cachedMatch).profile)
+				cycle.acceptedMatch()
 			} else {
 				logger.Errorf("failed to get MatchId %s with its corresponding tickets from the cache", mID)
 			}
@@ -504,6 +662,92 @@ func (s *synchronizerService) registrationInterval() time.Duration {
 	return s.cfg.GetDuration(name)
 }
 
+// expectedRegistrations is the number of FetchMatches calls expected each
+// cycle.  When set, the synchronizer runs in adaptive mode, closing the
+// registration window as soon as they have all registered.  Proposal
+// collection always closes once every registered match function is done.
+func (s *synchronizerService) expectedRegistrations() int {
+	const name = "expectedRegistrations"
+
+	if !s.cfg.IsSet(name) {
+		return 0
+	}
+
+	return s.cfg.GetInt(name)
+}
+
+// minRegistrationInterval bounds how early the registration window closes in
+// adaptive mode.
+func (s *synchronizerService) minRegistrationInterval() time.Duration {
+	const name = "minRegistrationInterval"
+
+	if !s.cfg.IsSet(name) {
+		return 0
+	}
+
+	return s.cfg.GetDuration(name)
+}
+
+// tentativeAssignmentCleanupInterval is how often tentative assignments past
+// their acceptance timeout are canceled, besides after every cycle, so that
+// they are canceled while no cycle runs too.  0 disables it.
+func tentativeAssignmentCleanupInterval(cfg config.View) time.Duration {
+	const (
+		name            = "tentativeAssignmentCleanupInterval"
+		defaultInterval = 5 * time.Second
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultInterval
+	}
+
+	return cfg.GetDuration(name)
+}
+
+// cycleHistorySize is how many finished cycles are listed on the cycles page.
+func cycleHistorySize(cfg config.View) int {
+	const (
+		name        = "cycleHistorySize"
+		defaultSize = 10
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultSize
+	}
+
+	return cfg.GetInt(name)
+}
+
+// evaluationStatsMaxProfiles is how many profiles evaluation results are
+// tracked for, on the /rejectionz page and as metric tags.
+func evaluationStatsMaxProfiles(cfg config.View) int {
+	const (
+		name               = "evaluationStatsMaxProfiles"
+		defaultMaxProfiles = 100
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultMaxProfiles
+	}
+
+	return cfg.GetInt(name)
+}
+
+// evaluationStatsTTL is how long a profile without evaluation results keeps
+// being tracked, while other profiles are waiting for room.
+func evaluationStatsTTL(cfg config.View) time.Duration {
+	const (
+		name       = "evaluationStatsTTL"
+		defaultTTL = time.Hour
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultTTL
+	}
+
+	return cfg.GetDuration(name)
+}
+
 func (s *synchronizerService) proposalCollectionInterval() time.Duration {
 	const (
 		name            = "proposalCollectionInterval"
@@ -525,10 +769,10 @@ func (s *synchronizerService) proposalCollectionInterval() time.Duration {
 // the input channel, always appending to the slice which will
 // next be used for output.  Used before external calls, so that
 // network won't back up internal processing.
-func bufferMatchChannel(in chan *pb.Match) chan []*pb.Match {
-	out := make(chan []*pb.Match)
+func bufferMatchChannel(in chan *pb.EvaluateRequest) chan []*pb.EvaluateRequest {
+	out := make(chan []*pb.EvaluateRequest)
 	go func() {
-		var a []*pb.Match
+		var a []*pb.EvaluateRequest
 
 	outerLoop:
 		for {
@@ -536,7 +780,7 @@ func bufferMatchChannel(in chan *pb.Match) chan []*pb.Match {
 			if !ok {
 				break outerLoop
 			}
-			a = []*pb.Match{m}
+			a = []*pb.EvaluateRequest{m}
 
 			for len(a) > 0 {
 				select {
@@ -0,0 +1,270 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package synchronizer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/internal/appmain/contextcause"
+	statestoreTesting "open-match.dev/open-match/internal/statestore/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+type drainingEvaluator struct{}
+
+func (drainingEvaluator) evaluate(ctx context.Context, in <-chan []*pb.EvaluateRequest, out chan<- string, rejected chan<- *pb.MatchRejection) error {
+	for range in {
+	}
+	return nil
+}
+
+// fakeClock only moves forward when advanced, firing the timers which are due.
+type fakeClock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []fakeTimer
+}
+
+type fakeTimer struct {
+	at time.Time
+	c  chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	c := &fakeClock{now: time.Unix(0, 0)}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := fakeTimer{at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+	} else {
+		c.timers = append(c.timers, t)
+	}
+	c.cond.Broadcast()
+	return t.c
+}
+
+// advance moves the clock forward by d.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.at.After(c.now) {
+			pending = append(pending, t)
+		} else {
+			t.c <- c.now
+		}
+	}
+	c.timers = pending
+}
+
+// waitForTimers blocks until n timers are pending.
+func (c *fakeClock) waitForTimers(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func TestAdaptiveRegistration(t *testing.T) {
+	const registrationInterval = time.Second
+
+	for _, test := range []struct {
+		name                    string
+		expectedRegistrations   int
+		minRegistrationInterval time.Duration
+		// Timers pending once both calls registered.
+		timers int
+		// How long after the cycle started registration closes.
+		closesAfter time.Duration
+	}{
+		{name: "fixed", timers: 1, closesAfter: registrationInterval},
+		{name: "adaptive", expectedRegistrations: 2, timers: 1, closesAfter: 0},
+		{name: "bounded", expectedRegistrations: 2, minRegistrationInterval: 300 * time.Millisecond, timers: 2, closesAfter: 300 * time.Millisecond},
+		{name: "missing director", expectedRegistrations: 3, timers: 1, closesAfter: registrationInterval},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cfg := viper.New()
+			cfg.Set("registrationInterval", registrationInterval)
+			cfg.Set("proposalCollectionInterval", 10*time.Second)
+			if test.expectedRegistrations > 0 {
+				cfg.Set("expectedRegistrations", test.expectedRegistrations)
+				cfg.Set("minRegistrationInterval", test.minRegistrationInterval)
+			}
+			store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+			defer closer()
+
+			s := newSynchronizerService(cfg, drainingEvaluator{}, store)
+			clock := newFakeClock()
+			s.clock = clock
+
+			// The calls' results only come back once registration has closed.
+			done := make(chan struct{})
+			var registered, finished sync.WaitGroup
+			for i := 0; i < 2; i++ {
+				registered.Add(1)
+				finished.Add(1)
+				go func() {
+					defer finished.Done()
+					r := s.register(context.Background())
+					registered.Done()
+					r.allM1cSent.Done()
+					for range r.m7c {
+					}
+				}()
+			}
+			go func() {
+				finished.Wait()
+				close(done)
+			}()
+			registered.Wait()
+			clock.waitForTimers(test.timers)
+
+			isDone := func() bool {
+				select {
+				case <-done:
+					return true
+				default:
+					return false
+				}
+			}
+
+			if test.closesAfter > 0 {
+				clock.advance(test.closesAfter - time.Millisecond)
+				require.False(t, isDone())
+				clock.advance(time.Millisecond)
+			}
+			require.Eventually(t, isDone, 10*time.Second, time.Millisecond)
+		})
+	}
+}
+
+// TestCycleHistoryCallersDone covers cycles ending with all their callers
+// done, which isn't recorded as a cancel cause.
+func TestCycleHistoryCallersDone(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("registrationInterval", time.Second)
+	cfg.Set("proposalCollectionInterval", 10*time.Second)
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+
+	s := newSynchronizerService(cfg, drainingEvaluator{}, store)
+	clock := newFakeClock()
+	s.clock = clock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := s.register(ctx)
+	r.allM1cSent.Done()
+	cancel()
+	clock.waitForTimers(1)
+	clock.advance(time.Second)
+	for range r.m7c {
+	}
+
+	s.cycles.mu.Lock()
+	cycle := s.cycles.cycles[0]
+	s.cycles.mu.Unlock()
+	require.Eventually(t, func() bool {
+		return cycle.view().State == "done"
+	}, 10*time.Second, time.Millisecond)
+	require.Empty(t, cycle.view().CancelCause)
+}
+
+func TestWrapEvaluatorLatency(t *testing.T) {
+	cfg := viper.New()
+	s := newSynchronizerService(cfg, drainingEvaluator{}, nil)
+	ctx, cancel := contextcause.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	cycle := s.cycles.start()
+	m4c := make(chan []*pb.EvaluateRequest)
+	m5c := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		s.wrapEvaluator(ctx, cancel, cycle, &sync.Map{}, m4c, m5c)
+		close(done)
+	}()
+
+	// Waiting for proposals doesn't count towards the evaluator's latency.
+	const collection = 200 * time.Millisecond
+	time.Sleep(collection)
+	m4c <- []*pb.EvaluateRequest{{Match: &pb.Match{MatchId: "1"}}}
+	close(m4c)
+	for range m5c {
+	}
+	<-done
+
+	latency := cycle.view().EvaluatorLatency
+	require.Greater(t, int64(latency), int64(0))
+	require.Less(t, int64(latency), int64(collection))
+}
+
+func TestCleanupTentativeAssignments(t *testing.T) {
+	cfg := viper.New()
+	store, closer := statestoreTesting.NewStoreServiceForTesting(t, cfg)
+	defer closer()
+	ctx := context.Background()
+	cfg.Set("assignmentAcceptanceTimeout", time.Millisecond)
+
+	ticket := &pb.Ticket{Id: "1"}
+	require.NoError(t, store.CreateTicket(ctx, ticket))
+	require.NoError(t, store.IndexTicket(ctx, ticket))
+	_, _, err := store.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"1"}, Assignment: &pb.Assignment{Connection: "server"}}},
+		Tentative:   true,
+	})
+	require.NoError(t, err)
+
+	// The expired assignment is canceled without any cycle running.
+	s := newSynchronizerService(cfg, drainingEvaluator{}, store)
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		s.cleanupTentativeAssignments(runCtx, 10*time.Millisecond)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := store.GetTicket(ctx, "1")
+		return status.Code(err) == codes.NotFound
+	}, 10*time.Second, 10*time.Millisecond)
+}
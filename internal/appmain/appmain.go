@@ -115,6 +115,12 @@ func (b *Bindings) AddHandleFunc(handlerFunc rpc.GrpcHandler, grpcProxyHandler r
 	b.sp.AddHandleFunc(handlerFunc, grpcProxyHandler)
 }
 
+// AddWebSocketHandler serves a streaming method over websockets on the given
+// path of the HTTP port.  See rpc.NewWebSocketHandler.
+func (b *Bindings) AddWebSocketHandler(path string, handler http.Handler) {
+	b.sp.AddWebSocketHandler(path, handler)
+}
+
 // TelemetryHandle adds a handler to the mux for serving debug info and metrics.
 func (b *Bindings) TelemetryHandle(pattern string, handler http.Handler) {
 	b.sp.ServeMux.Handle(pattern, handler)
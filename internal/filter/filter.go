@@ -44,6 +44,7 @@ type PoolFilter struct {
 	TagPresentFilters   []*pb.TagPresentFilter
 	CreatedBefore       time.Time
 	CreatedAfter        time.Time
+	MinOpenSlots        int32
 }
 
 // NewPoolFilter validates a Pool's filtering criteria and returns a PoolFilter.
@@ -71,6 +72,7 @@ func NewPoolFilter(pool *pb.Pool) (*PoolFilter, error) {
 		TagPresentFilters:   pool.GetTagPresentFilters(),
 		CreatedBefore:       cb,
 		CreatedAfter:        ca,
+		MinOpenSlots:        pool.GetMinOpenSlots(),
 	}, nil
 }
 
@@ -80,6 +82,12 @@ type filteredEntity interface {
 	GetCreateTime() *timestamppb.Timestamp
 }
 
+// capacityEntity is implemented by Backfills, which are the only entities
+// filtered on open slots.
+type capacityEntity interface {
+	GetCapacity() *pb.BackfillCapacity
+}
+
 // In returns true if the Ticket meets all the criteria for this PoolFilter.
 func (pf *PoolFilter) In(entity filteredEntity) bool {
 	s := entity.GetSearchFields()
@@ -111,6 +119,14 @@ func (pf *PoolFilter) In(entity filteredEntity) bool {
 		}
 	}
 
+	if pf.MinOpenSlots > 0 {
+		if c, ok := entity.(capacityEntity); ok {
+			if c.GetCapacity() == nil || c.GetCapacity().GetOpenSlots() < pf.MinOpenSlots {
+				return false
+			}
+		}
+	}
+
 	for _, f := range pf.DoubleRangeFilters {
 		v, ok := s.DoubleArgs[f.DoubleArg]
 		if !ok {
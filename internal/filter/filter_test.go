@@ -114,3 +114,13 @@ func TestValidPoolFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestMinOpenSlots(t *testing.T) {
+	pf, err := NewPoolFilter(&pb.Pool{MinOpenSlots: 2})
+	require.NoError(t, err)
+
+	require.True(t, pf.In(&pb.Ticket{}), "tickets are not filtered on open slots")
+	require.False(t, pf.In(&pb.Backfill{}), "backfills without capacity have no open slots")
+	require.False(t, pf.In(&pb.Backfill{Capacity: &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 1}}))
+	require.True(t, pf.In(&pb.Backfill{Capacity: &pb.BackfillCapacity{MaxSlots: 4, OpenSlots: 2}}))
+}
@@ -15,6 +15,7 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
 	"sync"
 
@@ -70,6 +71,12 @@ func (cc *ClientCache) GetHTTP(address string) (*http.Client, string, error) {
 	return c.client, c.baseURL, nil
 }
 
+// DialWebSocket opens a websocket to path on the address.  A websocket carries
+// a single call, so unlike other clients it is not cached.
+func (cc *ClientCache) DialWebSocket(ctx context.Context, address string, path string) (*WebSocketClientStream, error) {
+	return DialWebSocket(ctx, cc.cfg, address, path)
+}
+
 // NewClientCache creates a cache with all the clients.
 func NewClientCache(cfg config.View) *ClientCache {
 	return &ClientCache{
@@ -83,6 +83,7 @@ func (s *insecureServer) start(params *ServerParams) error {
 
 	s.httpMux.Handle(telemetry.HealthCheckEndpoint, telemetry.NewHealthCheck(params.handlersForHealthCheck))
 	s.httpMux.Handle("/", s.proxyMux)
+	bindWebSocketHandlers(s.httpMux, s.proxyMux, params.handlersForWebSocket)
 	s.httpServer = &http.Server{
 		Addr:    s.httpListener.Addr().String(),
 		Handler: instrumentHTTPHandler(s.httpMux, params),
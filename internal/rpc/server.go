@@ -68,6 +68,7 @@ type ServerParams struct {
 	handlersForGrpc        []GrpcHandler
 	handlersForGrpcProxy   []GrpcProxyHandler
 	handlersForHealthCheck []func(context.Context) error
+	handlersForWebSocket   []webSocketHandler
 
 	grpcListener      net.Listener
 	grpcProxyListener net.Listener
@@ -0,0 +1,337 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/internal/config"
+)
+
+// Streaming calls over websockets carry one JSON encoded message per text
+// message.  The client sends its requests as-is, followed by an empty message
+// once it has nothing more to send.  The server wraps each response as
+// {"result": ...}, the same as the JSON streams served by grpc-gateway, and
+// reports a failed call with a final {"error": ...} holding a
+// google.rpc.Status.  The server closing the connection ends the stream.
+
+var (
+	webSocketMarshaler = protojson.MarshalOptions{
+		UseProtoNames: true,
+	}
+	webSocketUnmarshaler = protojson.UnmarshalOptions{
+		DiscardUnknown: true,
+	}
+)
+
+type webSocketHandler struct {
+	path    string
+	handler http.Handler
+}
+
+// AddWebSocketHandler serves handler on path of the HTTP server for websocket
+// upgrade requests.  Other requests to path are still sent to the grpc-gateway
+// proxy, so a streaming method can be served over both at the same URL.
+func (p *ServerParams) AddWebSocketHandler(path string, handler http.Handler) {
+	if handler != nil {
+		p.handlersForWebSocket = append(p.handlersForWebSocket, webSocketHandler{path: path, handler: handler})
+	}
+}
+
+func bindWebSocketHandlers(mux *http.ServeMux, proxy http.Handler, handlers []webSocketHandler) {
+	for _, h := range handlers {
+		h := h
+		mux.HandleFunc(h.path, func(w http.ResponseWriter, req *http.Request) {
+			if isWebSocketUpgrade(req) {
+				h.handler.ServeHTTP(w, req)
+				return
+			}
+			proxy.ServeHTTP(w, req)
+		})
+	}
+}
+
+func isWebSocketUpgrade(req *http.Request) bool {
+	if !strings.EqualFold(req.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, v := range strings.Split(req.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(v), "upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebSocketHandler returns a handler which serves a streaming gRPC method
+// over websockets.  handler is the method's generated grpc.StreamDesc Handler,
+// and srv the service implementation it is called with.
+func NewWebSocketHandler(srv interface{}, handler grpc.StreamHandler) http.Handler {
+	// Using Server directly skips the Origin check of websocket.Handler, which
+	// only makes sense for browsers.
+	return websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			defer func() {
+				if err := conn.Close(); err != nil {
+					serverLogger.WithError(err).Debug("failed to close websocket")
+				}
+			}()
+
+			stream, cancel := newWebSocketServerStream(conn)
+			defer cancel()
+
+			if err := handler(srv, stream); err != nil {
+				serverLogger.Error(err)
+				if err := stream.sendError(err); err != nil {
+					serverLogger.WithError(err).Debug("failed to send error over websocket")
+				}
+			}
+		},
+	}
+}
+
+// webSocketServerStream adapts a websocket to the grpc.ServerStream used by
+// generated gRPC handlers.
+type webSocketServerStream struct {
+	ctx  context.Context
+	conn *websocket.Conn
+
+	// requests is closed once the client is done sending, after which RecvMsg
+	// returns recvErr.
+	requests chan string
+	recvErr  error
+}
+
+func newWebSocketServerStream(conn *websocket.Conn) (*webSocketServerStream, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(conn.Request().Context())
+	s := &webSocketServerStream{
+		ctx:      ctx,
+		conn:     conn,
+		requests: make(chan string),
+	}
+	go s.read(cancel)
+	return s, cancel
+}
+
+// read keeps reading from the websocket for as long as it is open, as the
+// connection going away is the only way the client can cancel the call.
+func (s *webSocketServerStream) read(cancel context.CancelFunc) {
+	defer cancel()
+
+	ended := false
+	for {
+		var msg string
+		if err := websocket.Message.Receive(s.conn, &msg); err != nil {
+			if !ended {
+				s.recvErr = status.Error(codes.Canceled, "websocket closed before the end of the requests")
+				close(s.requests)
+			}
+			return
+		}
+		if ended {
+			continue
+		}
+		if msg == "" {
+			ended = true
+			s.recvErr = io.EOF
+			close(s.requests)
+			continue
+		}
+
+		select {
+		case s.requests <- msg:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *webSocketServerStream) SetHeader(metadata.MD) error  { return nil }
+func (s *webSocketServerStream) SendHeader(metadata.MD) error { return nil }
+func (s *webSocketServerStream) SetTrailer(metadata.MD)       {}
+
+func (s *webSocketServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *webSocketServerStream) SendMsg(m interface{}) error {
+	b, err := webSocketMarshaler.Marshal(m.(proto.Message))
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal response: %s", err.Error())
+	}
+	return websocket.Message.Send(s.conn, `{"result":`+string(b)+`}`)
+}
+
+func (s *webSocketServerStream) RecvMsg(m interface{}) error {
+	var msg string
+	select {
+	case r, ok := <-s.requests:
+		if !ok {
+			return s.recvErr
+		}
+		msg = r
+	case <-s.ctx.Done():
+		return status.FromContextError(s.ctx.Err()).Err()
+	}
+
+	if err := webSocketUnmarshaler.Unmarshal([]byte(msg), m.(proto.Message)); err != nil {
+		return status.Errorf(codes.InvalidArgument, "failed to unmarshal request: %s", err.Error())
+	}
+	return nil
+}
+
+func (s *webSocketServerStream) sendError(err error) error {
+	b, mErr := webSocketMarshaler.Marshal(status.Convert(err).Proto())
+	if mErr != nil {
+		return mErr
+	}
+	return websocket.Message.Send(s.conn, `{"error":`+string(b)+`}`)
+}
+
+// WebSocketClientStream is the client side of a streaming call over a
+// websocket.
+type WebSocketClientStream struct {
+	conn *websocket.Conn
+	stop func() bool
+}
+
+// DialWebSocket opens a websocket to path on the HTTP endpoint at address.  The
+// websocket is closed once ctx is done, unblocking any pending Send or Recv.
+func DialWebSocket(ctx context.Context, cfg config.View, address string, path string) (*WebSocketClientStream, error) {
+	scheme, originScheme := "ws", "http"
+	var tlsConfig *tls.Config
+
+	// If TLS support is enabled in the config, fill in the trusted certificates for decrpting server certificate.
+	if certPath := cfg.GetString(configNameClientTrustedCertificatePath); certPath != "" {
+		trustedCertificate, err := os.ReadFile(certPath)
+		if err != nil {
+			clientLogger.WithError(err).Error("failed to read tls trusted certificate to establish a secure websocket.")
+			return nil, err
+		}
+		pool, err := trustedCertificateFromFileData(trustedCertificate)
+		if err != nil {
+			return nil, err
+		}
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			host = address
+		}
+		tlsConfig = &tls.Config{
+			ServerName: host,
+			RootCAs:    pool,
+		}
+		scheme, originScheme = "wss", "https"
+	}
+
+	wsCfg, err := websocket.NewConfig(fmt.Sprintf("%s://%s%s", scheme, address, path), fmt.Sprintf("%s://%s", originScheme, address))
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s is not a valid websocket address", address)
+	}
+	wsCfg.TlsConfig = tlsConfig
+
+	conn, err := wsCfg.DialContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, errors.Wrapf(err, "failed to open websocket to %s", wsCfg.Location)
+	}
+
+	return &WebSocketClientStream{
+		conn: conn,
+		stop: context.AfterFunc(ctx, func() {
+			_ = conn.Close()
+		}),
+	}, nil
+}
+
+// Send sends a request message.
+func (s *WebSocketClientStream) Send(m proto.Message) error {
+	b, err := webSocketMarshaler.Marshal(m)
+	if err != nil {
+		return status.Errorf(codes.FailedPrecondition, "failed to marshal request: %s", err.Error())
+	}
+	if len(b) == 0 {
+		// An empty message would be read as the end of the requests.
+		b = []byte("{}")
+	}
+	return websocket.Message.Send(s.conn, string(b))
+}
+
+// CloseSend tells the server no more requests will be sent.
+func (s *WebSocketClientStream) CloseSend() error {
+	return websocket.Message.Send(s.conn, "")
+}
+
+// Recv reads the next response into m.  It returns io.EOF once the server has
+// closed the stream, or the status error sent by the server if the call
+// failed.
+func (s *WebSocketClientStream) Recv(m proto.Message) error {
+	var msg string
+	if err := websocket.Message.Receive(s.conn, &msg); err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return status.Errorf(codes.Unavailable, "failed to read from websocket: %s", err.Error())
+	}
+
+	var item struct {
+		Result json.RawMessage `json:"result"`
+		Error  json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(msg), &item); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to read response from websocket JSON stream: %s", err.Error())
+	}
+	if len(item.Error) != 0 {
+		return StatusFromJSON(item.Error)
+	}
+	if err := webSocketUnmarshaler.Unmarshal(item.Result, m); err != nil {
+		return status.Errorf(codes.Unavailable, "failed to unmarshal response %s: %s", item.Result, err.Error())
+	}
+	return nil
+}
+
+// Close closes the websocket.
+func (s *WebSocketClientStream) Close() error {
+	s.stop()
+	return s.conn.Close()
+}
+
+// StatusFromJSON converts the JSON encoded google.rpc.Status of a failed
+// streaming call into a status error, keeping the code reported by the server.
+func StatusFromJSON(raw []byte) error {
+	st := &spb.Status{}
+	if err := webSocketUnmarshaler.Unmarshal(raw, st); err != nil || st.GetCode() == int32(codes.OK) {
+		return status.Errorf(codes.Unknown, "call failed with unreadable error: %s", raw)
+	}
+	return status.ErrorProto(st)
+}
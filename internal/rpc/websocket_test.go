@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// echoEvaluator accepts every match, failing with NotFound on a match
+// without an id.
+type echoEvaluator struct{}
+
+func (echoEvaluator) Evaluate(stream pb.Evaluator_EvaluateServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.GetMatch().GetMatchId() == "" {
+			return status.Error(codes.NotFound, "match without id")
+		}
+		err = stream.Send(&pb.EvaluateResponse{MatchId: req.GetMatch().GetMatchId()})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	require := require.New(t)
+	grpcL := MustListen()
+	httpL := MustListen()
+
+	const path = "/v1/evaluator/matches:evaluate"
+	params := NewServerParamsFromListeners(grpcL, httpL)
+	params.AddHandleFunc(func(s *grpc.Server) {
+		pb.RegisterEvaluatorServer(s, echoEvaluator{})
+	}, pb.RegisterEvaluatorHandlerFromEndpoint)
+	params.AddWebSocketHandler(path, NewWebSocketHandler(echoEvaluator{}, pb.Evaluator_ServiceDesc.Streams[0].Handler))
+	s := newInsecureServer(grpcL, httpL)
+	defer s.stop()
+	require.Nil(s.start(params))
+
+	ctx := context.Background()
+	address := fmt.Sprintf("localhost:%s", MustGetPortNumber(httpL))
+
+	stream, err := DialWebSocket(ctx, viper.New(), address, path)
+	require.Nil(err)
+	defer stream.Close()
+
+	require.Nil(stream.Send(&pb.EvaluateRequest{Match: &pb.Match{MatchId: "1"}}))
+	require.Nil(stream.Send(&pb.EvaluateRequest{Match: &pb.Match{MatchId: "2"}}))
+	require.Nil(stream.CloseSend())
+
+	for _, id := range []string{"1", "2"} {
+		resp := &pb.EvaluateResponse{}
+		require.Nil(stream.Recv(resp))
+		require.Equal(id, resp.GetMatchId())
+	}
+	require.Equal(io.EOF, stream.Recv(&pb.EvaluateResponse{}))
+
+	// Errors returned by the service keep their status.
+	stream, err = DialWebSocket(ctx, viper.New(), address, path)
+	require.Nil(err)
+	defer stream.Close()
+
+	require.Nil(stream.Send(&pb.EvaluateRequest{Match: &pb.Match{}}))
+	err = stream.Recv(&pb.EvaluateResponse{})
+	require.Equal(codes.NotFound, status.Code(err))
+	require.Contains(err.Error(), "match without id")
+
+	// Closing the context closes the websocket.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	stream, err = DialWebSocket(cancelCtx, viper.New(), address, path)
+	require.Nil(err)
+	defer stream.Close()
+
+	recvErr := make(chan error)
+	go func() {
+		recvErr <- stream.Recv(&pb.EvaluateResponse{})
+	}()
+	cancel()
+	select {
+	case err := <-recvErr:
+		require.Error(err)
+	case <-time.After(5 * time.Second):
+		require.Fail("Recv not unblocked by canceling the context")
+	}
+
+	// Requests which aren't websocket upgrades still reach the grpc-gateway.
+	httpResp, err := http.Post("http://"+address+path, "application/json", strings.NewReader(`{"match": {"match_id": "3"}}`))
+	require.Nil(err)
+	defer httpResp.Body.Close()
+	body, err := ioutil.ReadAll(httpResp.Body)
+	require.Nil(err)
+	require.Equal(200, httpResp.StatusCode)
+	require.Contains(string(body), `"match_id":"3"`)
+}
+
+func TestStatusFromJSON(t *testing.T) {
+	require := require.New(t)
+
+	err := StatusFromJSON([]byte(`{"code": 5, "message": "not here"}`))
+	require.Equal(codes.NotFound, status.Code(err))
+	require.Equal("not here", status.Convert(err).Message())
+
+	err = StatusFromJSON([]byte(`{"code": 0}`))
+	require.Equal(codes.Unknown, status.Code(err))
+
+	err = StatusFromJSON([]byte(`not json`))
+	require.Equal(codes.Unknown, status.Code(err))
+}
@@ -20,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff"
 	"github.com/gomodule/redigo/redis"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -41,6 +42,9 @@ var (
 const (
 	backfillLastAckTime = "backfill_last_ack_time"
 	allBackfills        = "allBackfills"
+	// backfillAssignedTicketsPrefix keys the set of the ids of the tickets
+	// assigned to a backfill, whose slots RemoveBackfillTickets can reopen.
+	backfillAssignedTicketsPrefix = "backfill_assigned_tickets/"
 )
 
 // CreateBackfill creates a new Backfill in the state storage if one doesn't exist. The xids algorithm used to create the ids ensures that they are unique with no system wide synchronization. Calling clients are forbidden from choosing an id during create. So no conflicts will occur.
@@ -83,7 +87,18 @@ func (rb *redisBackend) GetBackfill(ctx context.Context, id string) (*pb.Backfil
 	}
 	defer handleConnectionClose(&redisConn)
 
-	value, err := redis.Bytes(redisConn.Do("GET", id))
+	_, bi, err := getBackfillValue(redisConn, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bi.Backfill, bi.TicketIds, nil
+}
+
+// getBackfillValue returns the stored value of the Backfill with the specified
+// id, along with the internal Backfill it holds.
+func getBackfillValue(conn redis.Conn, id string) ([]byte, *ipb.BackfillInternal, error) {
+	value, err := redis.Bytes(conn.Do("GET", id))
 	if err != nil {
 		// Return NotFound if redigo did not find the backfill in storage.
 		if err == redis.ErrNil {
@@ -105,7 +120,28 @@ func (rb *redisBackend) GetBackfill(ctx context.Context, id string) (*pb.Backfil
 		return nil, nil, status.Errorf(codes.Internal, "%v", err)
 	}
 
-	return bi.Backfill, bi.TicketIds, nil
+	return value, bi, nil
+}
+
+// WatchBackfill calls the callback with the Backfill of the input id and its
+// associated ticketIDs, polling the state storage with the constant backoff
+// strategy until the callback or the lookup of the Backfill fails.
+func (rb *redisBackend) WatchBackfill(ctx context.Context, id string, callback func(*pb.Backfill, []string) error) error {
+	backoffOperation := func() error {
+		backfill, ticketIDs, err := rb.GetBackfill(ctx, id)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		err = callback(backfill, ticketIDs)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		return status.Error(codes.Unavailable, "listening on backfill updates, waiting for the next backoff")
+	}
+
+	return backoff.Retry(backoffOperation, backoff.WithContext(rb.newConstantBackoffStrategy(), ctx))
 }
 
 // GetBackfills returns multiple backfills from storage
@@ -182,6 +218,12 @@ func (rb *redisBackend) DeleteBackfill(ctx context.Context, id string) error {
 		return status.Errorf(codes.NotFound, "Backfill id: %s not found", id)
 	}
 
+	_, err = redisConn.Do("DEL", backfillAssignedTicketsPrefix+id)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to delete the tickets assigned to the backfill, id: %s", id)
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
 	return rb.deleteExpiredBackfillID(redisConn, id)
 }
 
@@ -202,6 +244,196 @@ func (rb *redisBackend) UpdateBackfill(ctx context.Context, backfill *pb.Backfil
 		return status.Errorf(codes.Unavailable, "can not update an expired backfill, id: %s", backfill.Id)
 	}
 
+	return setBackfill(redisConn, backfill, ticketIDs)
+}
+
+// ModifyBackfill applies modify to the Backfill with the specified id and its
+// associated ticketIDs, then stores the modified Backfill along with the
+// ticketIDs modify returns.  The Backfill is only stored if it wasn't changed
+// since it was read, otherwise modify is applied again to the new Backfill, so
+// that concurrent changes are never overwritten.  Errors returned by modify
+// are returned as is.
+func (rb *redisBackend) ModifyBackfill(ctx context.Context, id string, modify func(*pb.Backfill, []string) ([]string, error)) (*pb.Backfill, error) {
+	return rb.modifyBackfill(ctx, id, true, modify)
+}
+
+// modifyBackfill implements ModifyBackfill, failing on expired backfills only
+// if checkExpired is set.
+func (rb *redisBackend) modifyBackfill(ctx context.Context, id string, checkExpired bool, modify func(*pb.Backfill, []string) ([]string, error)) (*pb.Backfill, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "ModifyBackfill, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	for {
+		if ctx.Err() != nil {
+			return nil, status.Errorf(codes.Unavailable, "ModifyBackfill, id: %s: %v", id, ctx.Err())
+		}
+
+		value, bi, err := getBackfillValue(redisConn, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if checkExpired {
+			expired, err := isBackfillExpired(redisConn, id, getBackfillReleaseTimeoutFraction(rb.cfg))
+			if err != nil {
+				return nil, err
+			}
+			if expired {
+				return nil, status.Errorf(codes.Unavailable, "can not update an expired backfill, id: %s", id)
+			}
+		}
+
+		ticketIDs, err := modify(bi.Backfill, bi.TicketIds)
+		if err != nil {
+			return nil, err
+		}
+
+		swapped, err := compareAndSwapBackfill(redisConn, id, value, &ipb.BackfillInternal{Backfill: bi.Backfill, TicketIds: ticketIDs})
+		if err != nil {
+			return nil, err
+		}
+		if swapped {
+			return bi.Backfill, nil
+		}
+	}
+}
+
+// RemoveBackfillTickets applies reopen to the Backfill with the specified id
+// and the ticketIDs which were assigned to it and not removed yet, then stores
+// the Backfill and forgets these tickets were assigned to it.  Like
+// ModifyBackfill, the Backfill is only stored if it wasn't changed since it was
+// read, and reopen is applied again otherwise.  Removing tickets again is a
+// no-op: the Backfill is returned untouched along with no removed ticketIDs.
+func (rb *redisBackend) RemoveBackfillTickets(ctx context.Context, id string, ticketIDs []string, reopen func(*pb.Backfill, []string) error) (*pb.Backfill, []string, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "RemoveBackfillTickets, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	for {
+		if ctx.Err() != nil {
+			return nil, nil, status.Errorf(codes.Unavailable, "RemoveBackfillTickets, id: %s: %v", id, ctx.Err())
+		}
+
+		value, bi, err := getBackfillValue(redisConn, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		expired, err := isBackfillExpired(redisConn, id, getBackfillReleaseTimeoutFraction(rb.cfg))
+		if err != nil {
+			return nil, nil, err
+		}
+		if expired {
+			return nil, nil, status.Errorf(codes.Unavailable, "can not update an expired backfill, id: %s", id)
+		}
+
+		assigned, err := redis.Strings(redisConn.Do("SMEMBERS", backfillAssignedTicketsPrefix+id))
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get the tickets assigned to the backfill, id: %s", id)
+			return nil, nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		wasAssigned := make(map[string]struct{}, len(assigned))
+		for _, ticketID := range assigned {
+			wasAssigned[ticketID] = struct{}{}
+		}
+		removed := make([]string, 0, len(ticketIDs))
+		for _, ticketID := range ticketIDs {
+			if _, ok := wasAssigned[ticketID]; ok {
+				removed = append(removed, ticketID)
+				delete(wasAssigned, ticketID)
+			}
+		}
+		if len(removed) == 0 {
+			return bi.Backfill, removed, nil
+		}
+
+		if err = reopen(bi.Backfill, removed); err != nil {
+			return nil, nil, err
+		}
+		newValue, err := proto.Marshal(bi)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to marshal the backfill proto, id: %s", id)
+			return nil, nil, status.Errorf(codes.Internal, "%v", err)
+		}
+
+		args := make([]interface{}, 0, len(removed)+4)
+		args = append(args, id, backfillAssignedTicketsPrefix+id, value, newValue)
+		for _, ticketID := range removed {
+			args = append(args, ticketID)
+		}
+		swapped, err := redis.Bool(removeBackfillTicketsScript.Do(redisConn, args...))
+		if err != nil {
+			err = errors.Wrapf(err, "failed to remove the tickets of the backfill, id: %s", id)
+			return nil, nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		if swapped {
+			return bi.Backfill, removed, nil
+		}
+	}
+}
+
+// removeBackfillTicketsScript replaces the backfill KEYS[1] with ARGV[2] and
+// removes the ARGV[3...] tickets from its assigned tickets KEYS[2], only if its
+// value is still ARGV[1] and the tickets are all still assigned to it.  Returns
+// 1 if the backfill was replaced, 0 otherwise.
+var removeBackfillTicketsScript = redis.NewScript(2, `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+for i = 3, #ARGV do
+  if redis.call('SISMEMBER', KEYS[2], ARGV[i]) == 0 then
+    return 0
+  end
+end
+redis.call('SET', KEYS[1], ARGV[2])
+for i = 3, #ARGV do
+  redis.call('SREM', KEYS[2], ARGV[i])
+end
+return 1
+`)
+
+// compareAndSwapBackfillScript replaces the backfill KEYS[1] with ARGV[2], or
+// deletes it along with its assigned tickets if ARGV[2] is empty, only if its
+// value is still ARGV[1].  Returns 1 if the backfill was replaced, 0 otherwise.
+var compareAndSwapBackfillScript = redis.NewScript(1, `
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+if ARGV[2] == '' then
+  redis.call('DEL', KEYS[1], '`+backfillAssignedTicketsPrefix+`' .. KEYS[1])
+else
+  redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// compareAndSwapBackfill replaces the backfill with the specified id by bi, or
+// deletes it if bi is nil, only if its stored value is still old.  Returns
+// whether the backfill was replaced.
+func compareAndSwapBackfill(conn redis.Conn, id string, old []byte, bi *ipb.BackfillInternal) (bool, error) {
+	var value []byte
+	if bi != nil {
+		var err error
+		value, err = proto.Marshal(bi)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to marshal the backfill proto, id: %s", id)
+			return false, status.Errorf(codes.Internal, "%v", err)
+		}
+	}
+
+	swapped, err := redis.Bool(compareAndSwapBackfillScript.Do(conn, id, old, value))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to compare and swap the backfill, id: %s", id)
+		return false, status.Errorf(codes.Internal, "%v", err)
+	}
+	return swapped, nil
+}
+
+func setBackfill(conn redis.Conn, backfill *pb.Backfill, ticketIDs []string) error {
 	bf := ipb.BackfillInternal{
 		Backfill:  backfill,
 		TicketIds: ticketIDs,
@@ -213,7 +445,7 @@ func (rb *redisBackend) UpdateBackfill(ctx context.Context, backfill *pb.Backfil
 		return status.Errorf(codes.Internal, "%v", err)
 	}
 
-	_, err = redisConn.Do("SET", backfill.GetId(), value)
+	_, err = conn.Do("SET", backfill.GetId(), value)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to set the value for backfill, id: %s", backfill.GetId())
 		return status.Errorf(codes.Internal, "%v", err)
@@ -235,51 +467,74 @@ func isBackfillExpired(conn redis.Conn, id string, ttl time.Duration) (bool, err
 
 // DeleteBackfillCompletely performs a set of operations to remove backfill and all related entities.
 func (rb *redisBackend) DeleteBackfillCompletely(ctx context.Context, id string) error {
-	m := rb.NewMutex(id)
-	err := m.Lock(ctx)
+	// 1. deindex backfill
+	err := rb.DeindexBackfill(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	defer func() {
-		if _, err = m.Unlock(context.Background()); err != nil {
-			logger.WithError(err).Error("error on mutex unlock")
-		}
-	}()
-
-	// 1. deindex backfill
-	err = rb.DeindexBackfill(ctx, id)
+	redisConn, err := rb.redisPool.GetContext(ctx)
 	if err != nil {
-		return err
+		return status.Errorf(codes.Unavailable, "DeleteBackfillCompletely, id: %s, failed to connect to redis: %v", id, err)
 	}
+	defer handleConnectionClose(&redisConn)
 
 	// just log errors and try to perform as mush actions as possible
+	for ctx.Err() == nil {
+		// 2. get associated with a current backfill tickets ids
+		value, bi, err := getBackfillValue(redisConn, id)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"error":       err.Error(),
+				"backfill_id": id,
+			}).Error("DeleteBackfillCompletely - failed to GetBackfill")
+			break
+		}
 
-	// 2. get associated with a current backfill tickets ids
-	_, associatedTickets, err := rb.GetBackfill(ctx, id)
-	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"error":       err.Error(),
-			"backfill_id": id,
-		}).Error("DeleteBackfillCompletely - failed to GetBackfill")
-	}
+		// 3. delete associated tickets from pending release state
+		err = rb.DeleteTicketsFromPendingRelease(ctx, bi.TicketIds)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"error":       err.Error(),
+				"backfill_id": id,
+			}).Error("DeleteBackfillCompletely - failed to DeleteTicketsFromPendingRelease")
+		}
 
-	// 3. delete associated tickets from pending release state
-	err = rb.DeleteTicketsFromPendingRelease(ctx, associatedTickets)
-	if err != nil {
-		logger.WithFields(logrus.Fields{
-			"error":       err.Error(),
-			"backfill_id": id,
-		}).Error("DeleteBackfillCompletely - failed to DeleteTicketsFromPendingRelease")
+		// 4. delete backfill, unless tickets were associated with it in the meantime
+		deleted, err := compareAndSwapBackfill(redisConn, id, value, nil)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"error":       err.Error(),
+				"backfill_id": id,
+			}).Error("DeleteBackfillCompletely - failed to DeleteBackfill")
+			break
+		}
+		if !deleted {
+			continue
+		}
+
+		if len(bi.TicketIds) > 0 {
+			rb.recordBackfillEvent(ctx, pb.BackfillEvent_TICKETS_RELEASED, id, bi.TicketIds)
+		}
+		rb.recordBackfillEvent(ctx, pb.BackfillEvent_DELETED, id, nil)
+
+		err = rb.deleteExpiredBackfillID(redisConn, id)
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"error":       err.Error(),
+				"backfill_id": id,
+			}).Error("DeleteBackfillCompletely - failed to delete the backfill's last acknowledgment time")
+		}
+		break
 	}
 
-	// 4. delete backfill
-	err = rb.DeleteBackfill(ctx, id)
+	// 5. forget the backfill expired, if it was in its grace period
+	err = rb.forgetExpiredBackfill(ctx, id)
 	if err != nil {
 		logger.WithFields(logrus.Fields{
 			"error":       err.Error(),
 			"backfill_id": id,
-		}).Error("DeleteBackfillCompletely - failed to DeleteBackfill")
+		}).Error("DeleteBackfillCompletely - failed to forget expired backfill")
 	}
 
 	return nil
@@ -288,7 +543,7 @@ func (rb *redisBackend) DeleteBackfillCompletely(ctx context.Context, id string)
 func (rb *redisBackend) cleanupWorker(ctx context.Context, backfillIDsCh <-chan string, wg *sync.WaitGroup) {
 	var err error
 	for id := range backfillIDsCh {
-		err = rb.DeleteBackfillCompletely(ctx, id)
+		err = rb.expireBackfill(ctx, id)
 		if err != nil {
 			logger.WithFields(logrus.Fields{
 				"error":       err.Error(),
@@ -338,7 +593,19 @@ func (rb *redisBackend) UpdateAcknowledgmentTimestamp(ctx context.Context, id st
 	}
 
 	if expired {
-		return status.Errorf(codes.Unavailable, "can not acknowledge an expired backfill, id: %s", id)
+		// Expired backfills can still be acknowledged during their grace period.
+		deleted, err := isBackfillExpired(redisConn, id, getBackfillReleaseTimeoutFraction(rb.cfg)+getBackfillGracePeriod(rb.cfg))
+		if err != nil {
+			return err
+		}
+		if deleted {
+			return status.Errorf(codes.Unavailable, "can not acknowledge an expired backfill, id: %s", id)
+		}
+
+		err = rb.resurrectBackfill(ctx, redisConn, id)
+		if err != nil {
+			return err
+		}
 	}
 
 	return doUpdateAcknowledgmentTimestamp(redisConn, id)
@@ -389,7 +656,19 @@ func (rb *redisBackend) deleteExpiredBackfillID(conn redis.Conn, backfillID stri
 	return nil
 }
 
-// IndexBackfill adds the backfill to the index.
+// indexBackfillScript sets the generation of the backfill ARGV[1] in the index
+// KEYS[1] to ARGV[2], unless a later generation is already indexed.
+var indexBackfillScript = redis.NewScript(1, `
+local indexed = redis.call('HGET', KEYS[1], ARGV[1])
+if not indexed or tonumber(indexed) < tonumber(ARGV[2]) then
+  redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+end
+return 0
+`)
+
+// IndexBackfill adds the backfill to the index.  Concurrent updates of the
+// backfill may be indexed out of order, so a generation older than the indexed
+// one is ignored.
 func (rb *redisBackend) IndexBackfill(ctx context.Context, backfill *pb.Backfill) error {
 	redisConn, err := rb.redisPool.GetContext(ctx)
 	if err != nil {
@@ -397,7 +676,7 @@ func (rb *redisBackend) IndexBackfill(ctx context.Context, backfill *pb.Backfill
 	}
 	defer handleConnectionClose(&redisConn)
 
-	err = redisConn.Send("HSET", allBackfills, backfill.Id, backfill.Generation)
+	_, err = indexBackfillScript.Do(redisConn, allBackfills, backfill.Id, backfill.Generation)
 	if err != nil {
 		err = errors.Wrapf(err, "failed to add backfill to all backfills, id: %s", backfill.Id)
 		return status.Errorf(codes.Internal, "%v", err)
@@ -0,0 +1,290 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// backfillEvents is a sorted set of the recent backfill events, scored by
+	// their sequence number.
+	backfillEvents = "backfill_events"
+	// backfillEventsSequence is the sequence number of the last backfill event.
+	backfillEventsSequence = "backfill_events_sequence"
+	// expiredBackfills is the set of ids of the expired backfills in their
+	// grace period.
+	expiredBackfills = "expired_backfills"
+	// maxBackfillEvents is the number of recent backfill events kept for
+	// WatchBackfillEvents calls polling them.
+	maxBackfillEvents = 1000
+)
+
+var (
+	backfillEventsCount = stats.Int64("open-match.dev/statestore/backfill_events", "Number of backfill life-cycle events", stats.UnitDimensionless)
+	keyEventType        = tag.MustNewKey("type")
+
+	// BackfillEventsView counts the backfill life-cycle events by type.  It is
+	// registered by the services changing backfills.
+	BackfillEventsView = &view.View{
+		Measure:     backfillEventsCount,
+		Name:        "open-match.dev/statestore/backfill_events",
+		Description: "Number of backfill life-cycle events",
+		Aggregation: view.Count(),
+		TagKeys:     []tag.Key{keyEventType},
+	}
+)
+
+// recordBackfillEvent stores the event for WatchBackfillEvents calls.  The
+// event is a notification only, so failures are logged instead of failing the
+// change of the backfill.
+func (rb *redisBackend) recordBackfillEvent(ctx context.Context, t pb.BackfillEvent_Type, id string, ticketIDs []string) {
+	err := stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(keyEventType, t.String())}, backfillEventsCount.M(1))
+	if err != nil {
+		logger.WithError(err).Info("failed to record backfill event metric")
+	}
+
+	err = rb.doRecordBackfillEvent(ctx, &pb.BackfillEvent{
+		Type:       t,
+		BackfillId: id,
+		TicketIds:  ticketIDs,
+		CreateTime: timestamppb.Now(),
+	})
+	if err != nil {
+		logger.WithFields(logrus.Fields{
+			"error":       err.Error(),
+			"backfill_id": id,
+			"type":        t.String(),
+		}).Error("failed to record backfill event")
+	}
+}
+
+func (rb *redisBackend) doRecordBackfillEvent(ctx context.Context, event *pb.BackfillEvent) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "recordBackfillEvent, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	value, err := proto.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the backfill event proto")
+	}
+
+	_, err = recordBackfillEventScript.Do(redisConn, backfillEvents, backfillEventsSequence, value, maxBackfillEvents)
+	return errors.Wrap(err, "failed to store backfill event")
+}
+
+// recordBackfillEventScript allocates the next KEYS[2] sequence number to the
+// ARGV[1] event and adds it to the KEYS[1] events, keeping the ARGV[2] most
+// recent ones.  Both happen in one step, so that watchers polling for events
+// after the sequence number they saw never miss one stored late.
+var recordBackfillEventScript = redis.NewScript(2, `
+local seq = redis.call('INCR', KEYS[2])
+redis.call('ZADD', KEYS[1], seq, ARGV[1])
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[2]) - 1)
+return seq
+`)
+
+// WatchBackfillEvents calls the callback with the backfill events recorded
+// after the call, of the backfill with the input id or of all backfills if id
+// is empty, polling the state storage with the constant backoff strategy until
+// the callback fails.
+func (rb *redisBackend) WatchBackfillEvents(ctx context.Context, id string, callback func(*pb.BackfillEvent) error) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "WatchBackfillEvents, failed to connect to redis: %v", err)
+	}
+	last, err := redis.Int64(redisConn.Do("GET", backfillEventsSequence))
+	handleConnectionClose(&redisConn)
+	if err != nil && err != redis.ErrNil {
+		return status.Errorf(codes.Internal, "%v", errors.Wrap(err, "failed to get the backfill event sequence number"))
+	}
+
+	backoffOperation := func() error {
+		var events []*pb.BackfillEvent
+		events, last, err = rb.getBackfillEventsAfter(ctx, last)
+		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		for _, e := range events {
+			if id != "" && e.GetBackfillId() != id {
+				continue
+			}
+			err = callback(e)
+			if err != nil {
+				return backoff.Permanent(err)
+			}
+		}
+
+		return status.Error(codes.Unavailable, "listening on backfill events, waiting for the next backoff")
+	}
+
+	return backoff.Retry(backoffOperation, backoff.WithContext(rb.newConstantBackoffStrategy(), ctx))
+}
+
+// getBackfillEventsAfter returns the backfill events with a sequence number
+// greater than seq, and the sequence number of the last one.
+func (rb *redisBackend) getBackfillEventsAfter(ctx context.Context, seq int64) ([]*pb.BackfillEvent, int64, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, seq, status.Errorf(codes.Unavailable, "getBackfillEventsAfter, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	values, err := redis.Values(redisConn.Do("ZRANGEBYSCORE", backfillEvents, fmt.Sprintf("(%d", seq), "+inf", "WITHSCORES"))
+	if err != nil {
+		return nil, seq, status.Errorf(codes.Internal, "%v", errors.Wrap(err, "failed to get backfill events"))
+	}
+
+	events := make([]*pb.BackfillEvent, 0, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		b, err := redis.Bytes(values[i], nil)
+		if err != nil {
+			return nil, seq, status.Errorf(codes.Internal, "%v", errors.Wrap(err, "failed to read backfill event"))
+		}
+		seq, err = redis.Int64(values[i+1], nil)
+		if err != nil {
+			return nil, seq, status.Errorf(codes.Internal, "%v", errors.Wrap(err, "failed to read backfill event sequence number"))
+		}
+
+		e := &pb.BackfillEvent{}
+		if err = proto.Unmarshal(b, e); err != nil {
+			return nil, seq, status.Errorf(codes.Internal, "%v", errors.Wrap(err, "failed to unmarshal backfill event"))
+		}
+		events = append(events, e)
+	}
+	return events, seq, nil
+}
+
+// expireBackfill handles a backfill which wasn't acknowledged in time.  Without
+// grace period, the backfill is deleted right away.  Otherwise its tickets are
+// released once, and the backfill is deleted after the grace period unless it
+// is acknowledged again.
+func (rb *redisBackend) expireBackfill(ctx context.Context, id string) error {
+	grace := getBackfillGracePeriod(rb.cfg)
+	if grace <= 0 {
+		rb.recordBackfillEvent(ctx, pb.BackfillEvent_EXPIRED, id, nil)
+		return rb.DeleteBackfillCompletely(ctx, id)
+	}
+
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "expireBackfill, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	deleted, err := isBackfillExpired(redisConn, id, getBackfillReleaseTimeoutFraction(rb.cfg)+grace)
+	if err != nil {
+		return err
+	}
+	if deleted {
+		return rb.DeleteBackfillCompletely(ctx, id)
+	}
+
+	added, err := redis.Int(redisConn.Do("SADD", expiredBackfills, id))
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", errors.Wrapf(err, "failed to mark backfill as expired, id: %s", id))
+	}
+	if added == 0 {
+		return nil
+	}
+
+	rb.recordBackfillEvent(ctx, pb.BackfillEvent_EXPIRED, id, nil)
+	return rb.releaseBackfillTickets(ctx, id)
+}
+
+// releaseBackfillTickets returns the tickets associated with the backfill to
+// the active pool, and clears them from the backfill.
+func (rb *redisBackend) releaseBackfillTickets(ctx context.Context, id string) error {
+	var associatedTickets []string
+	_, err := rb.modifyBackfill(ctx, id, false, func(_ *pb.Backfill, ticketIDs []string) ([]string, error) {
+		associatedTickets = ticketIDs
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(associatedTickets) == 0 {
+		return nil
+	}
+
+	err = rb.DeleteTicketsFromPendingRelease(ctx, associatedTickets)
+	if err != nil {
+		return err
+	}
+
+	rb.recordBackfillEvent(ctx, pb.BackfillEvent_TICKETS_RELEASED, id, associatedTickets)
+	return nil
+}
+
+// resurrectBackfill clears the expired mark of a backfill acknowledged during
+// its grace period.
+func (rb *redisBackend) resurrectBackfill(ctx context.Context, redisConn redis.Conn, id string) error {
+	removed, err := redis.Int(redisConn.Do("SREM", expiredBackfills, id))
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", errors.Wrapf(err, "failed to clear the expired mark of backfill, id: %s", id))
+	}
+	if removed == 1 {
+		rb.recordBackfillEvent(ctx, pb.BackfillEvent_RESURRECTED, id, nil)
+	}
+	return nil
+}
+
+// forgetExpiredBackfill clears the expired mark of a deleted backfill.
+func (rb *redisBackend) forgetExpiredBackfill(ctx context.Context, id string) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "forgetExpiredBackfill, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = redisConn.Do("SREM", expiredBackfills, id)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", errors.Wrapf(err, "failed to clear the expired mark of backfill, id: %s", id))
+	}
+	return nil
+}
+
+func getBackfillGracePeriod(cfg config.View) time.Duration {
+	const (
+		name = "backfillGracePeriod"
+		// Expired backfills are deleted right away by default.
+		defaultBackfillGracePeriod time.Duration = 0
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultBackfillGracePeriod
+	}
+
+	return cfg.GetDuration(name)
+}
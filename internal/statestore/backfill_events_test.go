@@ -0,0 +1,221 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestBackfillEventsWithoutGracePeriod(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+	rb := getRedisBackend(t, service)
+
+	rc, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+
+	bfID := "mockBackfill-1"
+	ticketIDs := []string{"t1", "t2"}
+	err = service.CreateBackfill(ctx, &pb.Backfill{Id: bfID}, ticketIDs)
+	require.NoError(t, err)
+	err = service.AddTicketsToPendingRelease(ctx, ticketIDs)
+	require.NoError(t, err)
+
+	_, err = rc.Do("ZADD", "backfill_last_ack_time", 123, bfID)
+	require.NoError(t, err)
+
+	err = service.CleanupBackfills(ctx)
+	require.NoError(t, err)
+
+	events, _, err := rb.getBackfillEventsAfter(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, []pb.BackfillEvent_Type{
+		pb.BackfillEvent_EXPIRED,
+		pb.BackfillEvent_TICKETS_RELEASED,
+		pb.BackfillEvent_DELETED,
+	}, eventTypes(events))
+	for _, e := range events {
+		require.Equal(t, bfID, e.GetBackfillId())
+		require.NotNil(t, e.GetCreateTime())
+	}
+	require.ElementsMatch(t, ticketIDs, events[1].GetTicketIds())
+
+	_, _, err = service.GetBackfill(ctx, bfID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBackfillEventsWithGracePeriod(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	cfg.(*viper.Viper).Set("backfillGracePeriod", time.Hour)
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+	rb := getRedisBackend(t, service)
+
+	rc, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+
+	bfID := "mockBackfill-1"
+	ticketIDs := []string{"t1", "t2"}
+	err = service.CreateBackfill(ctx, &pb.Backfill{Id: bfID}, ticketIDs)
+	require.NoError(t, err)
+	err = service.AddTicketsToPendingRelease(ctx, ticketIDs)
+	require.NoError(t, err)
+
+	// expired, but still in its grace period
+	_, err = rc.Do("ZADD", "backfill_last_ack_time", time.Now().Add(-time.Second).UnixNano(), bfID)
+	require.NoError(t, err)
+
+	// the tickets are released only once
+	for i := 0; i < 2; i++ {
+		err = service.CleanupBackfills(ctx)
+		require.NoError(t, err)
+	}
+
+	events, seq, err := rb.getBackfillEventsAfter(ctx, 0)
+	require.NoError(t, err)
+	require.Equal(t, []pb.BackfillEvent_Type{
+		pb.BackfillEvent_EXPIRED,
+		pb.BackfillEvent_TICKETS_RELEASED,
+	}, eventTypes(events))
+	require.ElementsMatch(t, ticketIDs, events[1].GetTicketIds())
+
+	bf, associatedTickets, err := service.GetBackfill(ctx, bfID)
+	require.NoError(t, err)
+	require.NotNil(t, bf)
+	require.Empty(t, associatedTickets)
+
+	pending, err := redis.Int(rc.Do("ZCARD", "proposed_ticket_ids"))
+	require.NoError(t, err)
+	require.Equal(t, 0, pending)
+
+	// acknowledging the backfill during its grace period resurrects it
+	err = service.UpdateAcknowledgmentTimestamp(ctx, bfID)
+	require.NoError(t, err)
+
+	events, seq, err = rb.getBackfillEventsAfter(ctx, seq)
+	require.NoError(t, err)
+	require.Equal(t, []pb.BackfillEvent_Type{pb.BackfillEvent_RESURRECTED}, eventTypes(events))
+
+	// past its grace period, the backfill can't be acknowledged and is deleted
+	_, err = rc.Do("ZADD", "backfill_last_ack_time", 123, bfID)
+	require.NoError(t, err)
+
+	err = service.UpdateAcknowledgmentTimestamp(ctx, bfID)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	err = service.CleanupBackfills(ctx)
+	require.NoError(t, err)
+
+	events, _, err = rb.getBackfillEventsAfter(ctx, seq)
+	require.NoError(t, err)
+	require.Equal(t, []pb.BackfillEvent_Type{pb.BackfillEvent_DELETED}, eventTypes(events))
+
+	_, _, err = service.GetBackfill(ctx, bfID)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestWatchBackfillEvents(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	err := service.CreateBackfill(ctx, &pb.Backfill{Id: "1"}, nil)
+	require.NoError(t, err)
+	err = service.CreateBackfill(ctx, &pb.Backfill{Id: "2"}, nil)
+	require.NoError(t, err)
+
+	// events recorded before the call are not sent
+	err = service.DeleteBackfillCompletely(ctx, "1")
+	require.NoError(t, err)
+
+	errStop := errors.New("stop")
+	received := make(chan *pb.BackfillEvent, 2)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- service.WatchBackfillEvents(ctx, "2", func(e *pb.BackfillEvent) error {
+			received <- e
+			return errStop
+		})
+	}()
+
+	// let the watch read the current sequence number
+	time.Sleep(50 * time.Millisecond)
+
+	err = service.CreateBackfill(ctx, &pb.Backfill{Id: "3"}, nil)
+	require.NoError(t, err)
+	err = service.DeleteBackfillCompletely(ctx, "3")
+	require.NoError(t, err)
+	err = service.DeleteBackfillCompletely(ctx, "2")
+	require.NoError(t, err)
+
+	select {
+	case err = <-watchErr:
+		require.Equal(t, errStop, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchBackfillEvents did not return")
+	}
+
+	require.Len(t, received, 1)
+	e := <-received
+	require.Equal(t, pb.BackfillEvent_DELETED, e.GetType())
+	require.Equal(t, "2", e.GetBackfillId())
+
+	// a canceled context stops the watch
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = service.WatchBackfillEvents(cancelCtx, "", func(*pb.BackfillEvent) error {
+		return nil
+	})
+	require.Error(t, err)
+}
+
+func getRedisBackend(t *testing.T, s Service) *redisBackend {
+	if is, ok := s.(*instrumentedService); ok {
+		s = is.s
+	}
+	rb, ok := s.(*redisBackend)
+	require.True(t, ok)
+	return rb
+}
+
+func eventTypes(events []*pb.BackfillEvent) []pb.BackfillEvent_Type {
+	types := make([]pb.BackfillEvent_Type, 0, len(events))
+	for _, e := range events {
+		types = append(types, e.GetType())
+	}
+	return types
+}
@@ -177,6 +177,111 @@ func TestUpdateExistingBackfillNoError(t *testing.T) {
 	require.Equal(t, v.Value, res.Value)
 }
 
+func TestModifyBackfill(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	bf := &pb.Backfill{Id: "bf1", Generation: 1}
+	require.NoError(t, service.CreateBackfill(ctx, bf, []string{"1"}))
+
+	// a concurrent change is never overwritten, modify is applied again instead
+	calls := 0
+	actual, err := service.ModifyBackfill(ctx, bf.Id, func(b *pb.Backfill, ticketIDs []string) ([]string, error) {
+		calls++
+		if calls == 1 {
+			require.NoError(t, service.UpdateBackfill(ctx, &pb.Backfill{Id: bf.Id, Generation: 2}, []string{"1", "2"}))
+		}
+		b.Generation++
+		return append(ticketIDs, "3"), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, int64(3), actual.Generation)
+
+	stored, ticketIDs, err := service.GetBackfill(ctx, bf.Id)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stored.Generation)
+	require.Equal(t, []string{"1", "2", "3"}, ticketIDs)
+
+	// errors of modify are returned as is, without storing anything
+	errModify := status.Error(codes.FailedPrecondition, "modify failed")
+	_, err = service.ModifyBackfill(ctx, bf.Id, func(b *pb.Backfill, _ []string) ([]string, error) {
+		b.Generation++
+		return nil, errModify
+	})
+	require.Equal(t, errModify, err)
+	stored, _, err = service.GetBackfill(ctx, bf.Id)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stored.Generation)
+
+	noop := func(_ *pb.Backfill, ticketIDs []string) ([]string, error) {
+		return ticketIDs, nil
+	}
+	_, err = service.ModifyBackfill(ctx, "missing", noop)
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	rc, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	_, err = rc.Do("ZADD", "backfill_last_ack_time", 123, bf.Id)
+	require.NoError(t, err)
+	_, err = service.ModifyBackfill(ctx, bf.Id, noop)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+	require.Contains(t, status.Convert(err).Message(), fmt.Sprintf("can not update an expired backfill, id: %s", bf.Id))
+}
+
+func TestRemoveBackfillTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	bf := &pb.Backfill{Id: "bf1", Generation: 1}
+	require.NoError(t, service.CreateBackfill(ctx, bf, []string{"1", "2"}))
+	for _, id := range []string{"1", "2"} {
+		require.NoError(t, service.CreateTicket(ctx, &pb.Ticket{Id: id}))
+	}
+	_, _, err := service.AssignBackfillTickets(ctx, bf, []string{"1", "2"}, &pb.Assignment{Connection: "server"})
+	require.NoError(t, err)
+
+	// only the tickets assigned to the backfill are removed, once
+	var reopened []string
+	reopen := func(b *pb.Backfill, removed []string) error {
+		reopened = removed
+		b.Generation++
+		return nil
+	}
+	actual, removed, err := service.RemoveBackfillTickets(ctx, bf.Id, []string{"1", "unknown", "1"}, reopen)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, removed)
+	require.Equal(t, []string{"1"}, reopened)
+	require.Equal(t, int64(2), actual.Generation)
+
+	reopened = nil
+	actual, removed, err = service.RemoveBackfillTickets(ctx, bf.Id, []string{"1"}, reopen)
+	require.NoError(t, err)
+	require.Empty(t, removed)
+	require.Nil(t, reopened)
+	require.Equal(t, int64(2), actual.Generation)
+
+	// the assigned tickets are forgotten with the backfill
+	require.NoError(t, service.DeleteBackfillCompletely(ctx, bf.Id))
+	rc, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer rc.Close()
+	exists, err := redis.Bool(rc.Do("EXISTS", backfillAssignedTicketsPrefix+bf.Id))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	_, _, err = service.RemoveBackfillTickets(ctx, bf.Id, []string{"2"}, reopen)
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+}
+
 func TestUpdateBackfillDoNotExistCanNotUpdate(t *testing.T) {
 	cfg, closer := createRedis(t, false, "")
 	defer closer()
@@ -603,6 +708,17 @@ func TestIndexBackfill(t *testing.T) {
 		require.Equal(t, "mockBackfillID-1", idsIndexed[1])
 	})
 
+	t.Run("WithOlderGeneration", func(t *testing.T) {
+		ctx := utilTesting.NewContext(t)
+		require.NoError(t, service.IndexBackfill(ctx, &pb.Backfill{Id: "bf", Generation: 3}))
+		require.NoError(t, service.IndexBackfill(ctx, &pb.Backfill{Id: "bf", Generation: 2}))
+		c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+		require.NoError(t, err)
+		generation, err := redis.Int(c.Do("HGET", allBackfills, "bf"))
+		require.NoError(t, err)
+		require.Equal(t, 3, generation)
+	})
+
 	t.Run("WithCancelledContext", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
@@ -16,6 +16,7 @@ package statestore
 
 import (
 	"context"
+	"time"
 
 	"go.opencensus.io/trace"
 	"open-match.dev/open-match/pkg/pb"
@@ -77,12 +78,48 @@ func (is *instrumentedService) GetIndexedIDSet(ctx context.Context) (map[string]
 	return is.s.GetIndexedIDSet(ctx)
 }
 
+func (is *instrumentedService) GetProposalState(ctx context.Context, ticketIDs []string, backfillID string) (*ProposalState, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetProposalState")
+	defer span.End()
+	return is.s.GetProposalState(ctx, ticketIDs, backfillID)
+}
+
 func (is *instrumentedService) UpdateAssignments(ctx context.Context, req *pb.AssignTicketsRequest) (*pb.AssignTicketsResponse, []*pb.Ticket, error) {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.UpdateAssignments")
 	defer span.End()
 	return is.s.UpdateAssignments(ctx, req)
 }
 
+func (is *instrumentedService) RevokeAssignments(ctx context.Context, ids []string) (*pb.RevokeAssignmentsResponse, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RevokeAssignments")
+	defer span.End()
+	return is.s.RevokeAssignments(ctx, ids)
+}
+
+func (is *instrumentedService) AcceptAssignment(ctx context.Context, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AcceptAssignment")
+	defer span.End()
+	return is.s.AcceptAssignment(ctx, id)
+}
+
+func (is *instrumentedService) DeclineAssignment(ctx context.Context, id string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.DeclineAssignment")
+	defer span.End()
+	return is.s.DeclineAssignment(ctx, id)
+}
+
+func (is *instrumentedService) CleanupTentativeAssignments(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CleanupTentativeAssignments")
+	defer span.End()
+	return is.s.CleanupTentativeAssignments(ctx)
+}
+
+func (is *instrumentedService) AssignBackfillTickets(ctx context.Context, backfill *pb.Backfill, ticketIDs []string, assignment *pb.Assignment) (*pb.AssignTicketsResponse, []*pb.Ticket, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AssignBackfillTickets")
+	defer span.End()
+	return is.s.AssignBackfillTickets(ctx, backfill, ticketIDs, assignment)
+}
+
 func (is *instrumentedService) GetAssignments(ctx context.Context, id string, callback func(*pb.Assignment) error) error {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetAssignments")
 	defer span.End()
@@ -107,6 +144,62 @@ func (is *instrumentedService) ReleaseAllTickets(ctx context.Context) error {
 	return is.s.ReleaseAllTickets(ctx)
 }
 
+// ReserveTickets reserves the indexed tickets for the profile for the given number of reservation cycles.
+func (is *instrumentedService) ReserveTickets(ctx context.Context, profile string, ids []string, cycles int) ([]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReserveTickets")
+	defer span.End()
+	return is.s.ReserveTickets(ctx, profile, ids, cycles)
+}
+
+// GetTicketReservations returns the profile reserving each reserved ticket.
+func (is *instrumentedService) GetTicketReservations(ctx context.Context) (map[string]string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketReservations")
+	defer span.End()
+	return is.s.GetTicketReservations(ctx)
+}
+
+// AdvanceReservationCycle starts the next reservation cycle of the ticket partition, expiring the reservations which ran out.
+func (is *instrumentedService) AdvanceReservationCycle(ctx context.Context, partition, holder string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AdvanceReservationCycle")
+	defer span.End()
+	return is.s.AdvanceReservationCycle(ctx, partition, holder)
+}
+
+// RecordMatch stores the record of a match, and adds it to the history of each of its tickets.
+func (is *instrumentedService) RecordMatch(ctx context.Context, record *pb.MatchRecord, retention time.Duration) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RecordMatch")
+	defer span.End()
+	return is.s.RecordMatch(ctx, record, retention)
+}
+
+// GetTicketMatchHistory returns the recorded matches of the ticket, newest first.
+func (is *instrumentedService) GetTicketMatchHistory(ctx context.Context, ticketID string) ([]*pb.MatchRecord, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetTicketMatchHistory")
+	defer span.End()
+	return is.s.GetTicketMatchHistory(ctx, ticketID)
+}
+
+// AcquireLease takes or renews the named lease for the holder.
+func (is *instrumentedService) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.AcquireLease")
+	defer span.End()
+	return is.s.AcquireLease(ctx, name, holder, ttl)
+}
+
+// GetLeaseHolder returns the holder of the named lease, or "" if it is free.
+func (is *instrumentedService) GetLeaseHolder(ctx context.Context, name string) (string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetLeaseHolder")
+	defer span.End()
+	return is.s.GetLeaseHolder(ctx, name)
+}
+
+// ReleaseLease frees the named lease if the holder has it.
+func (is *instrumentedService) ReleaseLease(ctx context.Context, name, holder string) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ReleaseLease")
+	defer span.End()
+	return is.s.ReleaseLease(ctx, name, holder)
+}
+
 // CreateBackfill creates a new Backfill in the state storage if one doesn't exist. The xids algorithm used to create the ids ensures that they are unique with no system wide synchronization. Calling clients are forbidden from choosing an id during create. So no conflicts will occur.
 func (is *instrumentedService) CreateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.CreateBackfill")
@@ -135,6 +228,13 @@ func (is *instrumentedService) DeleteBackfill(ctx context.Context, id string) er
 	return is.s.DeleteBackfill(ctx, id)
 }
 
+// WatchBackfill calls the callback with the Backfill and its associated ticketIDs until it fails.
+func (is *instrumentedService) WatchBackfill(ctx context.Context, id string, callback func(*pb.Backfill, []string) error) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.WatchBackfill")
+	defer span.End()
+	return is.s.WatchBackfill(ctx, id, callback)
+}
+
 // UpdateBackfill updates an existing Backfill with a new data. ticketIDs can be nil.
 func (is *instrumentedService) UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.UpdateBackfill")
@@ -142,6 +242,20 @@ func (is *instrumentedService) UpdateBackfill(ctx context.Context, backfill *pb.
 	return is.s.UpdateBackfill(ctx, backfill, ticketIDs)
 }
 
+// ModifyBackfill applies modify to an existing Backfill, and stores the result if the Backfill did not change in the meantime.
+func (is *instrumentedService) ModifyBackfill(ctx context.Context, id string, modify func(*pb.Backfill, []string) ([]string, error)) (*pb.Backfill, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.ModifyBackfill")
+	defer span.End()
+	return is.s.ModifyBackfill(ctx, id, modify)
+}
+
+// RemoveBackfillTickets applies reopen to an existing Backfill with the ticketIDs assigned to it, and stores the result if the Backfill did not change in the meantime.
+func (is *instrumentedService) RemoveBackfillTickets(ctx context.Context, id string, ticketIDs []string, reopen func(*pb.Backfill, []string) error) (*pb.Backfill, []string, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.RemoveBackfillTickets")
+	defer span.End()
+	return is.s.RemoveBackfillTickets(ctx, id, ticketIDs, reopen)
+}
+
 // NewMutex returns a new distributed mutex with given name
 func (is *instrumentedService) NewMutex(key string) RedisLocker {
 	_, span := trace.StartSpan(context.Background(), "statestore/instrumented.NewMutex")
@@ -156,6 +270,13 @@ func (is *instrumentedService) UpdateAcknowledgmentTimestamp(ctx context.Context
 	return is.s.UpdateAcknowledgmentTimestamp(ctx, id)
 }
 
+// WatchBackfillEvents calls the callback with the backfill events recorded after the call until it fails.
+func (is *instrumentedService) WatchBackfillEvents(ctx context.Context, id string, callback func(*pb.BackfillEvent) error) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.WatchBackfillEvents")
+	defer span.End()
+	return is.s.WatchBackfillEvents(ctx, id, callback)
+}
+
 // GetExpiredBackfillIDs - get all backfills which are expired
 func (is *instrumentedService) GetExpiredBackfillIDs(ctx context.Context) ([]string, error) {
 	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetExpiredBackfillIDs")
@@ -197,3 +318,17 @@ func (is *instrumentedService) DeleteBackfillCompletely(ctx context.Context, id
 	defer span.End()
 	return is.s.DeleteBackfillCompletely(ctx, id)
 }
+
+// PutWasmModule stores a WebAssembly match function module under name.
+func (is *instrumentedService) PutWasmModule(ctx context.Context, name string, module []byte) error {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.PutWasmModule")
+	defer span.End()
+	return is.s.PutWasmModule(ctx, name, module)
+}
+
+// GetWasmModule gets the WebAssembly match function module stored under name.
+func (is *instrumentedService) GetWasmModule(ctx context.Context, name string) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "statestore/instrumented.GetWasmModule")
+	defer span.End()
+	return is.s.GetWasmModule(ctx, name)
+}
@@ -0,0 +1,110 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// acquireLeaseScript takes the lease KEYS[1] for the holder ARGV[1] for ARGV[2]
+// milliseconds, if it is free or already held by the holder.
+var acquireLeaseScript = redis.NewScript(1, `
+local held = redis.call('GET', KEYS[1])
+if held and held ~= ARGV[1] then
+  return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// releaseLeaseScript frees the lease KEYS[1] if it is held by the holder
+// ARGV[1].
+var releaseLeaseScript = redis.NewScript(1, `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// SynchronizerLease names the lease held by the leading synchronizer replica
+// of a ticket partition, or of the default synchronizer if partition is "".
+func SynchronizerLease(partition string) string {
+	if partition == "" {
+		return "synchronizer"
+	}
+	return "synchronizer/" + partition
+}
+
+func leaseKey(name string) string {
+	return "lease/" + name
+}
+
+// AcquireLease takes or renews the named lease for the holder, returning
+// whether the holder has the lease for the ttl.
+func (rb *redisBackend) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return false, status.Errorf(codes.Unavailable, "AcquireLease, name: %s, failed to connect to redis: %v", name, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	acquired, err := redis.Bool(acquireLeaseScript.Do(redisConn, leaseKey(name), holder, ttl.Milliseconds()))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to acquire lease, name: %s", name)
+		return false, status.Errorf(codes.Internal, "%v", err)
+	}
+	return acquired, nil
+}
+
+// GetLeaseHolder returns the holder of the named lease, or "" if it is free.
+func (rb *redisBackend) GetLeaseHolder(ctx context.Context, name string) (string, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "GetLeaseHolder, name: %s, failed to connect to redis: %v", name, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	holder, err := redis.String(redisConn.Do("GET", leaseKey(name)))
+	if err == redis.ErrNil {
+		return "", nil
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get lease holder, name: %s", name)
+		return "", status.Errorf(codes.Internal, "%v", err)
+	}
+	return holder, nil
+}
+
+// ReleaseLease frees the named lease if the holder has it.
+func (rb *redisBackend) ReleaseLease(ctx context.Context, name, holder string) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "ReleaseLease, name: %s, failed to connect to redis: %v", name, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = releaseLeaseScript.Do(redisConn, leaseKey(name), holder)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to release lease, name: %s", name)
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
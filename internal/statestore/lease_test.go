@@ -0,0 +1,79 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+)
+
+func TestLeases(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	holder, err := service.GetLeaseHolder(ctx, "synchronizer")
+	require.NoError(t, err)
+	require.Equal(t, "", holder)
+
+	acquired, err := service.AcquireLease(ctx, "synchronizer", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// The holder can renew the lease, but others can't take it.
+	acquired, err = service.AcquireLease(ctx, "synchronizer", "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	acquired, err = service.AcquireLease(ctx, "synchronizer", "b", time.Minute)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	// Leases are independent of each other.
+	acquired, err = service.AcquireLease(ctx, "synchronizer/eu", "b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	holder, err = service.GetLeaseHolder(ctx, "synchronizer")
+	require.NoError(t, err)
+	require.Equal(t, "a", holder)
+
+	// Only the holder can release the lease.
+	require.NoError(t, service.ReleaseLease(ctx, "synchronizer", "b"))
+	holder, err = service.GetLeaseHolder(ctx, "synchronizer")
+	require.NoError(t, err)
+	require.Equal(t, "a", holder)
+
+	require.NoError(t, service.ReleaseLease(ctx, "synchronizer", "a"))
+	acquired, err = service.AcquireLease(ctx, "synchronizer", "b", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	_, err = service.AcquireLease(ctx, "synchronizer", "a", time.Minute)
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
@@ -0,0 +1,121 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func matchRecordKey(matchID string) string {
+	return "match_history/" + matchID
+}
+
+// ticketMatchesKey lists the ids of the recorded matches of a ticket, newest
+// first.
+func ticketMatchesKey(ticketID string) string {
+	return "ticket_matches/" + ticketID
+}
+
+// RecordMatch stores the record of a match, and adds it to the history of
+// each of its tickets, for the retention period.
+func (rb *redisBackend) RecordMatch(ctx context.Context, record *pb.MatchRecord, retention time.Duration) error {
+	ms := retention.Milliseconds()
+	if ms <= 0 {
+		return status.Errorf(codes.InvalidArgument, "RecordMatch, id: %s, retention must be at least 1ms, got %v", record.GetMatchId(), retention)
+	}
+
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "RecordMatch, id: %s, failed to connect to redis: %v", record.GetMatchId(), err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	value, err := proto.Marshal(record)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to marshal the match record proto, id: %s", record.GetMatchId())
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	if err = redisConn.Send("MULTI"); err != nil {
+		return errors.Wrap(err, "error starting redis multi")
+	}
+	if err = redisConn.Send("SET", matchRecordKey(record.GetMatchId()), value, "PX", ms); err != nil {
+		return errors.Wrap(err, "error sending match record")
+	}
+	for _, id := range record.GetTicketIds() {
+		if err = redisConn.Send("LPUSH", ticketMatchesKey(id), record.GetMatchId()); err != nil {
+			return errors.Wrap(err, "error sending ticket match history update")
+		}
+		if err = redisConn.Send("PEXPIRE", ticketMatchesKey(id), ms); err != nil {
+			return errors.Wrap(err, "error sending ticket match history expiry")
+		}
+	}
+	if _, err = redisConn.Do("EXEC"); err != nil {
+		err = errors.Wrapf(err, "failed to record match, id: %s", record.GetMatchId())
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	return nil
+}
+
+// GetTicketMatchHistory returns the recorded matches of the ticket, newest
+// first.  Records which expired are skipped.
+func (rb *redisBackend) GetTicketMatchHistory(ctx context.Context, ticketID string) ([]*pb.MatchRecord, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "GetTicketMatchHistory, id: %s, failed to connect to redis: %v", ticketID, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	matchIDs, err := redis.Strings(redisConn.Do("LRANGE", ticketMatchesKey(ticketID), 0, -1))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the match history of ticket, id: %s", ticketID)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	if len(matchIDs) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]interface{}, 0, len(matchIDs))
+	for _, id := range matchIDs {
+		keys = append(keys, matchRecordKey(id))
+	}
+	values, err := redis.ByteSlices(redisConn.Do("MGET", keys...))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the match records of ticket, id: %s", ticketID)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	records := make([]*pb.MatchRecord, 0, len(values))
+	for i, b := range values {
+		if b == nil {
+			continue
+		}
+		r := &pb.MatchRecord{}
+		if err = proto.Unmarshal(b, r); err != nil {
+			err = errors.Wrapf(err, "failed to unmarshal the match record proto, id: %s", matchIDs[i])
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestMatchHistory(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	first := &pb.MatchRecord{MatchId: "first", MatchProfile: "1v1", TicketIds: []string{"1", "2"}, Score: 3}
+	second := &pb.MatchRecord{MatchId: "second", MatchProfile: "ffa", TicketIds: []string{"2", "3"}, BackfillId: "bf"}
+	require.NoError(t, service.RecordMatch(ctx, first, time.Hour))
+	require.NoError(t, service.RecordMatch(ctx, second, time.Hour))
+	// Redis rejects an expiry of 0.
+	err := service.RecordMatch(ctx, &pb.MatchRecord{MatchId: "expired", TicketIds: []string{"2"}}, 0)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	records, err := service.GetTicketMatchHistory(ctx, "2")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.True(t, proto.Equal(second, records[0]))
+	require.True(t, proto.Equal(first, records[1]))
+
+	records, err = service.GetTicketMatchHistory(ctx, "1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "first", records[0].GetMatchId())
+
+	records, err = service.GetTicketMatchHistory(ctx, "unknown")
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	_, err = service.GetTicketMatchHistory(ctx, "2")
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
@@ -16,6 +16,7 @@ package statestore
 
 import (
 	"context"
+	"time"
 
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/telemetry"
@@ -52,13 +53,40 @@ type Service interface {
 	// GetIndexedIDSet returns the ids of all tickets currently indexed.
 	GetIndexedIDSet(ctx context.Context) (map[string]struct{}, error)
 
+	// GetProposalState looks up the stored tickets and backfill of a match proposal in a single round trip.
+	// backfillID can be empty.
+	GetProposalState(ctx context.Context, ticketIDs []string, backfillID string) (*ProposalState, error)
+
 	// GetTickets returns multiple tickets from storage.
 	// Missing tickets are silently ignored.
 	GetTickets(ctx context.Context, ids []string) ([]*pb.Ticket, error)
 
 	// UpdateAssignments update using the request's specified tickets with assignments.
+	// The tickets are deindexed and removed from pending release in the same atomic step.
+	// Tentative assignments must be accepted with AcceptAssignment before they're finalized.
 	UpdateAssignments(ctx context.Context, req *pb.AssignTicketsRequest) (*pb.AssignTicketsResponse, []*pb.Ticket, error)
 
+	// RevokeAssignments removes the assignments of the tickets, returning them to the index
+	// with their original create time.
+	RevokeAssignments(ctx context.Context, ids []string) (*pb.RevokeAssignmentsResponse, error)
+
+	// AcceptAssignment accepts the tentative assignment of the ticket, finalizing the
+	// assignments of its group once all tickets of the group accepted.
+	AcceptAssignment(ctx context.Context, id string) error
+
+	// DeclineAssignment declines the tentative assignment of the ticket, deleting the ticket
+	// and returning the other tickets of its group to the index.
+	DeclineAssignment(ctx context.Context, id string) error
+
+	// CleanupTentativeAssignments cancels the tentative assignments which weren't accepted in time,
+	// deleting the tickets which didn't accept and returning the others to the index.
+	CleanupTentativeAssignments(ctx context.Context) error
+
+	// AssignBackfillTickets assigns the tickets associated with the backfill, deindexing them,
+	// removing them from pending release and clearing them from the backfill in one atomic step.
+	// Fails with Aborted if the backfill changed since the backfill and ticketIDs were read.
+	AssignBackfillTickets(ctx context.Context, backfill *pb.Backfill, ticketIDs []string, assignment *pb.Assignment) (*pb.AssignTicketsResponse, []*pb.Ticket, error)
+
 	// GetAssignments returns the assignment associated with the input ticket id.
 	GetAssignments(ctx context.Context, id string, callback func(*pb.Assignment) error) error
 
@@ -71,6 +99,42 @@ type Service interface {
 	// ReleaseAllTickets releases all pending tickets back to active.
 	ReleaseAllTickets(ctx context.Context) error
 
+	// ReserveTickets reserves the indexed tickets for the profile for the given
+	// number of reservation cycles, skipping tickets reserved by another profile.
+	// Returns the ids which were reserved.
+	ReserveTickets(ctx context.Context, profile string, ids []string, cycles int) ([]string, error)
+
+	// GetTicketReservations returns the profile reserving each reserved ticket.
+	GetTicketReservations(ctx context.Context) (map[string]string, error)
+
+	// AdvanceReservationCycle starts the next reservation cycle of the ticket
+	// partition, expiring the reservations which ran out.  If holder is set,
+	// the cycle is only advanced while holder has the partition's synchronizer
+	// lease.
+	AdvanceReservationCycle(ctx context.Context, partition, holder string) error
+
+	// Match history
+
+	// RecordMatch stores the record of a match, and adds it to the history of
+	// each of its tickets, for the retention period.
+	RecordMatch(ctx context.Context, record *pb.MatchRecord, retention time.Duration) error
+
+	// GetTicketMatchHistory returns the recorded matches of the ticket, newest
+	// first.
+	GetTicketMatchHistory(ctx context.Context, ticketID string) ([]*pb.MatchRecord, error)
+
+	// Leases
+
+	// AcquireLease takes or renews the named lease for the holder, returning
+	// whether the holder has the lease for the ttl.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+
+	// GetLeaseHolder returns the holder of the named lease, or "" if it is free.
+	GetLeaseHolder(ctx context.Context, name string) (string, error)
+
+	// ReleaseLease frees the named lease if the holder has it.
+	ReleaseLease(ctx context.Context, name, holder string) error
+
 	// Backfill
 
 	// CreateBackfill creates a new Backfill in the state storage if one doesn't exist.
@@ -83,6 +147,10 @@ type Service interface {
 	// Returns the Backfill and associated ticketIDs if they exist.
 	GetBackfill(ctx context.Context, id string) (*pb.Backfill, []string, error)
 
+	// WatchBackfill calls the callback with the Backfill of the input id and its
+	// associated ticketIDs until the callback or the lookup of the Backfill fails.
+	WatchBackfill(ctx context.Context, id string, callback func(*pb.Backfill, []string) error) error
+
 	// GetBackfills returns multiple backfills from storage
 	GetBackfills(ctx context.Context, ids []string) ([]*pb.Backfill, error)
 
@@ -96,6 +164,15 @@ type Service interface {
 	// UpdateBackfill updates an existing Backfill with a new data. ticketIDs can be nil.
 	UpdateBackfill(ctx context.Context, backfill *pb.Backfill, ticketIDs []string) error
 
+	// ModifyBackfill applies modify to an existing Backfill and its associated ticketIDs, and stores the result
+	// only if the Backfill did not change in the meantime, applying modify again otherwise.
+	ModifyBackfill(ctx context.Context, id string, modify func(*pb.Backfill, []string) ([]string, error)) (*pb.Backfill, error)
+
+	// RemoveBackfillTickets applies reopen to an existing Backfill with the ticketIDs which were assigned to it
+	// and not removed yet, and stores the result while forgetting they were assigned to it, only if the Backfill
+	// did not change in the meantime, applying reopen again otherwise.  Returns the Backfill and the removed ticketIDs.
+	RemoveBackfillTickets(ctx context.Context, id string, ticketIDs []string, reopen func(*pb.Backfill, []string) error) (*pb.Backfill, []string, error)
+
 	// NewMutex returns an interface of a new distributed mutex with given name
 	NewMutex(key string) RedisLocker
 
@@ -105,6 +182,10 @@ type Service interface {
 	// UpdateAcknowledgmentTimestamp updates Backfill's last acknowledged time
 	UpdateAcknowledgmentTimestamp(ctx context.Context, id string) error
 
+	// WatchBackfillEvents calls the callback with the backfill events recorded after the call,
+	// of the backfill with the input id or of all backfills if id is empty, until the callback fails.
+	WatchBackfillEvents(ctx context.Context, id string, callback func(*pb.BackfillEvent) error) error
+
 	// GetExpiredBackfillIDs gets all backfill IDs which are expired
 	GetExpiredBackfillIDs(ctx context.Context) ([]string, error)
 
@@ -117,6 +198,27 @@ type Service interface {
 	// GetIndexedBackfills returns a map containing the IDs and
 	// the Generation number of the backfills currently indexed.
 	GetIndexedBackfills(ctx context.Context) (map[string]int, error)
+
+	// Wasm modules
+
+	// PutWasmModule stores a WebAssembly match function module under name,
+	// replacing any module already stored under it.
+	PutWasmModule(ctx context.Context, name string, module []byte) error
+
+	// GetWasmModule gets the WebAssembly match function module stored under name.
+	// This method fails with NotFound if there is no such module.
+	GetWasmModule(ctx context.Context, name string) ([]byte, error)
+}
+
+// ProposalState is the stored state of the tickets and backfill of a match proposal.
+type ProposalState struct {
+	// Tickets are the proposed tickets which exist.
+	Tickets []*pb.Ticket
+	// ActiveTicketIDs are the ids of the proposed tickets which are indexed and
+	// not pending release, matching what GetIndexedIDSet would return for them.
+	ActiveTicketIDs map[string]struct{}
+	// Backfill is the proposed backfill, or nil if it doesn't exist.
+	Backfill *pb.Backfill
 }
 
 // New creates a Service based on the configuration.
@@ -0,0 +1,247 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ticketReservations maps the ids of the default partition's tickets to
+	// "<expiry cycle>:<profile>".  Each other partition has its own map, see
+	// reservationsKey.
+	ticketReservations = "ticket_reservations"
+	reservationCycle   = "reservation_cycle"
+	// reservationPartitions holds the partitions with ticket reservations.
+	reservationPartitions = "reservation_partitions"
+
+	// synchronizerPartitionKey names the string_arg which partitions tickets
+	// between synchronizer shards, as configured for the backend.
+	synchronizerPartitionKey = "synchronizerPartitionKey"
+)
+
+// Reservations expire with the cycles of the synchronizer owning the tickets'
+// partition, so every partition counts its own cycles.
+func reservationsKey(partition string) string {
+	if partition == "" {
+		return ticketReservations
+	}
+	return ticketReservations + "/" + partition
+}
+
+func reservationCycleKey(partition string) string {
+	if partition == "" {
+		return reservationCycle
+	}
+	return reservationCycle + "/" + partition
+}
+
+// reserveTicketsScript reserves the indexed tickets among ARGV[4:] for the
+// profile ARGV[2], until ARGV[3] more reservation cycles of the partition
+// ARGV[1] have passed.  Tickets reserved by another profile are skipped, and
+// the reserved ids returned.
+var reserveTicketsScript = redis.NewScript(4, `
+local cycle = tonumber(redis.call('GET', KEYS[2]) or '0')
+local expiry = cycle + tonumber(ARGV[3])
+local reserved = {}
+for i = 4, #ARGV do
+  local id = ARGV[i]
+  local ok = redis.call('SISMEMBER', KEYS[3], id) == 1
+  local held = redis.call('HGET', KEYS[1], id)
+  if ok and held then
+    local sep = string.find(held, ':', 1, true)
+    if tonumber(string.sub(held, 1, sep - 1)) > cycle and string.sub(held, sep + 1) ~= ARGV[2] then
+      ok = false
+    end
+  end
+  if ok then
+    redis.call('HSET', KEYS[1], id, expiry .. ':' .. ARGV[2])
+    table.insert(reserved, id)
+  end
+end
+if #reserved > 0 then
+  redis.call('SADD', KEYS[4], ARGV[1])
+end
+return reserved
+`)
+
+// advanceReservationCycleScript starts the next reservation cycle of a
+// partition, and removes the reservations which expired with it.  If ARGV[1]
+// is set, the cycle is only advanced while it holds the lease KEYS[3].
+var advanceReservationCycleScript = redis.NewScript(3, `
+if ARGV[1] ~= '' and redis.call('GET', KEYS[3]) ~= ARGV[1] then
+  return -1
+end
+local cycle = redis.call('INCR', KEYS[2])
+local all = redis.call('HGETALL', KEYS[1])
+for i = 1, #all, 2 do
+  local held = all[i + 1]
+  local sep = string.find(held, ':', 1, true)
+  if tonumber(string.sub(held, 1, sep - 1)) <= cycle then
+    redis.call('HDEL', KEYS[1], all[i])
+  end
+end
+return cycle
+`)
+
+// ReserveTickets reserves the tickets for the profile for the given number of
+// reservation cycles, returning the ids which were reserved.  Tickets which
+// aren't indexed, or are reserved by another profile, are skipped.  Reserving
+// a ticket the profile already holds extends the reservation.
+func (rb *redisBackend) ReserveTickets(ctx context.Context, profile string, ids []string, cycles int) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "ReserveTickets, profile: %s, failed to connect to redis: %v", profile, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	// Look up the tickets' partitions first, the script can't read tickets.
+	// Deleted tickets are skipped, as they are no longer indexed.
+	tickets, err := getTickets(redisConn, ids)
+	if err != nil {
+		return nil, err
+	}
+	key := rb.cfg.GetString(synchronizerPartitionKey)
+	var partitions []string
+	byPartition := map[string][]interface{}{}
+	for _, t := range tickets {
+		partition := ""
+		if key != "" {
+			partition = t.GetSearchFields().GetStringArgs()[key]
+		}
+		if _, ok := byPartition[partition]; !ok {
+			partitions = append(partitions, partition)
+		}
+		byPartition[partition] = append(byPartition[partition], t.GetId())
+	}
+
+	reservedSet := map[string]bool{}
+	for _, partition := range partitions {
+		args := make([]interface{}, 0, len(byPartition[partition])+7)
+		args = append(args, reservationsKey(partition), reservationCycleKey(partition), allTickets, reservationPartitions, partition, profile, cycles)
+		args = append(args, byPartition[partition]...)
+
+		reserved, err := redis.Strings(reserveTicketsScript.Do(redisConn, args...))
+		if err != nil {
+			err = errors.Wrapf(err, "failed to reserve tickets, profile: %s", profile)
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		for _, id := range reserved {
+			reservedSet[id] = true
+		}
+	}
+
+	// Reserved ids are returned in the order they were asked for.
+	var reserved []string
+	for _, id := range ids {
+		if reservedSet[id] {
+			reserved = append(reserved, id)
+			delete(reservedSet, id)
+		}
+	}
+	return reserved, nil
+}
+
+// GetTicketReservations returns the profile reserving each reserved ticket.
+func (rb *redisBackend) GetTicketReservations(ctx context.Context) (map[string]string, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "GetTicketReservations, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	partitions, err := redis.Strings(redisConn.Do("SMEMBERS", reservationPartitions))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting reservation partitions %v", err)
+	}
+	if len(partitions) == 0 {
+		return map[string]string{}, nil
+	}
+
+	if err = redisConn.Send("MULTI"); err != nil {
+		return nil, errors.Wrap(err, "error starting redis multi")
+	}
+	for _, partition := range partitions {
+		if err = redisConn.Send("GET", reservationCycleKey(partition)); err != nil {
+			return nil, errors.Wrap(err, "error sending reservation cycle lookup")
+		}
+		if err = redisConn.Send("HGETALL", reservationsKey(partition)); err != nil {
+			return nil, errors.Wrap(err, "error sending ticket reservations lookup")
+		}
+	}
+	replies, err := redis.Values(redisConn.Do("EXEC"))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting ticket reservations %v", err)
+	}
+
+	r := map[string]string{}
+	for i := range partitions {
+		cycle, err := redis.Int64(replies[2*i], nil)
+		if err != nil && err != redis.ErrNil {
+			return nil, status.Errorf(codes.Internal, "error reading reservation cycle %v", err)
+		}
+		held, err := redis.StringMap(replies[2*i+1], nil)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "error reading ticket reservations %v", err)
+		}
+
+		for id, v := range held {
+			parts := strings.SplitN(v, ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			expiry, err := strconv.ParseInt(parts[0], 10, 64)
+			if err != nil || expiry <= cycle {
+				continue
+			}
+			r[id] = parts[1]
+		}
+	}
+	return r, nil
+}
+
+// AdvanceReservationCycle starts the next reservation cycle of the partition,
+// expiring reservations which were made for one cycle less than have passed.
+// If holder is set, the cycle is only advanced while holder has the
+// partition's synchronizer lease, and FailedPrecondition is returned
+// otherwise.
+func (rb *redisBackend) AdvanceReservationCycle(ctx context.Context, partition, holder string) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "AdvanceReservationCycle, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	cycle, err := redis.Int64(advanceReservationCycleScript.Do(redisConn, reservationsKey(partition), reservationCycleKey(partition), leaseKey(SynchronizerLease(partition)), holder))
+	if err != nil {
+		err = errors.Wrap(err, "failed to advance the reservation cycle")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if cycle < 0 {
+		return status.Errorf(codes.FailedPrecondition, "%s doesn't hold the synchronizer lease of partition %q", holder, partition)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func TestTicketReservations(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, service.CreateTicket(ctx, &pb.Ticket{Id: id}))
+		require.NoError(t, service.IndexTicket(ctx, &pb.Ticket{Id: id}))
+	}
+
+	reservations, err := service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+
+	// Tickets which aren't indexed can't be reserved.
+	reserved, err := service.ReserveTickets(ctx, "br", []string{"1", "2", "unknown"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2"}, reserved)
+
+	// Another profile only gets the tickets which aren't reserved.
+	reserved, err = service.ReserveTickets(ctx, "1v1", []string{"2", "3"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"3"}, reserved)
+
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"1": "br", "2": "br", "3": "1v1"}, reservations)
+
+	// The 1v1 reservation expires after one cycle, while br extends ticket 2.
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "", ""))
+	reserved, err = service.ReserveTickets(ctx, "br", []string{"2"}, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, reserved)
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"1": "br", "2": "br"}, reservations)
+
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "", ""))
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"2": "br"}, reservations)
+
+	// Once expired, tickets can be reserved by others.
+	reserved, err = service.ReserveTickets(ctx, "1v1", []string{"1"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, reserved)
+
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "", ""))
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "", ""))
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	_, err = service.ReserveTickets(ctx, "br", []string{"1"}, 1)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+	require.Contains(t, status.Convert(err).Message(), "ReserveTickets, profile: br, failed to connect to redis:")
+	_, err = service.GetTicketReservations(ctx)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+	err = service.AdvanceReservationCycle(ctx, "", "")
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
+
+func TestTicketReservationPartitions(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	cfg.(*viper.Viper).Set(synchronizerPartitionKey, "region")
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for id, region := range map[string]string{"eu": "eu", "us": "us", "none": ""} {
+		ticket := &pb.Ticket{Id: id}
+		if region != "" {
+			ticket.SearchFields = &pb.SearchFields{StringArgs: map[string]string{"region": region}}
+		}
+		require.NoError(t, service.CreateTicket(ctx, ticket))
+		require.NoError(t, service.IndexTicket(ctx, ticket))
+	}
+
+	reserved, err := service.ReserveTickets(ctx, "p", []string{"none", "us", "eu"}, 1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"none", "us", "eu"}, reserved)
+
+	// Each partition's reservations expire with its own cycles.
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "eu", ""))
+	reservations, err := service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"none": "p", "us": "p"}, reservations)
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "", ""))
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"us": "p"}, reservations)
+
+	// Only the holder of the partition's synchronizer lease advances its cycle.
+	acquired, err := service.AcquireLease(ctx, SynchronizerLease("us"), "leader", time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+	err = service.AdvanceReservationCycle(ctx, "us", "standby")
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"us": "p"}, reservations)
+	require.NoError(t, service.AdvanceReservationCycle(ctx, "us", "leader"))
+	reservations, err = service.GetTicketReservations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, reservations)
+}
@@ -0,0 +1,388 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+const (
+	// tentativeAssignmentPrefix keys a hash of the group's ticket ids to
+	// their acceptance state.
+	tentativeAssignmentPrefix = "tentative_assignment/"
+	// ticketTentativeAssignmentPrefix keys the group id of a ticket.
+	ticketTentativeAssignmentPrefix = "ticket_tentative_assignment/"
+	// tentativeAssignmentDeadlines is a sorted set of group ids, scored by
+	// the time they're cancelled at if not accepted.
+	tentativeAssignmentDeadlines = "tentative_assignment_deadlines"
+
+	assignmentPending  = "pending"
+	assignmentAccepted = "accepted"
+)
+
+// acceptAssignmentScript marks the ARGV[1] ticket of the ARGV[2] group as
+// accepted.  Once every ticket of the group accepted, it finalizes the group in
+// the same step: the tickets are set to their finalized values with an expiry
+// of ARGV[3] ms, and the group is removed, including from the KEYS[1]
+// deadlines.  ARGV[4] is the number of the group's tickets which follow, each
+// as its id, its value read beforehand and its finalized value, both empty for
+// a missing ticket.
+//
+// Returns nil if the ticket has no tentative assignment in the group, 0 while
+// other tickets haven't accepted, and 1 once finalized.  Returns -1 without
+// accepting if the group would be finalized, but the tickets which follow
+// aren't the current ones, so that the caller reads them again.
+var acceptAssignmentScript = redis.NewScript(1, `
+local group = redis.call('GET', '`+ticketTentativeAssignmentPrefix+`' .. ARGV[1])
+if group ~= ARGV[2] then
+  return nil
+end
+local key = '`+tentativeAssignmentPrefix+`' .. group
+local states = redis.call('HGETALL', key)
+local pending = false
+for i = 1, #states, 2 do
+  if states[i] ~= ARGV[1] and states[i + 1] ~= '`+assignmentAccepted+`' then
+    pending = true
+  end
+end
+if pending then
+  redis.call('HSET', key, ARGV[1], '`+assignmentAccepted+`')
+  return 0
+end
+
+local n = tonumber(ARGV[4])
+if n * 2 ~= #states then
+  return -1
+end
+local finalized = {}
+for i = 5, 4 + n * 3, 3 do
+  local current = redis.call('GET', ARGV[i]) or ''
+  if redis.call('HEXISTS', key, ARGV[i]) == 0 or current ~= ARGV[i + 1] then
+    return -1
+  end
+  finalized[ARGV[i]] = ARGV[i + 2]
+end
+
+for i = 1, #states, 2 do
+  local id = states[i]
+  if finalized[id] ~= '' then
+    redis.call('SET', id, finalized[id], 'PX', ARGV[3], 'XX')
+  end
+  redis.call('DEL', '`+ticketTentativeAssignmentPrefix+`' .. id)
+end
+redis.call('DEL', key)
+redis.call('ZREM', KEYS[1], group)
+return 1
+`)
+
+// maxFinalizeAttempts bounds how many times the tickets of a tentative
+// assignment group are read again when they change before it is finalized or
+// cancelled.
+const maxFinalizeAttempts = 5
+
+// cancelAssignmentGroupScript cancels the ARGV[1] group.  If ARGV[2] isn't
+// empty, that ticket declined: the script does nothing unless it's in the
+// group, then deletes it and returns the others to the index KEYS[2].
+// Otherwise the group expired: the tickets which accepted return to the index,
+// and the others are deleted.  Deleted tickets are also removed from pending
+// release KEYS[3].  Either way the group is removed, including from the
+// KEYS[1] deadlines.  ARGV[3] is the number of tickets to return to the index
+// which follow, each as its id, its value read beforehand and its value
+// without the assignment, both empty for a missing ticket.
+//
+// Returns nil if the group doesn't exist, or doesn't include the declining
+// ticket, and 1 once cancelled.  Returns -1 without cancelling if the tickets
+// which follow aren't the current ones, so that the caller reads them again.
+var cancelAssignmentGroupScript = redis.NewScript(3, `
+local group = ARGV[1]
+if ARGV[2] ~= '' and redis.call('GET', '`+ticketTentativeAssignmentPrefix+`' .. ARGV[2]) ~= group then
+  return nil
+end
+local key = '`+tentativeAssignmentPrefix+`' .. group
+local states = redis.call('HGETALL', key)
+if #states == 0 then
+  return nil
+end
+
+local requeue = {}
+local count = 0
+for i = 1, #states, 2 do
+  local id = states[i]
+  if (ARGV[2] ~= '' and id ~= ARGV[2]) or (ARGV[2] == '' and states[i + 1] == '`+assignmentAccepted+`') then
+    requeue[id] = false
+    count = count + 1
+  end
+end
+local n = tonumber(ARGV[3])
+if n ~= count then
+  return -1
+end
+for i = 4, 3 + n * 3, 3 do
+  if requeue[ARGV[i]] ~= false or (redis.call('GET', ARGV[i]) or '') ~= ARGV[i + 1] then
+    return -1
+  end
+  requeue[ARGV[i]] = ARGV[i + 2]
+end
+
+for i = 1, #states, 2 do
+  local id = states[i]
+  local value = requeue[id]
+  if value == nil then
+    redis.call('DEL', id)
+    redis.call('SREM', KEYS[2], id)
+    redis.call('ZREM', KEYS[3], id)
+  elseif value ~= '' then
+    redis.call('SET', id, value, 'XX')
+    redis.call('SADD', KEYS[2], id)
+  end
+  redis.call('DEL', '`+ticketTentativeAssignmentPrefix+`' .. id)
+end
+redis.call('DEL', key)
+redis.call('ZREM', KEYS[1], group)
+return 1
+`)
+
+// AcceptAssignment accepts the tentative assignment of the ticket.  Once all
+// tickets of its group accepted, their assignments are finalized in the same
+// atomic step.
+func (rb *redisBackend) AcceptAssignment(ctx context.Context, id string) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "AcceptAssignment, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	assignmentTimeout := int64(getAssignedDeleteTimeout(rb.cfg) / time.Millisecond)
+	for attempt := 0; attempt < maxFinalizeAttempts; attempt++ {
+		group, err := redis.String(redisConn.Do("GET", ticketTentativeAssignmentPrefix+id))
+		if err == redis.ErrNil {
+			return status.Errorf(codes.NotFound, "Ticket id: %s has no tentative assignment", id)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get the tentative assignment of ticket, id: %s", id)
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+
+		args, err := finalizedGroupArgs(redisConn, id, group)
+		if err != nil {
+			return err
+		}
+		args = append([]interface{}{tentativeAssignmentDeadlines, id, group, assignmentTimeout}, args...)
+		result, err := redis.Int(acceptAssignmentScript.Do(redisConn, args...))
+		if err == redis.ErrNil {
+			// The group was finalized or canceled meanwhile.
+			return status.Errorf(codes.NotFound, "Ticket id: %s has no tentative assignment", id)
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to accept the assignment of ticket, id: %s", id)
+			return status.Errorf(codes.Internal, "%v", err)
+		}
+		if result >= 0 {
+			return nil
+		}
+	}
+	return status.Errorf(codes.Aborted, "AcceptAssignment, id: %s, the tickets of its tentative assignment kept changing", id)
+}
+
+// finalizedGroupArgs returns the acceptAssignmentScript arguments describing
+// the tickets of the group, if accepting the assignment of the ticket would
+// finalize it.  The group is otherwise left to the script.
+func finalizedGroupArgs(redisConn redis.Conn, id, group string) ([]interface{}, error) {
+	states, err := redis.StringMap(redisConn.Do("HGETALL", tentativeAssignmentPrefix+group))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get tentative assignment, group: %s", group)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	ids := make([]interface{}, 0, len(states))
+	for ticketID, state := range states {
+		if ticketID != id && state != assignmentAccepted {
+			return []interface{}{0}, nil
+		}
+		ids = append(ids, ticketID)
+	}
+	if len(ids) == 0 {
+		return []interface{}{0}, nil
+	}
+
+	return finalizedTicketArgs(redisConn, ids)
+}
+
+// finalizedTicketArgs returns the number of tickets followed by each ticket's
+// id, its current value and its value once its assignment is finalized, both
+// empty if the ticket is missing.
+func finalizedTicketArgs(redisConn redis.Conn, ids []interface{}) ([]interface{}, error) {
+	return updatedTicketArgs(redisConn, ids, func(t *pb.Ticket) {
+		if t.Assignment != nil {
+			t.Assignment.Tentative = false
+		}
+	})
+}
+
+// updatedTicketArgs returns the number of tickets followed by each ticket's
+// id, its current value and its value after applying update, both empty if
+// the ticket is missing.
+func updatedTicketArgs(redisConn redis.Conn, ids []interface{}, update func(*pb.Ticket)) ([]interface{}, error) {
+	values, err := redis.ByteSlices(redisConn.Do("MGET", ids...))
+	if err != nil {
+		err = errors.Wrap(err, "failed to get the tickets of tentative assignment")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	args := []interface{}{len(ids)}
+	for i, value := range values {
+		if value == nil {
+			args = append(args, ids[i], "", "")
+			continue
+		}
+		t := &pb.Ticket{}
+		if err = proto.Unmarshal(value, t); err != nil {
+			err = errors.Wrapf(err, "failed to unmarshal ticket from redis %s", ids[i])
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		update(t)
+		updated, err := proto.Marshal(t)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal ticket %s", ids[i])
+		}
+		args = append(args, ids[i], value, updated)
+	}
+	return args, nil
+}
+
+// DeclineAssignment declines the tentative assignment of the ticket, which
+// cancels its group.  The ticket is deleted, and the other tickets of the
+// group return to the index.
+func (rb *redisBackend) DeclineAssignment(ctx context.Context, id string) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "DeclineAssignment, id: %s, failed to connect to redis: %v", id, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	group, err := redis.String(redisConn.Do("GET", ticketTentativeAssignmentPrefix+id))
+	if err == redis.ErrNil {
+		return status.Errorf(codes.NotFound, "Ticket id: %s has no tentative assignment", id)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get the tentative assignment of ticket, id: %s", id)
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	cancelled, err := cancelAssignmentGroup(redisConn, group, id)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		// The group was finalized or canceled meanwhile.
+		return status.Errorf(codes.NotFound, "Ticket id: %s has no tentative assignment", id)
+	}
+	return nil
+}
+
+// CleanupTentativeAssignments cancels the tentative assignment groups which
+// weren't accepted by all their tickets within the acceptance timeout.  The
+// tickets which didn't accept are deleted, and the others return to the index.
+func (rb *redisBackend) CleanupTentativeAssignments(ctx context.Context) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "CleanupTentativeAssignments, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	groups, err := redis.Strings(redisConn.Do("ZRANGEBYSCORE", tentativeAssignmentDeadlines, "-inf", time.Now().UnixNano()))
+	if err != nil {
+		err = errors.Wrap(err, "failed to get expired tentative assignments")
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	for _, group := range groups {
+		// Groups accepted or declined meanwhile are skipped.
+		if _, err = cancelAssignmentGroup(redisConn, group, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancelAssignmentGroup cancels the group in one atomic step, see
+// cancelAssignmentGroupScript.  Returns false if the group doesn't exist, or
+// doesn't include the declined ticket.
+func cancelAssignmentGroup(redisConn redis.Conn, group, declined string) (bool, error) {
+	for attempt := 0; attempt < maxFinalizeAttempts; attempt++ {
+		args, err := requeuedGroupArgs(redisConn, group, declined)
+		if err != nil {
+			return false, err
+		}
+		args = append([]interface{}{tentativeAssignmentDeadlines, allTickets, proposedTicketIDs, group, declined}, args...)
+		result, err := redis.Int(cancelAssignmentGroupScript.Do(redisConn, args...))
+		if err == redis.ErrNil {
+			return false, nil
+		}
+		if err != nil {
+			err = errors.Wrapf(err, "failed to cancel tentative assignment, group: %s", group)
+			return false, status.Errorf(codes.Internal, "%v", err)
+		}
+		if result >= 0 {
+			return true, nil
+		}
+	}
+	return false, status.Errorf(codes.Aborted, "failed to cancel tentative assignment, group: %s, its tickets kept changing", group)
+}
+
+// requeuedGroupArgs returns the cancelAssignmentGroupScript arguments
+// describing the tickets of the group which return to the index: all but the
+// declined ticket, or the ones which accepted if declined is empty.
+func requeuedGroupArgs(redisConn redis.Conn, group, declined string) ([]interface{}, error) {
+	states, err := redis.StringMap(redisConn.Do("HGETALL", tentativeAssignmentPrefix+group))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to get tentative assignment, group: %s", group)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+	ids := make([]interface{}, 0, len(states))
+	for id, state := range states {
+		if (declined != "" && id != declined) || (declined == "" && state == assignmentAccepted) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return []interface{}{0}, nil
+	}
+
+	return updatedTicketArgs(redisConn, ids, func(t *pb.Ticket) {
+		t.Assignment = nil
+	})
+}
+
+func getAssignmentAcceptanceTimeout(cfg config.View) time.Duration {
+	const (
+		name = "assignmentAcceptanceTimeout"
+		// Default time for all tickets of a tentative assignment to accept it.
+		defaultAssignmentAcceptanceTimeout time.Duration = 30 * time.Second
+	)
+
+	if !cfg.IsSet(name) {
+		return defaultAssignmentAcceptanceTimeout
+	}
+
+	return cfg.GetDuration(name)
+}
@@ -0,0 +1,251 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+func createTentativeAssignment(ctx context.Context, t *testing.T, service Service, ids ...string) {
+	for _, id := range ids {
+		ticket := &pb.Ticket{Id: id, CreateTime: timestamppb.New(time.Unix(100, 0))}
+		require.NoError(t, service.CreateTicket(ctx, ticket))
+		require.NoError(t, service.IndexTicket(ctx, ticket))
+	}
+
+	resp, tickets, err := service.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: ids, Assignment: &pb.Assignment{Connection: "server"}}},
+		Tentative:   true,
+	})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetFailures())
+	require.Len(t, tickets, len(ids))
+
+	for _, id := range ids {
+		ticket, err := service.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.True(t, ticket.GetAssignment().GetTentative())
+	}
+
+	indexed, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	for _, id := range ids {
+		require.NotContains(t, indexed, id)
+	}
+}
+
+func TestAcceptAssignment(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "1", "2")
+
+	require.NoError(t, service.AcceptAssignment(ctx, "1"))
+	// Accepting twice doesn't finalize the assignment without the other ticket.
+	require.NoError(t, service.AcceptAssignment(ctx, "1"))
+	ticket, err := service.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.True(t, ticket.GetAssignment().GetTentative())
+
+	require.NoError(t, service.AcceptAssignment(ctx, "2"))
+	for _, id := range []string{"1", "2"} {
+		ticket, err = service.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.False(t, ticket.GetAssignment().GetTentative())
+		require.Equal(t, "server", ticket.GetAssignment().GetConnection())
+	}
+
+	// Finalized assignments can't be accepted or declined anymore.
+	err = service.AcceptAssignment(ctx, "1")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+	err = service.DeclineAssignment(ctx, "2")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	err = service.AcceptAssignment(ctx, "1")
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
+
+func TestAcceptAssignmentStaleTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "1", "2")
+	require.NoError(t, service.AcceptAssignment(ctx, "1"))
+
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	group, err := redis.String(c.Do("GET", ticketTentativeAssignmentPrefix+"2"))
+	require.NoError(t, err)
+
+	// Tickets which changed since they were read, or a missing ticket, make the
+	// script ask for them again, without accepting.
+	for name, args := range map[string][]interface{}{
+		"missing ticket": {1, "1", "", ""},
+		"stale ticket":   {2, "1", "stale", "finalized", "2", "stale", "finalized"},
+	} {
+		args = append([]interface{}{tentativeAssignmentDeadlines, "2", group, 1000}, args...)
+		result, err := redis.Int(acceptAssignmentScript.Do(c, args...))
+		require.NoError(t, err, name)
+		require.Equal(t, -1, result, name)
+		state, err := redis.String(c.Do("HGET", tentativeAssignmentPrefix+group, "2"))
+		require.NoError(t, err, name)
+		require.Equal(t, assignmentPending, state, name)
+	}
+
+	// AcceptAssignment reads them again.
+	require.NoError(t, service.AcceptAssignment(ctx, "2"))
+	ticket, err := service.GetTicket(ctx, "2")
+	require.NoError(t, err)
+	require.False(t, ticket.GetAssignment().GetTentative())
+}
+
+func TestDeclineAssignment(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "1", "2", "3")
+	require.NoError(t, service.AcceptAssignment(ctx, "1"))
+	require.NoError(t, service.DeclineAssignment(ctx, "2"))
+
+	// The decliner is deleted, the others are back in the index without assignment.
+	_, err := service.GetTicket(ctx, "2")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+	indexed, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"1": {}, "3": {}}, indexed)
+	for _, id := range []string{"1", "3"} {
+		ticket, err := service.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.Nil(t, ticket.GetAssignment())
+		require.Equal(t, int64(100), ticket.GetCreateTime().GetSeconds())
+	}
+
+	err = service.AcceptAssignment(ctx, "3")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+}
+
+func TestDeclineAssignmentStaleTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "1", "2")
+
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	group, err := redis.String(c.Do("GET", ticketTentativeAssignmentPrefix+"2"))
+	require.NoError(t, err)
+
+	// Tickets which changed since they were read, or aren't the ones to
+	// requeue, make the script ask for them again, without cancelling.
+	for name, args := range map[string][]interface{}{
+		"missing ticket": {0},
+		"stale ticket":   {1, "1", "stale", "requeued"},
+		"other ticket":   {1, "2", "", ""},
+	} {
+		args = append([]interface{}{tentativeAssignmentDeadlines, allTickets, proposedTicketIDs, group, "2"}, args...)
+		result, err := redis.Int(cancelAssignmentGroupScript.Do(c, args...))
+		require.NoError(t, err, name)
+		require.Equal(t, -1, result, name)
+		ticket, err := service.GetTicket(ctx, "1")
+		require.NoError(t, err, name)
+		require.True(t, ticket.GetAssignment().GetTentative(), name)
+	}
+
+	// DeclineAssignment reads them again.
+	require.NoError(t, service.DeclineAssignment(ctx, "2"))
+	ticket, err := service.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.Nil(t, ticket.GetAssignment())
+	indexed, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"1": {}}, indexed)
+}
+
+func TestCleanupTentativeAssignments(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "1", "2")
+	require.NoError(t, service.AcceptAssignment(ctx, "1"))
+
+	// Move the deadline of the group to the past.
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	groups, err := redis.Strings(c.Do("ZRANGE", tentativeAssignmentDeadlines, 0, -1))
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	_, err = c.Do("ZADD", tentativeAssignmentDeadlines, 0, groups[0])
+	require.NoError(t, err)
+
+	require.NoError(t, service.CleanupTentativeAssignments(ctx))
+	groups, err = redis.Strings(c.Do("ZRANGE", tentativeAssignmentDeadlines, 0, -1))
+	require.NoError(t, err)
+	require.Empty(t, groups)
+
+	// The acceptor is requeued, the ticket which didn't accept is deleted.
+	ticket, err := service.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.Nil(t, ticket.GetAssignment())
+	_, err = service.GetTicket(ctx, "2")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+	indexed, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"1": {}}, indexed)
+
+	// Nothing is left to clean up.
+	require.NoError(t, service.CleanupTentativeAssignments(ctx))
+	ticket, err = service.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.Nil(t, ticket.GetAssignment())
+}
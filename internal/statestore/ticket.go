@@ -22,10 +22,12 @@ import (
 	"github.com/cenkalti/backoff"
 	"github.com/gomodule/redigo/redis"
 	"github.com/pkg/errors"
+	"github.com/rs/xid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"open-match.dev/open-match/internal/config"
+	"open-match.dev/open-match/internal/ipb"
 	"open-match.dev/open-match/pkg/pb"
 )
 
@@ -187,6 +189,126 @@ func (rb *redisBackend) GetIndexedIDSet(ctx context.Context) (map[string]struct{
 	return r, nil
 }
 
+// GetProposalState looks up the tickets of ticketIDs, which of them are indexed
+// and not pending release, and the backfill of backfillID unless it's empty,
+// in a single round trip.
+func (rb *redisBackend) GetProposalState(ctx context.Context, ticketIDs []string, backfillID string) (*ProposalState, error) {
+	ps := &ProposalState{ActiveTicketIDs: map[string]struct{}{}}
+	if len(ticketIDs) == 0 && backfillID == "" {
+		return ps, nil
+	}
+
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "GetProposalState, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	err = redisConn.Send("MULTI")
+	if err != nil {
+		return nil, errors.Wrap(err, "error starting redis multi")
+	}
+	if len(ticketIDs) > 0 {
+		queryParams := make([]interface{}, len(ticketIDs))
+		for i, id := range ticketIDs {
+			queryParams[i] = id
+		}
+		if err = redisConn.Send("MGET", queryParams...); err != nil {
+			return nil, errors.Wrap(err, "error sending tickets lookup")
+		}
+		for _, id := range ticketIDs {
+			if err = redisConn.Send("SISMEMBER", allTickets, id); err != nil {
+				return nil, errors.Wrap(err, "error sending ticket index lookup")
+			}
+			if err = redisConn.Send("ZSCORE", proposedTicketIDs, id); err != nil {
+				return nil, errors.Wrap(err, "error sending ticket pending release lookup")
+			}
+		}
+	}
+	if backfillID != "" {
+		if err = redisConn.Send("GET", backfillID); err != nil {
+			return nil, errors.Wrap(err, "error sending backfill lookup")
+		}
+	}
+
+	replies, err := redis.Values(redisConn.Do("EXEC"))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error getting proposal state %v", err)
+	}
+	expected := 0
+	if len(ticketIDs) > 0 {
+		expected = 1 + 2*len(ticketIDs)
+	}
+	if backfillID != "" {
+		expected++
+	}
+	if len(replies) != expected {
+		return nil, status.Errorf(codes.Internal, "sent %d lookups to redis, but received %d back", expected, len(replies))
+	}
+
+	if len(ticketIDs) > 0 {
+		ticketBytes, err := redis.ByteSlices(replies[0], nil)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to lookup tickets %v", ticketIDs)
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		for i, b := range ticketBytes {
+			if b != nil {
+				t := &pb.Ticket{}
+				err = proto.Unmarshal(b, t)
+				if err != nil {
+					err = errors.Wrapf(err, "failed to unmarshal ticket from redis, key %s", ticketIDs[i])
+					return nil, status.Errorf(codes.Internal, "%v", err)
+				}
+				ps.Tickets = append(ps.Tickets, t)
+			}
+		}
+
+		// Same window as GetIndexedIDSet: tickets pending for longer than the
+		// release timeout are considered active again.
+		startTimeInt := time.Now().Add(-getBackfillReleaseTimeout(rb.cfg)).UnixNano()
+
+		lookups := replies[1:]
+		for i, id := range ticketIDs {
+			indexed, err := redis.Bool(lookups[2*i], nil)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "error reading ticket index lookup %v", err)
+			}
+			if !indexed {
+				continue
+			}
+
+			pendingSince, err := redis.Float64(lookups[2*i+1], nil)
+			if err != nil && err != redis.ErrNil {
+				return nil, status.Errorf(codes.Internal, "error reading ticket pending release lookup %v", err)
+			}
+			if err == nil && int64(pendingSince) >= startTimeInt {
+				continue
+			}
+			ps.ActiveTicketIDs[id] = struct{}{}
+		}
+	}
+
+	if backfillID != "" {
+		value, err := redis.Bytes(replies[len(replies)-1], nil)
+		if err != nil && err != redis.ErrNil {
+			err = errors.Wrapf(err, "failed to get the backfill from state storage, id: %s", backfillID)
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		if value != nil {
+			bi := &ipb.BackfillInternal{}
+			err = proto.Unmarshal(value, bi)
+			if err != nil {
+				err = errors.Wrapf(err, "failed to unmarshal internal backfill, id: %s", backfillID)
+				return nil, status.Errorf(codes.Internal, "%v", err)
+			}
+			ps.Backfill = bi.Backfill
+		}
+	}
+
+	return ps, nil
+}
+
 // GetTickets returns multiple tickets from storage.  Missing tickets are
 // silently ignored.
 func (rb *redisBackend) GetTickets(ctx context.Context, ids []string) ([]*pb.Ticket, error) {
@@ -200,6 +322,11 @@ func (rb *redisBackend) GetTickets(ctx context.Context, ids []string) ([]*pb.Tic
 	}
 	defer handleConnectionClose(&redisConn)
 
+	return getTickets(redisConn, ids)
+}
+
+// getTickets looks up the tickets which still exist among ids.
+func getTickets(redisConn redis.Conn, ids []string) ([]*pb.Ticket, error) {
 	queryParams := make([]interface{}, len(ids))
 	for i, id := range ids {
 		queryParams[i] = id
@@ -229,19 +356,119 @@ func (rb *redisBackend) GetTickets(ctx context.Context, ids []string) ([]*pb.Tic
 	return r, nil
 }
 
-// UpdateAssignments update using the request's specified tickets with assignments.
+// assignTicketsScript assigns tickets, setting each (id, value, group) triple
+// of ARGV from ARGV[6] on which still exists, skipping empty values.  Tickets
+// expire after ARGV[1] milliseconds, or never if it's empty.  Every ticket is
+// removed from the index KEYS[1] and pending release KEYS[2], so that assigned
+// tickets can't be matched again.  Assigned tickets with a group are added to
+// that tentative assignment group, which is cancelled at the ARGV[4] deadline
+// of KEYS[3].  If ARGV[2] isn't empty, the backfill with that id is set to
+// ARGV[3] in the same step, provided its value is still ARGV[5], or nothing is
+// done at all, and the assigned tickets are recorded as assigned to it.
+// Returns 1 for each assigned ticket and 0 for each missing one, or nil if the
+// backfill changed.
+var assignTicketsScript = redis.NewScript(3, `
+if ARGV[2] ~= '' and redis.call('GET', ARGV[2]) ~= ARGV[5] then
+  return false
+end
+local assigned = {}
+for i = 6, #ARGV, 3 do
+  local id, value, group = ARGV[i], ARGV[i + 1], ARGV[i + 2]
+  local set = false
+  if value ~= '' then
+    if ARGV[1] ~= '' then
+      set = redis.call('SET', id, value, 'PX', ARGV[1], 'XX')
+    else
+      set = redis.call('SET', id, value, 'XX')
+    end
+  end
+  if set then
+    table.insert(assigned, 1)
+    if ARGV[2] ~= '' then
+      redis.call('SADD', '`+backfillAssignedTicketsPrefix+`' .. ARGV[2], id)
+    end
+    if group ~= '' then
+      redis.call('HSET', '`+tentativeAssignmentPrefix+`' .. group, id, '`+assignmentPending+`')
+      redis.call('SET', '`+ticketTentativeAssignmentPrefix+`' .. id, group)
+      redis.call('ZADD', KEYS[3], ARGV[4], group)
+    end
+  else
+    table.insert(assigned, 0)
+  end
+  redis.call('SREM', KEYS[1], id)
+  redis.call('ZREM', KEYS[2], id)
+end
+if ARGV[2] ~= '' then
+  redis.call('SET', ARGV[2], ARGV[3], 'XX')
+end
+return assigned
+`)
+
+// UpdateAssignments sets the assignments of the request's tickets, removing
+// them from the index and pending release in the same atomic step.  Tentative
+// assignments are added to a group per AssignmentGroup, which must be accepted
+// within the acceptance timeout.
 func (rb *redisBackend) UpdateAssignments(ctx context.Context, req *pb.AssignTicketsRequest) (*pb.AssignTicketsResponse, []*pb.Ticket, error) {
-	resp := &pb.AssignTicketsResponse{}
-	if len(req.Assignments) == 0 {
-		return resp, []*pb.Ticket{}, nil
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Unavailable, "UpdateAssignments, failed to connect to redis: %v", err)
 	}
+	defer handleConnectionClose(&redisConn)
 
+	return rb.assignTickets(redisConn, req, nil, nil)
+}
+
+// AssignBackfillTickets assigns the backfill's tickets, removing them from the
+// index, pending release and the backfill in the same atomic step.  Fails with
+// Aborted if the generation or the associated tickets of the stored backfill
+// are no longer the ones of the input backfill and ticketIDs.
+func (rb *redisBackend) AssignBackfillTickets(ctx context.Context, backfill *pb.Backfill, ticketIDs []string, assignment *pb.Assignment) (*pb.AssignTicketsResponse, []*pb.Ticket, error) {
 	redisConn, err := rb.redisPool.GetContext(ctx)
 	if err != nil {
-		return nil, nil, status.Errorf(codes.Unavailable, "UpdateAssignments, failed to connect to redis: %v", err)
+		return nil, nil, status.Errorf(codes.Unavailable, "AssignBackfillTickets, id: %s, failed to connect to redis: %v", backfill.GetId(), err)
 	}
 	defer handleConnectionClose(&redisConn)
 
+	value, stored, err := getBackfillValue(redisConn, backfill.GetId())
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored.GetBackfill().GetGeneration() != backfill.GetGeneration() || !equalTicketIDs(stored.GetTicketIds(), ticketIDs) {
+		return nil, nil, status.Errorf(codes.Aborted, "backfill changed before its tickets were assigned, id: %s", backfill.GetId())
+	}
+
+	resp, tickets, err := rb.assignTickets(redisConn, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: ticketIDs, Assignment: assignment}},
+	}, &ipb.BackfillInternal{Backfill: backfill}, value)
+	if err == redis.ErrNil {
+		return nil, nil, status.Errorf(codes.Aborted, "backfill changed before its tickets were assigned, id: %s", backfill.GetId())
+	}
+	return resp, tickets, err
+}
+
+// equalTicketIDs returns whether a and b hold the same ticket ids in the same
+// order.
+func equalTicketIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// assignTickets assigns the request's tickets, and replaces the backfill if it
+// isn't nil and its stored value is still expected.  Returns redis.ErrNil if
+// the backfill changed.
+func (rb *redisBackend) assignTickets(redisConn redis.Conn, req *pb.AssignTicketsRequest, backfill *ipb.BackfillInternal, expected []byte) (*pb.AssignTicketsResponse, []*pb.Ticket, error) {
+	resp := &pb.AssignTicketsResponse{}
+	if len(req.Assignments) == 0 {
+		return resp, []*pb.Ticket{}, nil
+	}
+
 	idToA := make(map[string]*pb.Assignment)
 	ids := make([]string, 0)
 	idsI := make([]interface{}, 0)
@@ -270,6 +497,35 @@ func (rb *redisBackend) UpdateAssignments(ctx context.Context, req *pb.AssignTic
 		return nil, nil, err
 	}
 
+	// Tentative assignments don't expire until they're accepted.
+	var expiry, deadline interface{} = int64(getAssignedDeleteTimeout(rb.cfg) / time.Millisecond), ""
+	idToGroup := make(map[string]string)
+	if req.GetTentative() {
+		expiry = ""
+		deadline = time.Now().Add(getAssignmentAcceptanceTimeout(rb.cfg)).UnixNano()
+		for _, a := range req.Assignments {
+			group := xid.New().String()
+			for _, id := range a.TicketIds {
+				idToGroup[id] = group
+			}
+		}
+	}
+
+	args := make([]interface{}, 0, 3*len(ids)+7)
+	args = append(args, allTickets, proposedTicketIDs, tentativeAssignmentDeadlines, expiry)
+	if backfill != nil {
+		var value []byte
+		value, err = proto.Marshal(backfill)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to marshal the backfill proto, id: %s", backfill.GetBackfill().GetId())
+			return nil, nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		args = append(args, backfill.GetBackfill().GetId(), value)
+	} else {
+		args = append(args, "", "")
+	}
+	args = append(args, deadline, expected)
+
 	tickets := make([]*pb.Ticket, 0, len(ticketBytes))
 	for i, ticketByte := range ticketBytes {
 		// Tickets may be deleted by the time we read it from redis.
@@ -278,66 +534,306 @@ func (rb *redisBackend) UpdateAssignments(ctx context.Context, req *pb.AssignTic
 				TicketId: ids[i],
 				Cause:    pb.AssignmentFailure_TICKET_NOT_FOUND,
 			})
-		} else {
-			t := &pb.Ticket{}
-			err = proto.Unmarshal(ticketByte, t)
-			if err != nil {
-				err = errors.Wrapf(err, "failed to unmarshal ticket from redis %s", ids[i])
-				return nil, nil, status.Errorf(codes.Internal, "%v", err)
-			}
-			tickets = append(tickets, t)
+			// Still removed from the index and pending release.
+			args = append(args, ids[i], "", "")
+			continue
 		}
-	}
-	assignmentTimeout := getAssignedDeleteTimeout(rb.cfg) / time.Millisecond
-	err = redisConn.Send("MULTI")
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "error starting redis multi")
-	}
-
-	for _, ticket := range tickets {
-		ticket.Assignment = idToA[ticket.Id]
 
-		var ticketByte []byte
-		ticketByte, err = proto.Marshal(ticket)
+		t := &pb.Ticket{}
+		err = proto.Unmarshal(ticketByte, t)
 		if err != nil {
-			return nil, nil, status.Errorf(codes.Internal, "failed to marshal ticket %s", ticket.GetId())
+			err = errors.Wrapf(err, "failed to unmarshal ticket from redis %s", ids[i])
+			return nil, nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		t.Assignment = idToA[t.Id]
+		if req.GetTentative() {
+			t.Assignment = proto.Clone(t.Assignment).(*pb.Assignment)
+			t.Assignment.Tentative = true
 		}
 
-		err = redisConn.Send("SET", ticket.Id, ticketByte, "PX", int64(assignmentTimeout), "XX")
+		ticketByte, err = proto.Marshal(t)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "error sending ticket assignment set")
+			return nil, nil, status.Errorf(codes.Internal, "failed to marshal ticket %s", t.GetId())
 		}
+		args = append(args, t.Id, ticketByte, idToGroup[t.Id])
+		tickets = append(tickets, t)
 	}
 
-	wasSet, err := redis.Values(redisConn.Do("EXEC"))
+	wasSet, err := redis.Ints(assignTicketsScript.Do(redisConn, args...))
+	if err == redis.ErrNil {
+		return nil, nil, err
+	}
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "error executing assignment set")
+		return nil, nil, errors.Wrap(err, "error executing assignment script")
 	}
 
-	if len(wasSet) != len(tickets) {
-		return nil, nil, status.Errorf(codes.Internal, "sent %d tickets to redis, but received %d back", len(tickets), len(wasSet))
+	if len(wasSet) != len(ids) {
+		return nil, nil, status.Errorf(codes.Internal, "sent %d tickets to redis, but received %d back", len(ids), len(wasSet))
 	}
 
 	assignedTickets := make([]*pb.Ticket, 0, len(tickets))
-	for i, ticket := range tickets {
-		v, err := redis.String(wasSet[i], nil)
+	next := 0
+	for i, id := range ids {
+		if ticketBytes[i] == nil {
+			continue
+		}
+		ticket := tickets[next]
+		next++
+		if wasSet[i] == 0 {
+			resp.Failures = append(resp.Failures, &pb.AssignmentFailure{
+				TicketId: id,
+				Cause:    pb.AssignmentFailure_TICKET_NOT_FOUND,
+			})
+			continue
+		}
+		assignedTickets = append(assignedTickets, ticket)
+	}
+
+	return resp, assignedTickets, nil
+}
+
+// revokeAssignmentsScript revokes the ARGV[1] (id, value read, revoked value)
+// triples following ARGV[2]: each ticket is set to its revoked value without
+// expiry, returned to the index KEYS[1] and removed from pending release
+// KEYS[2].  Tickets are also removed from their tentative assignment group: a
+// group left empty is canceled, and one whose remaining tickets all accepted
+// is finalized, both being removed from the KEYS[3] deadlines.
+//
+// Finalizing a group sets its tickets to the values the caller read and
+// finalized beforehand, with an expiry of ARGV[2] ms.  They follow the
+// triples, as the group id, the number of its remaining tickets, and for each
+// of them its id, value read and finalized value, both empty for a missing
+// ticket.
+//
+// Returns 1 once revoked, or nil without revoking anything if a ticket isn't
+// the value the caller read, or a group would be finalized but the caller
+// didn't read its current tickets.
+var revokeAssignmentsScript = redis.NewScript(3, `
+local revoking = {}
+local groups = {}
+for i = 3, 2 + tonumber(ARGV[1]) * 3, 3 do
+  local id = ARGV[i]
+  if redis.call('GET', id) ~= ARGV[i + 1] then
+    return nil
+  end
+  revoking[id] = true
+  local group = redis.call('GET', '`+ticketTentativeAssignmentPrefix+`' .. id)
+  if group then
+    groups[group] = true
+  end
+end
+
+local finalized = {}
+local i = 3 + tonumber(ARGV[1]) * 3
+while i <= #ARGV do
+  local tickets = {}
+  local n = tonumber(ARGV[i + 1])
+  for j = i + 2, i + 1 + n * 3, 3 do
+    tickets[ARGV[j]] = {ARGV[j + 1], ARGV[j + 2]}
+  end
+  finalized[ARGV[i]] = tickets
+  i = i + 2 + n * 3
+end
+
+local finalizing = {}
+for group in pairs(groups) do
+  local states = redis.call('HGETALL', '`+tentativeAssignmentPrefix+`' .. group)
+  local remaining = {}
+  local accepted = true
+  for j = 1, #states, 2 do
+    if not revoking[states[j]] then
+      remaining[states[j]] = true
+      accepted = accepted and states[j + 1] == '`+assignmentAccepted+`'
+    end
+  end
+  if next(remaining) and accepted then
+    local tickets = finalized[group]
+    if not tickets then
+      return nil
+    end
+    for id in pairs(remaining) do
+      if not tickets[id] or (redis.call('GET', id) or '') ~= tickets[id][1] then
+        return nil
+      end
+    end
+    for id in pairs(tickets) do
+      if not remaining[id] then
+        return nil
+      end
+    end
+    finalizing[group] = tickets
+  end
+end
+
+for i = 3, 2 + tonumber(ARGV[1]) * 3, 3 do
+  local id = ARGV[i]
+  redis.call('SET', id, ARGV[i + 2], 'XX')
+  redis.call('SADD', KEYS[1], id)
+  redis.call('ZREM', KEYS[2], id)
+  local group = redis.call('GET', '`+ticketTentativeAssignmentPrefix+`' .. id)
+  if group then
+    redis.call('HDEL', '`+tentativeAssignmentPrefix+`' .. group, id)
+    redis.call('DEL', '`+ticketTentativeAssignmentPrefix+`' .. id)
+  end
+end
+
+for group in pairs(groups) do
+  local key = '`+tentativeAssignmentPrefix+`' .. group
+  local tickets = finalizing[group]
+  if tickets then
+    for id, values in pairs(tickets) do
+      if values[2] ~= '' then
+        redis.call('SET', id, values[2], 'PX', ARGV[2], 'XX')
+      end
+      redis.call('DEL', '`+ticketTentativeAssignmentPrefix+`' .. id)
+    end
+  end
+  if tickets or redis.call('EXISTS', key) == 0 then
+    redis.call('ZREM', KEYS[3], group)
+    redis.call('DEL', key)
+  end
+end
+return 1
+`)
+
+// RevokeAssignments removes the assignments of the tickets, returning them to
+// the index with their original create time.
+func (rb *redisBackend) RevokeAssignments(ctx context.Context, ids []string) (*pb.RevokeAssignmentsResponse, error) {
+	if len(ids) == 0 {
+		return &pb.RevokeAssignmentsResponse{}, nil
+	}
+
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "RevokeAssignments, failed to connect to redis: %v", err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	// The tickets are read again if they changed before they were revoked.
+	for attempt := 0; attempt < maxFinalizeAttempts; attempt++ {
+		resp, revokedIDs, args, err := revokedTicketArgs(redisConn, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(revokedIDs) == 0 {
+			return resp, nil
+		}
+
+		finalizeArgs, err := revokedGroupArgs(redisConn, revokedIDs)
+		if err != nil {
+			return nil, err
+		}
+		args = append([]interface{}{allTickets, proposedTicketIDs, tentativeAssignmentDeadlines, len(revokedIDs), getAssignedDeleteTimeout(rb.cfg).Milliseconds()}, args...)
+		_, err = redis.Int(revokeAssignmentsScript.Do(redisConn, append(args, finalizeArgs...)...))
 		if err == redis.ErrNil {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error executing revoke assignments script")
+		}
+		return resp, nil
+	}
+	return nil, status.Errorf(codes.Aborted, "RevokeAssignments, the tickets kept changing")
+}
+
+// revokedTicketArgs reads the tickets, returning the failures of the missing
+// or unassigned ones, and the ids of the others with their
+// revokeAssignmentsScript arguments.
+func revokedTicketArgs(redisConn redis.Conn, ids []string) (*pb.RevokeAssignmentsResponse, []string, []interface{}, error) {
+	idsI := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		idsI = append(idsI, id)
+	}
+	ticketBytes, err := redis.ByteSlices(redisConn.Do("MGET", idsI...))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	resp := &pb.RevokeAssignmentsResponse{}
+	revokedIDs := make([]string, 0, len(ids))
+	args := make([]interface{}, 0, 3*len(ids))
+	for i, ticketByte := range ticketBytes {
+		if ticketByte == nil {
 			resp.Failures = append(resp.Failures, &pb.AssignmentFailure{
-				TicketId: ticket.Id,
+				TicketId: ids[i],
 				Cause:    pb.AssignmentFailure_TICKET_NOT_FOUND,
 			})
 			continue
 		}
+
+		t := &pb.Ticket{}
+		err = proto.Unmarshal(ticketByte, t)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "unexpected error from redis multi set")
+			err = errors.Wrapf(err, "failed to unmarshal ticket from redis %s", ids[i])
+			return nil, nil, nil, status.Errorf(codes.Internal, "%v", err)
 		}
-		if v != "OK" {
-			return nil, nil, status.Errorf(codes.Internal, "unexpected response from redis: %s", v)
+		if t.Assignment == nil {
+			resp.Failures = append(resp.Failures, &pb.AssignmentFailure{
+				TicketId: ids[i],
+				Cause:    pb.AssignmentFailure_TICKET_NOT_ASSIGNED,
+			})
+			continue
 		}
-		assignedTickets = append(assignedTickets, ticket)
+		t.Assignment = nil
+
+		revoked, err := proto.Marshal(t)
+		if err != nil {
+			return nil, nil, nil, status.Errorf(codes.Internal, "failed to marshal ticket %s", t.GetId())
+		}
+		args = append(args, t.Id, ticketByte, revoked)
+		revokedIDs = append(revokedIDs, t.Id)
 	}
+	return resp, revokedIDs, args, nil
+}
 
-	return resp, assignedTickets, nil
+// revokedGroupArgs returns the revokeAssignmentsScript arguments describing
+// the tentative assignment groups which revoking the tickets would finalize,
+// as every other ticket of them accepted.
+func revokedGroupArgs(redisConn redis.Conn, ids []string) ([]interface{}, error) {
+	keys := make([]interface{}, 0, len(ids))
+	revoking := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		keys = append(keys, ticketTentativeAssignmentPrefix+id)
+		revoking[id] = struct{}{}
+	}
+	groups, err := redis.Strings(redisConn.Do("MGET", keys...))
+	if err != nil {
+		err = errors.Wrap(err, "failed to get the tentative assignments of the tickets")
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	var args []interface{}
+	seen := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		if _, ok := seen[group]; ok || group == "" {
+			continue
+		}
+		seen[group] = struct{}{}
+
+		states, err := redis.StringMap(redisConn.Do("HGETALL", tentativeAssignmentPrefix+group))
+		if err != nil {
+			err = errors.Wrapf(err, "failed to get tentative assignment, group: %s", group)
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		var remaining []interface{}
+		accepted := true
+		for id, state := range states {
+			if _, ok := revoking[id]; !ok {
+				remaining = append(remaining, id)
+				accepted = accepted && state == assignmentAccepted
+			}
+		}
+		if len(remaining) == 0 || !accepted {
+			continue
+		}
+
+		tickets, err := finalizedTicketArgs(redisConn, remaining)
+		if err != nil {
+			return nil, err
+		}
+		args = append(append(args, group), tickets...)
+	}
+	return args, nil
 }
 
 // GetAssignments returns the assignment associated with the input ticket id
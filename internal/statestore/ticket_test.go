@@ -31,6 +31,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"open-match.dev/open-match/internal/config"
 	"open-match.dev/open-match/internal/telemetry"
 	utilTesting "open-match.dev/open-match/internal/util/testing"
@@ -368,6 +370,266 @@ func TestUpdateAssignments(t *testing.T) {
 	require.Contains(t, status.Convert(err).Message(), "UpdateAssignments, failed to connect to redis: context canceled")
 }
 
+func TestUpdateAssignmentsDeindexesTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for _, id := range []string{"1", "2"} {
+		ticket := &pb.Ticket{Id: id}
+		require.NoError(t, service.CreateTicket(ctx, ticket))
+		require.NoError(t, service.IndexTicket(ctx, ticket))
+	}
+	require.NoError(t, service.AddTicketsToPendingRelease(ctx, []string{"1", "2"}))
+
+	resp, tickets, err := service.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{
+			TicketIds:  []string{"1", "missing"},
+			Assignment: &pb.Assignment{Connection: "2"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	require.Equal(t, "1", tickets[0].GetId())
+	require.Len(t, resp.GetFailures(), 1)
+	require.Equal(t, "missing", resp.GetFailures()[0].GetTicketId())
+
+	// The assigned ticket is neither indexed nor pending release, the other one is untouched.
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	indexed, err := redis.Strings(c.Do("SMEMBERS", allTickets))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, indexed)
+	pending, err := redis.Strings(c.Do("ZRANGE", proposedTicketIDs, 0, -1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, pending)
+
+	// A missing ticket is not recreated.
+	_, err = service.GetTicket(ctx, "missing")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	ticket, err := service.GetTicket(ctx, "1")
+	require.NoError(t, err)
+	require.Equal(t, "2", ticket.GetAssignment().GetConnection())
+}
+
+func TestAssignBackfillTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	ticket := &pb.Ticket{Id: "1"}
+	require.NoError(t, service.CreateTicket(ctx, ticket))
+	require.NoError(t, service.IndexTicket(ctx, ticket))
+	bf := &pb.Backfill{Id: "bf", Generation: 1}
+	require.NoError(t, service.CreateBackfill(ctx, bf, []string{"1", "missing"}))
+
+	resp, tickets, err := service.AssignBackfillTickets(ctx, bf, []string{"1", "missing"}, &pb.Assignment{Connection: "2"})
+	require.NoError(t, err)
+	require.Len(t, tickets, 1)
+	require.Equal(t, "2", tickets[0].GetAssignment().GetConnection())
+	require.Len(t, resp.GetFailures(), 1)
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	actual, ticketIDs, err := service.GetBackfill(ctx, bf.Id)
+	require.NoError(t, err)
+	require.Empty(t, ticketIDs)
+	require.Equal(t, bf.Generation, actual.GetGeneration())
+}
+
+func TestAssignBackfillTicketsChangedBackfill(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	for _, id := range []string{"1", "2"} {
+		ticket := &pb.Ticket{Id: id}
+		require.NoError(t, service.CreateTicket(ctx, ticket))
+		require.NoError(t, service.IndexTicket(ctx, ticket))
+	}
+	bf := &pb.Backfill{Id: "bf", Generation: 1}
+	require.NoError(t, service.CreateBackfill(ctx, bf, []string{"1"}))
+
+	// tickets matched to the backfill in the meantime
+	_, _, err := service.AssignBackfillTickets(ctx, bf, []string{"1", "2"}, &pb.Assignment{Connection: "2"})
+	require.Equal(t, codes.Aborted.String(), status.Convert(err).Code().String())
+
+	// the backfill was updated in the meantime
+	_, _, err = service.AssignBackfillTickets(ctx, &pb.Backfill{Id: "bf", Generation: 2}, []string{"1"}, &pb.Assignment{Connection: "2"})
+	require.Equal(t, codes.Aborted.String(), status.Convert(err).Code().String())
+
+	_, _, err = service.AssignBackfillTickets(ctx, &pb.Backfill{Id: "missing"}, []string{"1"}, &pb.Assignment{Connection: "2"})
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	// nothing was assigned
+	ids, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	actual, ticketIDs, err := service.GetBackfill(ctx, bf.Id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, ticketIDs)
+	require.Equal(t, bf.Generation, actual.GetGeneration())
+}
+
+func TestRevokeAssignments(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	created := timestamppb.New(time.Unix(100, 0))
+	for _, id := range []string{"assigned", "tentative", "active"} {
+		ticket := &pb.Ticket{Id: id, CreateTime: created}
+		require.NoError(t, service.CreateTicket(ctx, ticket))
+		require.NoError(t, service.IndexTicket(ctx, ticket))
+	}
+	_, _, err := service.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"assigned"}, Assignment: &pb.Assignment{Connection: "2"}}},
+	})
+	require.NoError(t, err)
+	_, _, err = service.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"tentative"}, Assignment: &pb.Assignment{Connection: "2"}}},
+		Tentative:   true,
+	})
+	require.NoError(t, err)
+
+	resp, err := service.RevokeAssignments(ctx, []string{"assigned", "tentative", "active", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, []*pb.AssignmentFailure{
+		{TicketId: "active", Cause: pb.AssignmentFailure_TICKET_NOT_ASSIGNED},
+		{TicketId: "missing", Cause: pb.AssignmentFailure_TICKET_NOT_FOUND},
+	}, resp.GetFailures())
+
+	ids, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{"assigned": {}, "tentative": {}, "active": {}}, ids)
+	for _, id := range []string{"assigned", "tentative"} {
+		ticket, err := service.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.Nil(t, ticket.GetAssignment())
+		require.Equal(t, created.GetSeconds(), ticket.GetCreateTime().GetSeconds())
+	}
+
+	// Revoked assignments don't expire, and left their tentative assignment group.
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	ttl, err := redis.Int(c.Do("PTTL", "assigned"))
+	require.NoError(t, err)
+	require.Equal(t, -1, ttl)
+	err = service.AcceptAssignment(ctx, "tentative")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	_, err = service.RevokeAssignments(ctx, []string{"assigned"})
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
+
+func TestRevokeAssignmentsStaleTickets(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	ticket := &pb.Ticket{Id: "assigned"}
+	require.NoError(t, service.CreateTicket(ctx, ticket))
+	_, _, err := service.UpdateAssignments(ctx, &pb.AssignTicketsRequest{
+		Assignments: []*pb.AssignmentGroup{{TicketIds: []string{"assigned"}, Assignment: &pb.Assignment{Connection: "2"}}},
+	})
+	require.NoError(t, err)
+
+	// A ticket which changed since it was read, or was deleted, makes the
+	// script ask for it again, without revoking anything.
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	for name, id := range map[string]string{"stale ticket": "assigned", "missing ticket": "missing"} {
+		_, err = redis.Int(revokeAssignmentsScript.Do(c, allTickets, proposedTicketIDs, tentativeAssignmentDeadlines, 1, 1000, id, "stale", "revoked"))
+		require.Equal(t, redis.ErrNil, err, name)
+	}
+	ticket, err = service.GetTicket(ctx, "assigned")
+	require.NoError(t, err)
+	require.Equal(t, "2", ticket.GetAssignment().GetConnection())
+	ids, err := service.GetIndexedIDSet(ctx)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+
+	// RevokeAssignments reads it again.
+	resp, err := service.RevokeAssignments(ctx, []string{"assigned"})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetFailures())
+	ticket, err = service.GetTicket(ctx, "assigned")
+	require.NoError(t, err)
+	require.Nil(t, ticket.GetAssignment())
+}
+
+func TestRevokeAssignmentsTentativeGroups(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	createTentativeAssignment(ctx, t, service, "accepted1", "accepted2", "revoked1")
+	require.NoError(t, service.AcceptAssignment(ctx, "accepted1"))
+	require.NoError(t, service.AcceptAssignment(ctx, "accepted2"))
+	createTentativeAssignment(ctx, t, service, "alone")
+	createTentativeAssignment(ctx, t, service, "revoked2", "pending")
+
+	resp, err := service.RevokeAssignments(ctx, []string{"revoked1", "alone", "revoked2"})
+	require.NoError(t, err)
+	require.Empty(t, resp.GetFailures())
+
+	// The group whose remaining tickets all accepted is finalized.
+	c, err := redis.Dial("tcp", fmt.Sprintf("%s:%s", cfg.GetString("redis.hostname"), cfg.GetString("redis.port")))
+	require.NoError(t, err)
+	defer c.Close()
+	for _, id := range []string{"accepted1", "accepted2"} {
+		ticket, err := service.GetTicket(ctx, id)
+		require.NoError(t, err)
+		require.False(t, ticket.GetAssignment().GetTentative())
+		ttl, err := redis.Int(c.Do("PTTL", id))
+		require.NoError(t, err)
+		require.Greater(t, ttl, 0)
+		err = service.AcceptAssignment(ctx, id)
+		require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+	}
+
+	// The emptied group is canceled, and only the group still waiting on a
+	// ticket keeps its deadline.
+	groups, err := redis.Strings(c.Do("ZRANGE", tentativeAssignmentDeadlines, 0, -1))
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	group, err := redis.String(c.Do("GET", ticketTentativeAssignmentPrefix+"pending"))
+	require.NoError(t, err)
+	require.Equal(t, group, groups[0])
+	states, err := redis.StringMap(c.Do("HGETALL", tentativeAssignmentPrefix+group))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pending": assignmentPending}, states)
+}
+
 func TestConnect(t *testing.T) {
 	testConnect(t, false, "")
 	testConnect(t, false, "redispassword")
@@ -713,6 +975,57 @@ func TestGetIndexedIDSet(t *testing.T) {
 	require.Contains(t, status.Convert(err).Message(), "GetIndexedIDSet, failed to connect to redis:")
 }
 
+func TestGetProposalState(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	tickets, ids := generateTickets(ctx, t, service, 3)
+	require.NoError(t, service.DeindexTicket(ctx, ids[2]))
+	backfill := &pb.Backfill{Id: "bf1", Generation: 2}
+	require.NoError(t, service.CreateBackfill(ctx, backfill, nil))
+
+	state, err := service.GetProposalState(ctx, append(ids, "unknownTicketID"), backfill.Id)
+	require.NoError(t, err)
+	require.Len(t, state.Tickets, 3)
+	for i, ticket := range state.Tickets {
+		require.True(t, proto.Equal(tickets[i], ticket))
+	}
+	require.Equal(t, map[string]struct{}{ids[0]: {}, ids[1]: {}}, state.ActiveTicketIDs)
+	require.True(t, proto.Equal(backfill, state.Backfill))
+
+	// Pending tickets are not active until the pending release expires.
+	require.NoError(t, service.AddTicketsToPendingRelease(ctx, ids[:1]))
+	state, err = service.GetProposalState(ctx, ids, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{ids[1]: {}}, state.ActiveTicketIDs)
+	require.Nil(t, state.Backfill)
+
+	time.Sleep(cfg.GetDuration("pendingReleaseTimeout"))
+	state, err = service.GetProposalState(ctx, ids, "unknownBackfillID")
+	require.NoError(t, err)
+	require.Equal(t, map[string]struct{}{ids[0]: {}, ids[1]: {}}, state.ActiveTicketIDs)
+	require.Nil(t, state.Backfill)
+
+	state, err = service.GetProposalState(ctx, nil, backfill.Id)
+	require.NoError(t, err)
+	require.Empty(t, state.Tickets)
+	require.Empty(t, state.ActiveTicketIDs)
+	require.True(t, proto.Equal(backfill, state.Backfill))
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	_, err = service.GetProposalState(ctx, ids, "")
+	require.Error(t, err)
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+	require.Contains(t, status.Convert(err).Message(), "GetProposalState, failed to connect to redis:")
+}
+
 func TestGetTickets(t *testing.T) {
 	cfg, closer := createRedis(t, false, "")
 	defer closer()
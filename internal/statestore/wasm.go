@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const wasmModules = "wasm_modules"
+
+// PutWasmModule stores a WebAssembly match function module under name, replacing any module already stored under it.
+func (rb *redisBackend) PutWasmModule(ctx context.Context, name string, module []byte) error {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "PutWasmModule, name: %s, failed to connect to redis: %v", name, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	_, err = redisConn.Do("HSET", wasmModules, name, module)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to store wasm module, name: %s", name)
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return nil
+}
+
+// GetWasmModule gets the WebAssembly match function module stored under name. This method fails with NotFound if there is no such module.
+func (rb *redisBackend) GetWasmModule(ctx context.Context, name string) ([]byte, error) {
+	redisConn, err := rb.redisPool.GetContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "GetWasmModule, name: %s, failed to connect to redis: %v", name, err)
+	}
+	defer handleConnectionClose(&redisConn)
+
+	module, err := redis.Bytes(redisConn.Do("HGET", wasmModules, name))
+	if err != nil {
+		if err == redis.ErrNil {
+			return nil, status.Errorf(codes.NotFound, "Wasm module name: %s not found", name)
+		}
+
+		err = errors.Wrapf(err, "failed to get the wasm module from state storage, name: %s", name)
+		return nil, status.Errorf(codes.Internal, "%v", err)
+	}
+
+	return module, nil
+}
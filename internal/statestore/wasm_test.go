@@ -0,0 +1,58 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	utilTesting "open-match.dev/open-match/internal/util/testing"
+)
+
+func TestWasmModule(t *testing.T) {
+	cfg, closer := createRedis(t, false, "")
+	defer closer()
+	service := New(cfg)
+	require.NotNil(t, service)
+	defer service.Close()
+	ctx := utilTesting.NewContext(t)
+
+	_, err := service.GetWasmModule(ctx, "mmf")
+	require.Equal(t, codes.NotFound.String(), status.Convert(err).Code().String())
+
+	require.NoError(t, service.PutWasmModule(ctx, "mmf", []byte{0, 1, 2}))
+	module, err := service.GetWasmModule(ctx, "mmf")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 1, 2}, module)
+
+	// Storing a module under the same name replaces it.
+	require.NoError(t, service.PutWasmModule(ctx, "mmf", []byte{3}))
+	module, err = service.GetWasmModule(ctx, "mmf")
+	require.NoError(t, err)
+	require.Equal(t, []byte{3}, module)
+
+	// Pass an expired context, err expected
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	service = New(cfg)
+	err = service.PutWasmModule(ctx, "mmf", []byte{4})
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+	require.Contains(t, status.Convert(err).Message(), "PutWasmModule, name: mmf, failed to connect to redis:")
+	_, err = service.GetWasmModule(ctx, "mmf")
+	require.Equal(t, codes.Unavailable.String(), status.Convert(err).Code().String())
+}
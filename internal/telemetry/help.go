@@ -36,6 +36,8 @@ const (
 * <a href="/debug/pprof/symbol">/debug/pprof/symbol</a> - PProf
 * <a href="/debug/pprof/trace">/debug/pprof/trace</a> - Execution Trace
 * <a href="/metrics">/metrics</a> - Raw Metrics, use prometheus or grafana instead.
+* <a href="/rejectionz">/rejectionz</a> - Evaluation results per match profile, synchronizer only.
+* <a href="/cyclez">/cyclez</a> - Running and recent cycles, synchronizer only.
 
 <i>For /debug/pprof/ links see, https://golang.org/pkg/net/http/pprof/ for details.</i>
 </pre>
@@ -26,7 +26,11 @@ import (
 
 // QueryPool queries queryService and returns the tickets that belong to the specified pool.
 func QueryPool(ctx context.Context, queryClient pb.QueryServiceClient, pool *pb.Pool, opts ...grpc.CallOption) ([]*pb.Ticket, error) {
-	query, err := queryClient.QueryTickets(ctx, &pb.QueryTicketsRequest{Pool: pool}, opts...)
+	return queryPool(ctx, queryClient, &pb.QueryTicketsRequest{Pool: pool}, opts...)
+}
+
+func queryPool(ctx context.Context, queryClient pb.QueryServiceClient, req *pb.QueryTicketsRequest, opts ...grpc.CallOption) ([]*pb.Ticket, error) {
+	query, err := queryClient.QueryTickets(ctx, req, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error calling queryService.QueryTickets: %w", err)
 	}
@@ -48,6 +52,17 @@ func QueryPool(ctx context.Context, queryClient pb.QueryServiceClient, pool *pb.
 
 // QueryPools queries queryService and returns a map of pool names to the tickets belonging to those pools.
 func QueryPools(ctx context.Context, queryClient pb.QueryServiceClient, pools []*pb.Pool, opts ...grpc.CallOption) (map[string][]*pb.Ticket, error) {
+	return queryPools(ctx, queryClient, "", pools, opts...)
+}
+
+// QueryProfilePools queries queryService for the pools of the profile, and
+// returns a map of pool names to the tickets belonging to those pools.  Unlike
+// QueryPools, the tickets reserved for the profile are included.
+func QueryProfilePools(ctx context.Context, queryClient pb.QueryServiceClient, profile *pb.MatchProfile, opts ...grpc.CallOption) (map[string][]*pb.Ticket, error) {
+	return queryPools(ctx, queryClient, profile.GetName(), profile.GetPools(), opts...)
+}
+
+func queryPools(ctx context.Context, queryClient pb.QueryServiceClient, profileName string, pools []*pb.Pool, opts ...grpc.CallOption) (map[string][]*pb.Ticket, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	type result struct {
@@ -62,7 +77,7 @@ func QueryPools(ctx context.Context, queryClient pb.QueryServiceClient, pools []
 			r := result{
 				name: pool.Name,
 			}
-			r.tickets, r.err = QueryPool(ctx, queryClient, pool, opts...)
+			r.tickets, r.err = queryPool(ctx, queryClient, &pb.QueryTicketsRequest{Pool: pool, ProfileName: profileName}, opts...)
 			select {
 			case results <- r:
 			case <-ctx.Done():
@@ -149,3 +164,27 @@ func QueryBackfillPools(ctx context.Context, queryClient pb.QueryServiceClient,
 
 	return poolMap, nil
 }
+
+// QueryCompatibleBackfills queries queryService and returns a map of ticket ids to the backfills compatible with
+// those tickets, best ranked first.  Tickets without compatible backfills are left out.
+func QueryCompatibleBackfills(ctx context.Context, queryClient pb.QueryServiceClient, req *pb.QueryCompatibleBackfillsRequest, opts ...grpc.CallOption) (map[string][]*pb.Backfill, error) {
+	query, err := queryClient.QueryCompatibleBackfills(ctx, req, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error calling queryService.QueryCompatibleBackfills: %w", err)
+	}
+
+	backfills := make(map[string][]*pb.Backfill)
+	for {
+		resp, err := query.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return backfills, nil
+			}
+			return nil, fmt.Errorf("error receiving backfills from queryService.QueryCompatibleBackfills: %w", err)
+		}
+
+		for _, tb := range resp.GetTicketBackfills() {
+			backfills[tb.GetTicketId()] = tb.GetBackfills()
+		}
+	}
+}
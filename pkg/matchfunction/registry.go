@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchfunction
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// InProcessFunction is a match function compiled into the Backend.  It is
+// run for FetchMatches calls using the IN_PROCESS FunctionConfig type with the
+// name it was registered under.
+type InProcessFunction interface {
+	// Run sends the proposals for the profile on out.  query is a client for a
+	// Query Service run in the Backend process, so queries don't go through
+	// the network.
+	Run(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error
+}
+
+// InProcessFunctionFunc is an adapter to use an ordinary function as an
+// InProcessFunction.
+type InProcessFunctionFunc func(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error
+
+// Run calls f(ctx, query, profile, out).
+func (f InProcessFunctionFunc) Run(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+	return f(ctx, query, profile, out)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]InProcessFunction{}
+)
+
+// Register makes a match function available to the Backend under name.  It is
+// meant to be called from an init function, and panics if name is already
+// registered or mmf is nil.
+func Register(name string, mmf InProcessFunction) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if mmf == nil {
+		panic("matchfunction: Register mmf is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("matchfunction: Register called twice for %q", name))
+	}
+	registry[name] = mmf
+}
+
+// Lookup returns the match function registered under name.
+func Lookup(name string) (InProcessFunction, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	mmf, ok := registry[name]
+	return mmf, ok
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package matchfunction
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// MatchFunction makes match proposals for a profile, sending them to out.
+type MatchFunction func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error
+
+type server struct {
+	mmf MatchFunction
+}
+
+// NewServer returns a MatchFunction service which streams the proposals of mmf.
+func NewServer(mmf MatchFunction) pb.MatchFunctionServer {
+	return &server{mmf: mmf}
+}
+
+func (s *server) Run(req *pb.RunRequest, stream pb.MatchFunction_RunServer) error {
+	g, ctx := errgroup.WithContext(stream.Context())
+
+	out := make(chan *pb.Match)
+
+	g.Go(func() error {
+		defer close(out)
+		return s.mmf(ctx, req.Profile, out)
+	})
+	g.Go(func() error {
+		defer func() {
+			for range out {
+			}
+		}()
+
+		for m := range out {
+			err := stream.Send(&pb.RunResponse{Proposal: m})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
@@ -33,10 +33,13 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	BackendService_FetchMatches_FullMethodName      = "/openmatch.BackendService/FetchMatches"
-	BackendService_AssignTickets_FullMethodName     = "/openmatch.BackendService/AssignTickets"
-	BackendService_ReleaseTickets_FullMethodName    = "/openmatch.BackendService/ReleaseTickets"
-	BackendService_ReleaseAllTickets_FullMethodName = "/openmatch.BackendService/ReleaseAllTickets"
+	BackendService_FetchMatches_FullMethodName       = "/openmatch.BackendService/FetchMatches"
+	BackendService_AssignTickets_FullMethodName      = "/openmatch.BackendService/AssignTickets"
+	BackendService_RevokeAssignments_FullMethodName  = "/openmatch.BackendService/RevokeAssignments"
+	BackendService_ReleaseTickets_FullMethodName     = "/openmatch.BackendService/ReleaseTickets"
+	BackendService_ReleaseAllTickets_FullMethodName  = "/openmatch.BackendService/ReleaseAllTickets"
+	BackendService_RegisterWasmModule_FullMethodName = "/openmatch.BackendService/RegisterWasmModule"
+	BackendService_GetMatchHistory_FullMethodName    = "/openmatch.BackendService/GetMatchHistory"
 )
 
 // BackendServiceClient is the client API for BackendService service.
@@ -51,6 +54,11 @@ type BackendServiceClient interface {
 	FetchMatches(ctx context.Context, in *FetchMatchesRequest, opts ...grpc.CallOption) (BackendService_FetchMatchesClient, error)
 	// AssignTickets overwrites the Assignment field of the input TicketIds.
 	AssignTickets(ctx context.Context, in *AssignTicketsRequest, opts ...grpc.CallOption) (*AssignTicketsResponse, error)
+	// RevokeAssignments removes the Assignment of the input TicketIds, for instance when
+	// their game server failed before the players connected. The Tickets return to the
+	// active pool with their original create_time, and WatchAssignments streams receive
+	// an empty Assignment.
+	RevokeAssignments(ctx context.Context, in *RevokeAssignmentsRequest, opts ...grpc.CallOption) (*RevokeAssignmentsResponse, error)
 	// ReleaseTickets moves tickets from the pending state, to the active state.
 	// This enables them to be returned by query, and find different matches.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
@@ -62,6 +70,18 @@ type BackendServiceClient interface {
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	ReleaseAllTickets(ctx context.Context, in *ReleaseAllTicketsRequest, opts ...grpc.CallOption) (*ReleaseAllTicketsResponse, error)
+	// RegisterWasmModule stores a WebAssembly match function module, replacing
+	// any module previously registered with the same name.  FetchMatches calls
+	// using it pick up the new module without redeploying the Backend.
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	RegisterWasmModule(ctx context.Context, in *RegisterWasmModuleRequest, opts ...grpc.CallOption) (*RegisterWasmModuleResponse, error)
+	// GetMatchHistory returns the matches FetchMatches returned a ticket in,
+	// while they are kept by the match history.  Requires the matchHistory
+	// config to be "statestore".
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	GetMatchHistory(ctx context.Context, in *GetMatchHistoryRequest, opts ...grpc.CallOption) (*GetMatchHistoryResponse, error)
 }
 
 type backendServiceClient struct {
@@ -113,6 +133,15 @@ func (c *backendServiceClient) AssignTickets(ctx context.Context, in *AssignTick
 	return out, nil
 }
 
+func (c *backendServiceClient) RevokeAssignments(ctx context.Context, in *RevokeAssignmentsRequest, opts ...grpc.CallOption) (*RevokeAssignmentsResponse, error) {
+	out := new(RevokeAssignmentsResponse)
+	err := c.cc.Invoke(ctx, BackendService_RevokeAssignments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *backendServiceClient) ReleaseTickets(ctx context.Context, in *ReleaseTicketsRequest, opts ...grpc.CallOption) (*ReleaseTicketsResponse, error) {
 	out := new(ReleaseTicketsResponse)
 	err := c.cc.Invoke(ctx, BackendService_ReleaseTickets_FullMethodName, in, out, opts...)
@@ -131,6 +160,24 @@ func (c *backendServiceClient) ReleaseAllTickets(ctx context.Context, in *Releas
 	return out, nil
 }
 
+func (c *backendServiceClient) RegisterWasmModule(ctx context.Context, in *RegisterWasmModuleRequest, opts ...grpc.CallOption) (*RegisterWasmModuleResponse, error) {
+	out := new(RegisterWasmModuleResponse)
+	err := c.cc.Invoke(ctx, BackendService_RegisterWasmModule_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *backendServiceClient) GetMatchHistory(ctx context.Context, in *GetMatchHistoryRequest, opts ...grpc.CallOption) (*GetMatchHistoryResponse, error) {
+	out := new(GetMatchHistoryResponse)
+	err := c.cc.Invoke(ctx, BackendService_GetMatchHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BackendServiceServer is the server API for BackendService service.
 // All implementations should embed UnimplementedBackendServiceServer
 // for forward compatibility
@@ -143,6 +190,11 @@ type BackendServiceServer interface {
 	FetchMatches(*FetchMatchesRequest, BackendService_FetchMatchesServer) error
 	// AssignTickets overwrites the Assignment field of the input TicketIds.
 	AssignTickets(context.Context, *AssignTicketsRequest) (*AssignTicketsResponse, error)
+	// RevokeAssignments removes the Assignment of the input TicketIds, for instance when
+	// their game server failed before the players connected. The Tickets return to the
+	// active pool with their original create_time, and WatchAssignments streams receive
+	// an empty Assignment.
+	RevokeAssignments(context.Context, *RevokeAssignmentsRequest) (*RevokeAssignmentsResponse, error)
 	// ReleaseTickets moves tickets from the pending state, to the active state.
 	// This enables them to be returned by query, and find different matches.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
@@ -154,6 +206,18 @@ type BackendServiceServer interface {
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	ReleaseAllTickets(context.Context, *ReleaseAllTicketsRequest) (*ReleaseAllTicketsResponse, error)
+	// RegisterWasmModule stores a WebAssembly match function module, replacing
+	// any module previously registered with the same name.  FetchMatches calls
+	// using it pick up the new module without redeploying the Backend.
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	RegisterWasmModule(context.Context, *RegisterWasmModuleRequest) (*RegisterWasmModuleResponse, error)
+	// GetMatchHistory returns the matches FetchMatches returned a ticket in,
+	// while they are kept by the match history.  Requires the matchHistory
+	// config to be "statestore".
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	GetMatchHistory(context.Context, *GetMatchHistoryRequest) (*GetMatchHistoryResponse, error)
 }
 
 // UnimplementedBackendServiceServer should be embedded to have forward compatible implementations.
@@ -166,12 +230,21 @@ func (UnimplementedBackendServiceServer) FetchMatches(*FetchMatchesRequest, Back
 func (UnimplementedBackendServiceServer) AssignTickets(context.Context, *AssignTicketsRequest) (*AssignTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AssignTickets not implemented")
 }
+func (UnimplementedBackendServiceServer) RevokeAssignments(context.Context, *RevokeAssignmentsRequest) (*RevokeAssignmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAssignments not implemented")
+}
 func (UnimplementedBackendServiceServer) ReleaseTickets(context.Context, *ReleaseTicketsRequest) (*ReleaseTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReleaseTickets not implemented")
 }
 func (UnimplementedBackendServiceServer) ReleaseAllTickets(context.Context, *ReleaseAllTicketsRequest) (*ReleaseAllTicketsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReleaseAllTickets not implemented")
 }
+func (UnimplementedBackendServiceServer) RegisterWasmModule(context.Context, *RegisterWasmModuleRequest) (*RegisterWasmModuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWasmModule not implemented")
+}
+func (UnimplementedBackendServiceServer) GetMatchHistory(context.Context, *GetMatchHistoryRequest) (*GetMatchHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatchHistory not implemented")
+}
 
 // UnsafeBackendServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to BackendServiceServer will
@@ -223,6 +296,24 @@ func _BackendService_AssignTickets_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BackendService_RevokeAssignments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAssignmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).RevokeAssignments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackendService_RevokeAssignments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).RevokeAssignments(ctx, req.(*RevokeAssignmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BackendService_ReleaseTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReleaseTicketsRequest)
 	if err := dec(in); err != nil {
@@ -259,6 +350,42 @@ func _BackendService_ReleaseAllTickets_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BackendService_RegisterWasmModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWasmModuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).RegisterWasmModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackendService_RegisterWasmModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).RegisterWasmModule(ctx, req.(*RegisterWasmModuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BackendService_GetMatchHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BackendServiceServer).GetMatchHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BackendService_GetMatchHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BackendServiceServer).GetMatchHistory(ctx, req.(*GetMatchHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BackendService_ServiceDesc is the grpc.ServiceDesc for BackendService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -270,6 +397,10 @@ var BackendService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AssignTickets",
 			Handler:    _BackendService_AssignTickets_Handler,
 		},
+		{
+			MethodName: "RevokeAssignments",
+			Handler:    _BackendService_RevokeAssignments_Handler,
+		},
 		{
 			MethodName: "ReleaseTickets",
 			Handler:    _BackendService_ReleaseTickets_Handler,
@@ -278,6 +409,14 @@ var BackendService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReleaseAllTickets",
 			Handler:    _BackendService_ReleaseAllTickets_Handler,
 		},
+		{
+			MethodName: "RegisterWasmModule",
+			Handler:    _BackendService_RegisterWasmModule_Handler,
+		},
+		{
+			MethodName: "GetMatchHistory",
+			Handler:    _BackendService_GetMatchHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -23,6 +23,8 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -42,6 +44,12 @@ type DefaultEvaluationCriteria struct {
 	unknownFields protoimpl.UnknownFields
 
 	Score float64 `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	// Time the longest waiting player of the match started waiting, used
+	// instead of the oldest create_time of the match's tickets when set.
+	OldestTicketCreateTime *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=oldest_ticket_create_time,json=oldestTicketCreateTime,proto3" json:"oldest_ticket_create_time,omitempty"`
+	// Boost per second of the oldest ticket's wait for this match, used instead
+	// of the evaluator's configured boost when greater than 0.
+	WaitBoostPerSecond float64 `protobuf:"fixed64,3,opt,name=wait_boost_per_second,json=waitBoostPerSecond,proto3" json:"wait_boost_per_second,omitempty"`
 }
 
 func (x *DefaultEvaluationCriteria) Reset() {
@@ -83,18 +91,372 @@ func (x *DefaultEvaluationCriteria) GetScore() float64 {
 	return 0
 }
 
+func (x *DefaultEvaluationCriteria) GetOldestTicketCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OldestTicketCreateTime
+	}
+	return nil
+}
+
+func (x *DefaultEvaluationCriteria) GetWaitBoostPerSecond() float64 {
+	if x != nil {
+		return x.WaitBoostPerSecond
+	}
+	return 0
+}
+
+// MatchRules configures the rule based match function, set in a MatchProfile's
+// extensions under the "rules" key.  Each match fills every slot of every team
+// with tickets from the profile's pools.
+type MatchRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Teams making up a match.
+	Teams []*MatchRules_Team `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+	// Optional constraint on how balanced the teams of a match must be.  Tickets
+	// are matched with the tickets closest to them on the double_arg first.
+	Balance *MatchRules_Balance `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// Tickets which have waited at least max_wait since they were created are
+	// matched first, and their matches are made regardless of balance, so that
+	// no ticket waits for a balanced match forever.  Unset means no limit.
+	MaxWait *durationpb.Duration `protobuf:"bytes,3,opt,name=max_wait,json=maxWait,proto3" json:"max_wait,omitempty"`
+}
+
+func (x *MatchRules) Reset() {
+	*x = MatchRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_extensions_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRules) ProtoMessage() {}
+
+func (x *MatchRules) ProtoReflect() protoreflect.Message {
+	mi := &file_api_extensions_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRules.ProtoReflect.Descriptor instead.
+func (*MatchRules) Descriptor() ([]byte, []int) {
+	return file_api_extensions_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MatchRules) GetTeams() []*MatchRules_Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+func (x *MatchRules) GetBalance() *MatchRules_Balance {
+	if x != nil {
+		return x.Balance
+	}
+	return nil
+}
+
+func (x *MatchRules) GetMaxWait() *durationpb.Duration {
+	if x != nil {
+		return x.MaxWait
+	}
+	return nil
+}
+
+// MatchTeams lists which tickets make up each team of a match made by the rule
+// based match function, set in the match's extensions under the "teams" key.
+type MatchTeams struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Teams []*MatchTeams_Team `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+}
+
+func (x *MatchTeams) Reset() {
+	*x = MatchTeams{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_extensions_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchTeams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchTeams) ProtoMessage() {}
+
+func (x *MatchTeams) ProtoReflect() protoreflect.Message {
+	mi := &file_api_extensions_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchTeams.ProtoReflect.Descriptor instead.
+func (*MatchTeams) Descriptor() ([]byte, []int) {
+	return file_api_extensions_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MatchTeams) GetTeams() []*MatchTeams_Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type MatchRules_Team struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the team, reported in the match's MatchTeams.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of tickets the team takes from each of the profile's pools, by
+	// pool name.  The size of the team is the total of its slots.
+	PoolSlots map[string]int32 `protobuf:"bytes,2,rep,name=pool_slots,json=poolSlots,proto3" json:"pool_slots,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+}
+
+func (x *MatchRules_Team) Reset() {
+	*x = MatchRules_Team{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_extensions_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchRules_Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRules_Team) ProtoMessage() {}
+
+func (x *MatchRules_Team) ProtoReflect() protoreflect.Message {
+	mi := &file_api_extensions_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRules_Team.ProtoReflect.Descriptor instead.
+func (*MatchRules_Team) Descriptor() ([]byte, []int) {
+	return file_api_extensions_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *MatchRules_Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MatchRules_Team) GetPoolSlots() map[string]int32 {
+	if x != nil {
+		return x.PoolSlots
+	}
+	return nil
+}
+
+type MatchRules_Balance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The double_arg of tickets to balance the teams on.
+	DoubleArg string `protobuf:"bytes,1,opt,name=double_arg,json=doubleArg,proto3" json:"double_arg,omitempty"`
+	// Maximum difference between the highest and lowest average double_arg of
+	// the teams of a match.
+	MaxDifference float64 `protobuf:"fixed64,2,opt,name=max_difference,json=maxDifference,proto3" json:"max_difference,omitempty"`
+}
+
+func (x *MatchRules_Balance) Reset() {
+	*x = MatchRules_Balance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_extensions_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchRules_Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchRules_Balance) ProtoMessage() {}
+
+func (x *MatchRules_Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_api_extensions_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchRules_Balance.ProtoReflect.Descriptor instead.
+func (*MatchRules_Balance) Descriptor() ([]byte, []int) {
+	return file_api_extensions_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *MatchRules_Balance) GetDoubleArg() string {
+	if x != nil {
+		return x.DoubleArg
+	}
+	return ""
+}
+
+func (x *MatchRules_Balance) GetMaxDifference() float64 {
+	if x != nil {
+		return x.MaxDifference
+	}
+	return 0
+}
+
+type MatchTeams_Team struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the team, from MatchRules.
+	Name      string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TicketIds []string `protobuf:"bytes,2,rep,name=ticket_ids,json=ticketIds,proto3" json:"ticket_ids,omitempty"`
+}
+
+func (x *MatchTeams_Team) Reset() {
+	*x = MatchTeams_Team{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_extensions_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MatchTeams_Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchTeams_Team) ProtoMessage() {}
+
+func (x *MatchTeams_Team) ProtoReflect() protoreflect.Message {
+	mi := &file_api_extensions_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchTeams_Team.ProtoReflect.Descriptor instead.
+func (*MatchTeams_Team) Descriptor() ([]byte, []int) {
+	return file_api_extensions_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *MatchTeams_Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MatchTeams_Team) GetTicketIds() []string {
+	if x != nil {
+		return x.TicketIds
+	}
+	return nil
+}
+
 var File_api_extensions_proto protoreflect.FileDescriptor
 
 var file_api_extensions_proto_rawDesc = []byte{
 	0x0a, 0x14, 0x61, 0x70, 0x69, 0x2f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73,
 	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x61, 0x74, 0x63,
-	0x68, 0x22, 0x31, 0x0a, 0x19, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x45, 0x76, 0x61, 0x6c,
-	0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x72, 0x69, 0x74, 0x65, 0x72, 0x69, 0x61, 0x12, 0x14,
-	0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73,
-	0x63, 0x6f, 0x72, 0x65, 0x42, 0x2e, 0x5a, 0x20, 0x6f, 0x70, 0x65, 0x6e, 0x2d, 0x6d, 0x61, 0x74,
-	0x63, 0x68, 0x2e, 0x64, 0x65, 0x76, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x2d, 0x6d, 0x61, 0x74, 0x63,
-	0x68, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x62, 0xaa, 0x02, 0x09, 0x4f, 0x70, 0x65, 0x6e, 0x4d,
-	0x61, 0x74, 0x63, 0x68, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x68, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xbb, 0x01, 0x0a, 0x19, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x45, 0x76,
+	0x61, 0x6c, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x72, 0x69, 0x74, 0x65, 0x72, 0x69, 0x61,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x55, 0x0a, 0x19, 0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74,
+	0x5f, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74,
+	0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x16, 0x6f, 0x6c, 0x64, 0x65, 0x73, 0x74, 0x54, 0x69, 0x63,
+	0x6b, 0x65, 0x74, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x31, 0x0a,
+	0x15, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x62, 0x6f, 0x6f, 0x73, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f,
+	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x77, 0x61,
+	0x69, 0x74, 0x42, 0x6f, 0x6f, 0x73, 0x74, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64,
+	0x22, 0xa3, 0x03, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12,
+	0x30, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x05, 0x74, 0x65, 0x61, 0x6d,
+	0x73, 0x12, 0x37, 0x0a, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x2e, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x52, 0x07, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x08, 0x6d, 0x61,
+	0x78, 0x5f, 0x77, 0x61, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74,
+	0x1a, 0xa2, 0x01, 0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a,
+	0x0a, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x73, 0x6c, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x29, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x2e, 0x50, 0x6f,
+	0x6f, 0x6c, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x70, 0x6f,
+	0x6f, 0x6c, 0x53, 0x6c, 0x6f, 0x74, 0x73, 0x1a, 0x3c, 0x0a, 0x0e, 0x50, 0x6f, 0x6f, 0x6c, 0x53,
+	0x6c, 0x6f, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4f, 0x0a, 0x07, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x5f, 0x61, 0x72, 0x67, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x6f, 0x75, 0x62, 0x6c, 0x65, 0x41, 0x72, 0x67, 0x12,
+	0x25, 0x0a, 0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x69, 0x66, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x44, 0x69, 0x66, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x79, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x54,
+	0x65, 0x61, 0x6d, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x54, 0x65, 0x61, 0x6d, 0x73, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52,
+	0x05, 0x74, 0x65, 0x61, 0x6d, 0x73, 0x1a, 0x39, 0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x5f, 0x69, 0x64, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x63, 0x6b, 0x65, 0x74, 0x49, 0x64,
+	0x73, 0x42, 0x2e, 0x5a, 0x20, 0x6f, 0x70, 0x65, 0x6e, 0x2d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x64, 0x65, 0x76, 0x2f, 0x6f, 0x70, 0x65, 0x6e, 0x2d, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2f, 0x70,
+	0x6b, 0x67, 0x2f, 0x70, 0x62, 0xaa, 0x02, 0x09, 0x4f, 0x70, 0x65, 0x6e, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -109,16 +471,30 @@ func file_api_extensions_proto_rawDescGZIP() []byte {
 	return file_api_extensions_proto_rawDescData
 }
 
-var file_api_extensions_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_api_extensions_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_api_extensions_proto_goTypes = []interface{}{
 	(*DefaultEvaluationCriteria)(nil), // 0: openmatch.DefaultEvaluationCriteria
+	(*MatchRules)(nil),                // 1: openmatch.MatchRules
+	(*MatchTeams)(nil),                // 2: openmatch.MatchTeams
+	(*MatchRules_Team)(nil),           // 3: openmatch.MatchRules.Team
+	(*MatchRules_Balance)(nil),        // 4: openmatch.MatchRules.Balance
+	nil,                               // 5: openmatch.MatchRules.Team.PoolSlotsEntry
+	(*MatchTeams_Team)(nil),           // 6: openmatch.MatchTeams.Team
+	(*timestamppb.Timestamp)(nil),     // 7: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),       // 8: google.protobuf.Duration
 }
 var file_api_extensions_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	7, // 0: openmatch.DefaultEvaluationCriteria.oldest_ticket_create_time:type_name -> google.protobuf.Timestamp
+	3, // 1: openmatch.MatchRules.teams:type_name -> openmatch.MatchRules.Team
+	4, // 2: openmatch.MatchRules.balance:type_name -> openmatch.MatchRules.Balance
+	8, // 3: openmatch.MatchRules.max_wait:type_name -> google.protobuf.Duration
+	6, // 4: openmatch.MatchTeams.teams:type_name -> openmatch.MatchTeams.Team
+	5, // 5: openmatch.MatchRules.Team.pool_slots:type_name -> openmatch.MatchRules.Team.PoolSlotsEntry
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_api_extensions_proto_init() }
@@ -139,6 +515,66 @@ func file_api_extensions_proto_init() {
 				return nil
 			}
 		}
+		file_api_extensions_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_extensions_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchTeams); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_extensions_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchRules_Team); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_extensions_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchRules_Balance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_extensions_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MatchTeams_Team); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -146,7 +582,7 @@ func file_api_extensions_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_extensions_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -34,15 +34,20 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	FrontendService_CreateTicket_FullMethodName        = "/openmatch.FrontendService/CreateTicket"
-	FrontendService_DeleteTicket_FullMethodName        = "/openmatch.FrontendService/DeleteTicket"
-	FrontendService_GetTicket_FullMethodName           = "/openmatch.FrontendService/GetTicket"
-	FrontendService_WatchAssignments_FullMethodName    = "/openmatch.FrontendService/WatchAssignments"
-	FrontendService_AcknowledgeBackfill_FullMethodName = "/openmatch.FrontendService/AcknowledgeBackfill"
-	FrontendService_CreateBackfill_FullMethodName      = "/openmatch.FrontendService/CreateBackfill"
-	FrontendService_DeleteBackfill_FullMethodName      = "/openmatch.FrontendService/DeleteBackfill"
-	FrontendService_GetBackfill_FullMethodName         = "/openmatch.FrontendService/GetBackfill"
-	FrontendService_UpdateBackfill_FullMethodName      = "/openmatch.FrontendService/UpdateBackfill"
+	FrontendService_CreateTicket_FullMethodName          = "/openmatch.FrontendService/CreateTicket"
+	FrontendService_DeleteTicket_FullMethodName          = "/openmatch.FrontendService/DeleteTicket"
+	FrontendService_GetTicket_FullMethodName             = "/openmatch.FrontendService/GetTicket"
+	FrontendService_WatchAssignments_FullMethodName      = "/openmatch.FrontendService/WatchAssignments"
+	FrontendService_AcceptAssignment_FullMethodName      = "/openmatch.FrontendService/AcceptAssignment"
+	FrontendService_DeclineAssignment_FullMethodName     = "/openmatch.FrontendService/DeclineAssignment"
+	FrontendService_AcknowledgeBackfill_FullMethodName   = "/openmatch.FrontendService/AcknowledgeBackfill"
+	FrontendService_WatchBackfill_FullMethodName         = "/openmatch.FrontendService/WatchBackfill"
+	FrontendService_WatchBackfillEvents_FullMethodName   = "/openmatch.FrontendService/WatchBackfillEvents"
+	FrontendService_RemoveBackfillTickets_FullMethodName = "/openmatch.FrontendService/RemoveBackfillTickets"
+	FrontendService_CreateBackfill_FullMethodName        = "/openmatch.FrontendService/CreateBackfill"
+	FrontendService_DeleteBackfill_FullMethodName        = "/openmatch.FrontendService/DeleteBackfill"
+	FrontendService_GetBackfill_FullMethodName           = "/openmatch.FrontendService/GetBackfill"
+	FrontendService_UpdateBackfill_FullMethodName        = "/openmatch.FrontendService/UpdateBackfill"
 )
 
 // FrontendServiceClient is the client API for FrontendService service.
@@ -62,11 +67,40 @@ type FrontendServiceClient interface {
 	// WatchAssignments stream back Assignment of the specified TicketId if it is updated.
 	//   - If the Assignment is not updated, GetAssignment will retry using the configured backoff strategy.
 	WatchAssignments(ctx context.Context, in *WatchAssignmentsRequest, opts ...grpc.CallOption) (FrontendService_WatchAssignmentsClient, error)
+	// AcceptAssignment accepts the tentative Assignment of the specified TicketId.
+	// Once every Ticket of the assignment group accepted, the Assignment is finalized.
+	//   - If the Ticket has no tentative Assignment, a NotFound error is returned.
+	AcceptAssignment(ctx context.Context, in *AcceptAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeclineAssignment declines the tentative Assignment of the specified TicketId.
+	// The declining Ticket is deleted, and the other Tickets of the assignment group return to the active pool.
+	//   - If the Ticket has no tentative Assignment, a NotFound error is returned.
+	DeclineAssignment(ctx context.Context, in *DeclineAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// AcknowledgeBackfill is used to notify OpenMatch about GameServer connection info
 	// This triggers an assignment process.
 	// BETA FEATURE WARNING: This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	AcknowledgeBackfill(ctx context.Context, in *AcknowledgeBackfillRequest, opts ...grpc.CallOption) (*AcknowledgeBackfillResponse, error)
+	// WatchBackfill streams back the Backfill each time Tickets are matched to it or it is
+	// updated, assigning the matched Tickets like AcknowledgeBackfill.
+	//   - The Backfill is kept acknowledged while the stream is open, so the GameServer
+	//     doesn't need to call AcknowledgeBackfill.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	WatchBackfill(ctx context.Context, in *WatchBackfillRequest, opts ...grpc.CallOption) (FrontendService_WatchBackfillClient, error)
+	// WatchBackfillEvents streams back the life-cycle events of Backfills, such as the
+	// expiration and deletion of Backfills which were not acknowledged in time.
+	//   - Only the events recorded after the call are streamed.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	WatchBackfillEvents(ctx context.Context, in *WatchBackfillEventsRequest, opts ...grpc.CallOption) (FrontendService_WatchBackfillEventsClient, error)
+	// RemoveBackfillTickets reports Tickets assigned to a Backfill whose players never
+	// joined the GameServer, reopening their slots in the Backfill capacity.
+	//   - The Backfill generation is incremented, like on UpdateBackfill.
+	//   - If requeue is set, the Assignment of the Tickets is revoked and they can be
+	//     matched again.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	RemoveBackfillTickets(ctx context.Context, in *RemoveBackfillTicketsRequest, opts ...grpc.CallOption) (*RemoveBackfillTicketsResponse, error)
 	// CreateBackfill creates a new Backfill object.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
@@ -82,6 +116,7 @@ type FrontendServiceClient interface {
 	GetBackfill(ctx context.Context, in *GetBackfillRequest, opts ...grpc.CallOption) (*Backfill, error)
 	// UpdateBackfill updates search_fields and extensions for the backfill with the provided id.
 	// Any tickets waiting for this backfill will be returned to the active pool, no longer pending.
+	//   - If expected_generation is set, the backfill is only updated if it still has that generation.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	UpdateBackfill(ctx context.Context, in *UpdateBackfillRequest, opts ...grpc.CallOption) (*Backfill, error)
@@ -154,6 +189,24 @@ func (x *frontendServiceWatchAssignmentsClient) Recv() (*WatchAssignmentsRespons
 	return m, nil
 }
 
+func (c *frontendServiceClient) AcceptAssignment(ctx context.Context, in *AcceptAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FrontendService_AcceptAssignment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *frontendServiceClient) DeclineAssignment(ctx context.Context, in *DeclineAssignmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FrontendService_DeclineAssignment_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *frontendServiceClient) AcknowledgeBackfill(ctx context.Context, in *AcknowledgeBackfillRequest, opts ...grpc.CallOption) (*AcknowledgeBackfillResponse, error) {
 	out := new(AcknowledgeBackfillResponse)
 	err := c.cc.Invoke(ctx, FrontendService_AcknowledgeBackfill_FullMethodName, in, out, opts...)
@@ -163,6 +216,79 @@ func (c *frontendServiceClient) AcknowledgeBackfill(ctx context.Context, in *Ack
 	return out, nil
 }
 
+func (c *frontendServiceClient) WatchBackfill(ctx context.Context, in *WatchBackfillRequest, opts ...grpc.CallOption) (FrontendService_WatchBackfillClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FrontendService_ServiceDesc.Streams[1], FrontendService_WatchBackfill_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &frontendServiceWatchBackfillClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FrontendService_WatchBackfillClient interface {
+	Recv() (*WatchBackfillResponse, error)
+	grpc.ClientStream
+}
+
+type frontendServiceWatchBackfillClient struct {
+	grpc.ClientStream
+}
+
+func (x *frontendServiceWatchBackfillClient) Recv() (*WatchBackfillResponse, error) {
+	m := new(WatchBackfillResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *frontendServiceClient) WatchBackfillEvents(ctx context.Context, in *WatchBackfillEventsRequest, opts ...grpc.CallOption) (FrontendService_WatchBackfillEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &FrontendService_ServiceDesc.Streams[2], FrontendService_WatchBackfillEvents_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &frontendServiceWatchBackfillEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FrontendService_WatchBackfillEventsClient interface {
+	Recv() (*WatchBackfillEventsResponse, error)
+	grpc.ClientStream
+}
+
+type frontendServiceWatchBackfillEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *frontendServiceWatchBackfillEventsClient) Recv() (*WatchBackfillEventsResponse, error) {
+	m := new(WatchBackfillEventsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *frontendServiceClient) RemoveBackfillTickets(ctx context.Context, in *RemoveBackfillTicketsRequest, opts ...grpc.CallOption) (*RemoveBackfillTicketsResponse, error) {
+	out := new(RemoveBackfillTicketsResponse)
+	err := c.cc.Invoke(ctx, FrontendService_RemoveBackfillTickets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *frontendServiceClient) CreateBackfill(ctx context.Context, in *CreateBackfillRequest, opts ...grpc.CallOption) (*Backfill, error) {
 	out := new(Backfill)
 	err := c.cc.Invoke(ctx, FrontendService_CreateBackfill_FullMethodName, in, out, opts...)
@@ -216,11 +342,40 @@ type FrontendServiceServer interface {
 	// WatchAssignments stream back Assignment of the specified TicketId if it is updated.
 	//   - If the Assignment is not updated, GetAssignment will retry using the configured backoff strategy.
 	WatchAssignments(*WatchAssignmentsRequest, FrontendService_WatchAssignmentsServer) error
+	// AcceptAssignment accepts the tentative Assignment of the specified TicketId.
+	// Once every Ticket of the assignment group accepted, the Assignment is finalized.
+	//   - If the Ticket has no tentative Assignment, a NotFound error is returned.
+	AcceptAssignment(context.Context, *AcceptAssignmentRequest) (*emptypb.Empty, error)
+	// DeclineAssignment declines the tentative Assignment of the specified TicketId.
+	// The declining Ticket is deleted, and the other Tickets of the assignment group return to the active pool.
+	//   - If the Ticket has no tentative Assignment, a NotFound error is returned.
+	DeclineAssignment(context.Context, *DeclineAssignmentRequest) (*emptypb.Empty, error)
 	// AcknowledgeBackfill is used to notify OpenMatch about GameServer connection info
 	// This triggers an assignment process.
 	// BETA FEATURE WARNING: This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	AcknowledgeBackfill(context.Context, *AcknowledgeBackfillRequest) (*AcknowledgeBackfillResponse, error)
+	// WatchBackfill streams back the Backfill each time Tickets are matched to it or it is
+	// updated, assigning the matched Tickets like AcknowledgeBackfill.
+	//   - The Backfill is kept acknowledged while the stream is open, so the GameServer
+	//     doesn't need to call AcknowledgeBackfill.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	WatchBackfill(*WatchBackfillRequest, FrontendService_WatchBackfillServer) error
+	// WatchBackfillEvents streams back the life-cycle events of Backfills, such as the
+	// expiration and deletion of Backfills which were not acknowledged in time.
+	//   - Only the events recorded after the call are streamed.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	WatchBackfillEvents(*WatchBackfillEventsRequest, FrontendService_WatchBackfillEventsServer) error
+	// RemoveBackfillTickets reports Tickets assigned to a Backfill whose players never
+	// joined the GameServer, reopening their slots in the Backfill capacity.
+	//   - The Backfill generation is incremented, like on UpdateBackfill.
+	//   - If requeue is set, the Assignment of the Tickets is revoked and they can be
+	//     matched again.
+	// BETA FEATURE WARNING: This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	RemoveBackfillTickets(context.Context, *RemoveBackfillTicketsRequest) (*RemoveBackfillTicketsResponse, error)
 	// CreateBackfill creates a new Backfill object.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
@@ -236,6 +391,7 @@ type FrontendServiceServer interface {
 	GetBackfill(context.Context, *GetBackfillRequest) (*Backfill, error)
 	// UpdateBackfill updates search_fields and extensions for the backfill with the provided id.
 	// Any tickets waiting for this backfill will be returned to the active pool, no longer pending.
+	//   - If expected_generation is set, the backfill is only updated if it still has that generation.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	UpdateBackfill(context.Context, *UpdateBackfillRequest) (*Backfill, error)
@@ -257,9 +413,24 @@ func (UnimplementedFrontendServiceServer) GetTicket(context.Context, *GetTicketR
 func (UnimplementedFrontendServiceServer) WatchAssignments(*WatchAssignmentsRequest, FrontendService_WatchAssignmentsServer) error {
 	return status.Errorf(codes.Unimplemented, "method WatchAssignments not implemented")
 }
+func (UnimplementedFrontendServiceServer) AcceptAssignment(context.Context, *AcceptAssignmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptAssignment not implemented")
+}
+func (UnimplementedFrontendServiceServer) DeclineAssignment(context.Context, *DeclineAssignmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeclineAssignment not implemented")
+}
 func (UnimplementedFrontendServiceServer) AcknowledgeBackfill(context.Context, *AcknowledgeBackfillRequest) (*AcknowledgeBackfillResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeBackfill not implemented")
 }
+func (UnimplementedFrontendServiceServer) WatchBackfill(*WatchBackfillRequest, FrontendService_WatchBackfillServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBackfill not implemented")
+}
+func (UnimplementedFrontendServiceServer) WatchBackfillEvents(*WatchBackfillEventsRequest, FrontendService_WatchBackfillEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchBackfillEvents not implemented")
+}
+func (UnimplementedFrontendServiceServer) RemoveBackfillTickets(context.Context, *RemoveBackfillTicketsRequest) (*RemoveBackfillTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveBackfillTickets not implemented")
+}
 func (UnimplementedFrontendServiceServer) CreateBackfill(context.Context, *CreateBackfillRequest) (*Backfill, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateBackfill not implemented")
 }
@@ -359,6 +530,42 @@ func (x *frontendServiceWatchAssignmentsServer) Send(m *WatchAssignmentsResponse
 	return x.ServerStream.SendMsg(m)
 }
 
+func _FrontendService_AcceptAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptAssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).AcceptAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FrontendService_AcceptAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).AcceptAssignment(ctx, req.(*AcceptAssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FrontendService_DeclineAssignment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeclineAssignmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).DeclineAssignment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FrontendService_DeclineAssignment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).DeclineAssignment(ctx, req.(*DeclineAssignmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FrontendService_AcknowledgeBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AcknowledgeBackfillRequest)
 	if err := dec(in); err != nil {
@@ -377,6 +584,66 @@ func _FrontendService_AcknowledgeBackfill_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FrontendService_WatchBackfill_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBackfillRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FrontendServiceServer).WatchBackfill(m, &frontendServiceWatchBackfillServer{stream})
+}
+
+type FrontendService_WatchBackfillServer interface {
+	Send(*WatchBackfillResponse) error
+	grpc.ServerStream
+}
+
+type frontendServiceWatchBackfillServer struct {
+	grpc.ServerStream
+}
+
+func (x *frontendServiceWatchBackfillServer) Send(m *WatchBackfillResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FrontendService_WatchBackfillEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBackfillEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FrontendServiceServer).WatchBackfillEvents(m, &frontendServiceWatchBackfillEventsServer{stream})
+}
+
+type FrontendService_WatchBackfillEventsServer interface {
+	Send(*WatchBackfillEventsResponse) error
+	grpc.ServerStream
+}
+
+type frontendServiceWatchBackfillEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *frontendServiceWatchBackfillEventsServer) Send(m *WatchBackfillEventsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FrontendService_RemoveBackfillTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveBackfillTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FrontendServiceServer).RemoveBackfillTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FrontendService_RemoveBackfillTickets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FrontendServiceServer).RemoveBackfillTickets(ctx, req.(*RemoveBackfillTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _FrontendService_CreateBackfill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateBackfillRequest)
 	if err := dec(in); err != nil {
@@ -468,10 +735,22 @@ var FrontendService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTicket",
 			Handler:    _FrontendService_GetTicket_Handler,
 		},
+		{
+			MethodName: "AcceptAssignment",
+			Handler:    _FrontendService_AcceptAssignment_Handler,
+		},
+		{
+			MethodName: "DeclineAssignment",
+			Handler:    _FrontendService_DeclineAssignment_Handler,
+		},
 		{
 			MethodName: "AcknowledgeBackfill",
 			Handler:    _FrontendService_AcknowledgeBackfill_Handler,
 		},
+		{
+			MethodName: "RemoveBackfillTickets",
+			Handler:    _FrontendService_RemoveBackfillTickets_Handler,
+		},
 		{
 			MethodName: "CreateBackfill",
 			Handler:    _FrontendService_CreateBackfill_Handler,
@@ -495,6 +774,16 @@ var FrontendService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FrontendService_WatchAssignments_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchBackfill",
+			Handler:       _FrontendService_WatchBackfill_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchBackfillEvents",
+			Handler:       _FrontendService_WatchBackfillEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/frontend.proto",
 }
@@ -33,9 +33,11 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	QueryService_QueryTickets_FullMethodName   = "/openmatch.QueryService/QueryTickets"
-	QueryService_QueryTicketIds_FullMethodName = "/openmatch.QueryService/QueryTicketIds"
-	QueryService_QueryBackfills_FullMethodName = "/openmatch.QueryService/QueryBackfills"
+	QueryService_QueryTickets_FullMethodName             = "/openmatch.QueryService/QueryTickets"
+	QueryService_QueryTicketIds_FullMethodName           = "/openmatch.QueryService/QueryTicketIds"
+	QueryService_ReserveTickets_FullMethodName           = "/openmatch.QueryService/ReserveTickets"
+	QueryService_QueryBackfills_FullMethodName           = "/openmatch.QueryService/QueryBackfills"
+	QueryService_QueryCompatibleBackfills_FullMethodName = "/openmatch.QueryService/QueryCompatibleBackfills"
 )
 
 // QueryServiceClient is the client API for QueryService service.
@@ -54,10 +56,22 @@ type QueryServiceClient interface {
 	// QueryTicketIds pages the TicketIDs by `queryPageSize` and stream back responses.
 	//   - queryPageSize is default to 1000 if not set, and has a minimum of 10 and maximum of 10000.
 	QueryTicketIds(ctx context.Context, in *QueryTicketIdsRequest, opts ...grpc.CallOption) (QueryService_QueryTicketIdsClient, error)
+	// ReserveTickets soft-reserves Tickets for a MatchProfile across synchronizer
+	// cycles, so that a match needing many Tickets can form even when smaller
+	// matches would otherwise take them.  Reserved Tickets are hidden from
+	// queries for other profiles until the reservation expires.  Reserving a
+	// Ticket the profile already holds extends the reservation.
+	ReserveTickets(ctx context.Context, in *ReserveTicketsRequest, opts ...grpc.CallOption) (*ReserveTicketsResponse, error)
 	// QueryBackfills gets a list of Backfills.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	QueryBackfills(ctx context.Context, in *QueryBackfillsRequest, opts ...grpc.CallOption) (QueryService_QueryBackfillsClient, error)
+	// QueryCompatibleBackfills gets, for each input Ticket, the Backfills it is
+	// compatible with, ranked by how well they fit the Ticket.
+	// QueryCompatibleBackfills pages the Tickets by `queryPageSize` and stream back responses.
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	QueryCompatibleBackfills(ctx context.Context, in *QueryCompatibleBackfillsRequest, opts ...grpc.CallOption) (QueryService_QueryCompatibleBackfillsClient, error)
 }
 
 type queryServiceClient struct {
@@ -132,6 +146,15 @@ func (x *queryServiceQueryTicketIdsClient) Recv() (*QueryTicketIdsResponse, erro
 	return m, nil
 }
 
+func (c *queryServiceClient) ReserveTickets(ctx context.Context, in *ReserveTicketsRequest, opts ...grpc.CallOption) (*ReserveTicketsResponse, error) {
+	out := new(ReserveTicketsResponse)
+	err := c.cc.Invoke(ctx, QueryService_ReserveTickets_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *queryServiceClient) QueryBackfills(ctx context.Context, in *QueryBackfillsRequest, opts ...grpc.CallOption) (QueryService_QueryBackfillsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[2], QueryService_QueryBackfills_FullMethodName, opts...)
 	if err != nil {
@@ -164,6 +187,38 @@ func (x *queryServiceQueryBackfillsClient) Recv() (*QueryBackfillsResponse, erro
 	return m, nil
 }
 
+func (c *queryServiceClient) QueryCompatibleBackfills(ctx context.Context, in *QueryCompatibleBackfillsRequest, opts ...grpc.CallOption) (QueryService_QueryCompatibleBackfillsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &QueryService_ServiceDesc.Streams[3], QueryService_QueryCompatibleBackfills_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &queryServiceQueryCompatibleBackfillsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type QueryService_QueryCompatibleBackfillsClient interface {
+	Recv() (*QueryCompatibleBackfillsResponse, error)
+	grpc.ClientStream
+}
+
+type queryServiceQueryCompatibleBackfillsClient struct {
+	grpc.ClientStream
+}
+
+func (x *queryServiceQueryCompatibleBackfillsClient) Recv() (*QueryCompatibleBackfillsResponse, error) {
+	m := new(QueryCompatibleBackfillsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 // All implementations should embed UnimplementedQueryServiceServer
 // for forward compatibility
@@ -180,10 +235,22 @@ type QueryServiceServer interface {
 	// QueryTicketIds pages the TicketIDs by `queryPageSize` and stream back responses.
 	//   - queryPageSize is default to 1000 if not set, and has a minimum of 10 and maximum of 10000.
 	QueryTicketIds(*QueryTicketIdsRequest, QueryService_QueryTicketIdsServer) error
+	// ReserveTickets soft-reserves Tickets for a MatchProfile across synchronizer
+	// cycles, so that a match needing many Tickets can form even when smaller
+	// matches would otherwise take them.  Reserved Tickets are hidden from
+	// queries for other profiles until the reservation expires.  Reserving a
+	// Ticket the profile already holds extends the reservation.
+	ReserveTickets(context.Context, *ReserveTicketsRequest) (*ReserveTicketsResponse, error)
 	// QueryBackfills gets a list of Backfills.
 	// BETA FEATURE WARNING:  This call and the associated Request and Response
 	// messages are not finalized and still subject to possible change or removal.
 	QueryBackfills(*QueryBackfillsRequest, QueryService_QueryBackfillsServer) error
+	// QueryCompatibleBackfills gets, for each input Ticket, the Backfills it is
+	// compatible with, ranked by how well they fit the Ticket.
+	// QueryCompatibleBackfills pages the Tickets by `queryPageSize` and stream back responses.
+	// BETA FEATURE WARNING:  This call and the associated Request and Response
+	// messages are not finalized and still subject to possible change or removal.
+	QueryCompatibleBackfills(*QueryCompatibleBackfillsRequest, QueryService_QueryCompatibleBackfillsServer) error
 }
 
 // UnimplementedQueryServiceServer should be embedded to have forward compatible implementations.
@@ -196,9 +263,15 @@ func (UnimplementedQueryServiceServer) QueryTickets(*QueryTicketsRequest, QueryS
 func (UnimplementedQueryServiceServer) QueryTicketIds(*QueryTicketIdsRequest, QueryService_QueryTicketIdsServer) error {
 	return status.Errorf(codes.Unimplemented, "method QueryTicketIds not implemented")
 }
+func (UnimplementedQueryServiceServer) ReserveTickets(context.Context, *ReserveTicketsRequest) (*ReserveTicketsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveTickets not implemented")
+}
 func (UnimplementedQueryServiceServer) QueryBackfills(*QueryBackfillsRequest, QueryService_QueryBackfillsServer) error {
 	return status.Errorf(codes.Unimplemented, "method QueryBackfills not implemented")
 }
+func (UnimplementedQueryServiceServer) QueryCompatibleBackfills(*QueryCompatibleBackfillsRequest, QueryService_QueryCompatibleBackfillsServer) error {
+	return status.Errorf(codes.Unimplemented, "method QueryCompatibleBackfills not implemented")
+}
 
 // UnsafeQueryServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to QueryServiceServer will
@@ -253,6 +326,24 @@ func (x *queryServiceQueryTicketIdsServer) Send(m *QueryTicketIdsResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _QueryService_ReserveTickets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveTicketsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).ReserveTickets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryService_ReserveTickets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).ReserveTickets(ctx, req.(*ReserveTicketsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _QueryService_QueryBackfills_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(QueryBackfillsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -274,13 +365,39 @@ func (x *queryServiceQueryBackfillsServer) Send(m *QueryBackfillsResponse) error
 	return x.ServerStream.SendMsg(m)
 }
 
+func _QueryService_QueryCompatibleBackfills_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryCompatibleBackfillsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(QueryServiceServer).QueryCompatibleBackfills(m, &queryServiceQueryCompatibleBackfillsServer{stream})
+}
+
+type QueryService_QueryCompatibleBackfillsServer interface {
+	Send(*QueryCompatibleBackfillsResponse) error
+	grpc.ServerStream
+}
+
+type queryServiceQueryCompatibleBackfillsServer struct {
+	grpc.ServerStream
+}
+
+func (x *queryServiceQueryCompatibleBackfillsServer) Send(m *QueryCompatibleBackfillsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // QueryService_ServiceDesc is the grpc.ServiceDesc for QueryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
 var QueryService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "openmatch.QueryService",
 	HandlerType: (*QueryServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReserveTickets",
+			Handler:    _QueryService_ReserveTickets_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "QueryTickets",
@@ -297,6 +414,11 @@ var QueryService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _QueryService_QueryBackfills_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "QueryCompatibleBackfills",
+			Handler:       _QueryService_QueryCompatibleBackfills_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/query.proto",
 }
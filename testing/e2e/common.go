@@ -158,6 +158,14 @@ func (om *om) MMFConfigHTTP() *pb.FunctionConfig {
 	}
 }
 
+func (om *om) MMFConfigWebSocket() *pb.FunctionConfig {
+	return &pb.FunctionConfig{
+		Host: om.cfg.GetString("api." + apptest.ServiceName + ".hostname"),
+		Port: int32(om.cfg.GetInt("api." + apptest.ServiceName + ".httpport")),
+		Type: pb.FunctionConfig_WEBSOCKET,
+	}
+}
+
 // Testing constants which must match the configuration.  Not parsed in test so
 // that parsing bugs can't hide logic bugs.
 const registrationInterval = time.Millisecond * 200
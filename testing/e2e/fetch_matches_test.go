@@ -852,3 +852,81 @@ func TestHTTPMMF(t *testing.T) {
 	require.Equal(t, err.Error(), io.EOF.Error())
 	require.Nil(t, resp)
 }
+
+// TestWebSocketMMF covers calling the MMF over a websocket instead of gRPC.
+func TestWebSocketMMF(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	t1, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+	t2, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+
+	m1 := &pb.Match{
+		MatchId: "1",
+		Tickets: []*pb.Ticket{t1},
+	}
+	m2 := &pb.Match{
+		MatchId: "2",
+		Tickets: []*pb.Ticket{t2},
+	}
+
+	om.SetMMF(func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		require.Equal(t, "websocket", profile.Name)
+		out <- m1
+		out <- m2
+		return nil
+	})
+
+	om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		for p := range in {
+			out <- p.MatchId
+		}
+		return nil
+	})
+
+	stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+		Config:  om.MMFConfigWebSocket(),
+		Profile: &pb.MatchProfile{Name: "websocket"},
+	})
+	require.Nil(t, err)
+
+	ids := []string{}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.Nil(t, err)
+		ids = append(ids, resp.Match.MatchId)
+	}
+	require.ElementsMatch(t, []string{"1", "2"}, ids)
+}
+
+// TestWebSocketMMFError covers a MMF called over a websocket returning an
+// error.
+func TestWebSocketMMFError(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	om.SetMMF(func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		return status.Error(codes.OutOfRange, "Error in MMF")
+	})
+
+	om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		_, ok := <-in
+		require.False(t, ok)
+		return nil
+	})
+
+	stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+		Config:  om.MMFConfigWebSocket(),
+		Profile: &pb.MatchProfile{},
+	})
+	require.Nil(t, err)
+
+	resp, err := stream.Recv()
+	require.Contains(t, err.Error(), "Error in MMF")
+	require.Nil(t, resp)
+}
@@ -0,0 +1,181 @@
+//go:build !e2ecluster
+// +build !e2ecluster
+
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/anypb"
+	"open-match.dev/open-match/internal/app/rulesmmf"
+	"open-match.dev/open-match/pkg/matchfunction"
+	"open-match.dev/open-match/pkg/pb"
+)
+
+// In process match functions are compiled into the backend, which only shares
+// this binary when running in memory.
+func init() {
+	matchfunction.Register("e2e-all-tickets", matchfunction.InProcessFunctionFunc(func(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		tickets, err := matchfunction.QueryPool(ctx, query, profile.GetPools()[0])
+		if err != nil {
+			return err
+		}
+		out <- &pb.Match{
+			MatchId: "1",
+			Tickets: tickets,
+		}
+		return nil
+	}))
+	matchfunction.Register("e2e-panic", matchfunction.InProcessFunctionFunc(func(ctx context.Context, query pb.QueryServiceClient, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		panic("my custom panic")
+	}))
+	matchfunction.Register("e2e-rules", rulesmmf.Function)
+}
+
+func mmfConfigInProcess(name string) *pb.FunctionConfig {
+	return &pb.FunctionConfig{
+		Name: name,
+		Type: pb.FunctionConfig_IN_PROCESS,
+	}
+}
+
+// TestInProcessMMF covers calling a match function registered in the backend,
+// which queries tickets with the backend's query client.
+func TestInProcessMMF(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	t1, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+	t2, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+	require.Nil(t, err)
+
+	om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		for p := range in {
+			out <- p.MatchId
+		}
+		return nil
+	})
+
+	stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+		Config:  mmfConfigInProcess("e2e-all-tickets"),
+		Profile: &pb.MatchProfile{Pools: []*pb.Pool{{Name: "all"}}},
+	})
+	require.Nil(t, err)
+
+	resp, err := stream.Recv()
+	require.Nil(t, err)
+	require.Equal(t, "1", resp.Match.MatchId)
+	ids := []string{}
+	for _, ticket := range resp.Match.Tickets {
+		ids = append(ids, ticket.Id)
+	}
+	require.ElementsMatch(t, []string{t1.Id, t2.Id}, ids)
+
+	resp, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Nil(t, resp)
+}
+
+// TestInProcessMMFErrors covers calling match functions which aren't
+// registered, or panic.
+func TestInProcessMMFErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  string
+	}{
+		{name: "e2e-missing", err: `no match function registered in the backend with name "e2e-missing"`},
+		{name: "e2e-panic", err: "my custom panic"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			om := newOM(t)
+
+			om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+				_, ok := <-in
+				require.False(t, ok)
+				return nil
+			})
+
+			stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+				Config:  mmfConfigInProcess(test.name),
+				Profile: &pb.MatchProfile{},
+			})
+			require.Nil(t, err)
+
+			resp, err := stream.Recv()
+			require.Contains(t, err.Error(), test.err)
+			require.Nil(t, resp)
+		})
+	}
+}
+
+// TestInProcessRulesMMF covers the rule based match function, configured by
+// the profile's rules extension.
+func TestInProcessRulesMMF(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	for i := 0; i < 5; i++ {
+		_, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{}})
+		require.Nil(t, err)
+	}
+
+	om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		for p := range in {
+			out <- p.MatchId
+		}
+		return nil
+	})
+
+	rules, err := anypb.New(&pb.MatchRules{
+		Teams: []*pb.MatchRules_Team{
+			{Name: "red", PoolSlots: map[string]int32{"all": 2}},
+			{Name: "blue", PoolSlots: map[string]int32{"all": 2}},
+		},
+	})
+	require.Nil(t, err)
+
+	stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+		Config: mmfConfigInProcess("e2e-rules"),
+		Profile: &pb.MatchProfile{
+			Name:       "2v2",
+			Pools:      []*pb.Pool{{Name: "all"}},
+			Extensions: map[string]*anypb.Any{rulesmmf.RulesExtension: rules},
+		},
+	})
+	require.Nil(t, err)
+
+	resp, err := stream.Recv()
+	require.Nil(t, err)
+	require.Len(t, resp.Match.Tickets, 4)
+	teams := &pb.MatchTeams{}
+	require.Nil(t, resp.Match.Extensions[rulesmmf.TeamsExtension].UnmarshalTo(teams))
+	require.Len(t, teams.Teams, 2)
+	require.Equal(t, "red", teams.Teams[0].Name)
+	require.Len(t, teams.Teams[0].TicketIds, 2)
+
+	resp, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Nil(t, resp)
+}
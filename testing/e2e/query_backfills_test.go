@@ -217,3 +217,65 @@ func TestBackfillQueryAfterGSUpdate(t *testing.T) {
 		require.Nil(t, resp)
 	}
 }
+
+func TestQueryCompatibleBackfills(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	createBackfill := func(mode string, skill float64) string {
+		resp, err := om.Frontend().CreateBackfill(ctx, &pb.CreateBackfillRequest{Backfill: &pb.Backfill{
+			SearchFields: &pb.SearchFields{
+				StringArgs: map[string]string{"mode": mode},
+				DoubleArgs: map[string]float64{"skill": skill},
+			},
+		}})
+		require.NoError(t, err)
+		return resp.Id
+	}
+	far := createBackfill("ctf", 15)
+	near := createBackfill("ctf", 11)
+	createBackfill("ctf", 30)
+	createBackfill("dm", 10)
+
+	ticket, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+		SearchFields: &pb.SearchFields{
+			StringArgs: map[string]string{"mode": "ctf"},
+			DoubleArgs: map[string]float64{"skill": 10},
+		},
+	}})
+	require.NoError(t, err)
+	_, err = om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+		SearchFields: &pb.SearchFields{
+			StringArgs: map[string]string{"mode": "koth"},
+			DoubleArgs: map[string]float64{"skill": 10},
+		},
+	}})
+	require.NoError(t, err)
+
+	req := &pb.QueryCompatibleBackfillsRequest{
+		TicketPool: &pb.Pool{},
+		Compatibility: &pb.BackfillCompatibility{
+			StringArgs: []string{"mode"},
+			DoubleArgs: []*pb.DoubleArgDistance{{DoubleArg: "skill", MaxDistance: 10}},
+		},
+	}
+	stream, err := om.Query().QueryCompatibleBackfills(ctx, req)
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, resp.TicketBackfills, 1)
+	require.Equal(t, ticket.Id, resp.TicketBackfills[0].TicketId)
+	require.Len(t, resp.TicketBackfills[0].Backfills, 2)
+	require.Equal(t, near, resp.TicketBackfills[0].Backfills[0].Id)
+	require.Equal(t, far, resp.TicketBackfills[0].Backfills[1].Id)
+
+	resp, err = stream.Recv()
+	require.Equal(t, io.EOF, err)
+	require.Nil(t, resp)
+
+	stream, err = om.Query().QueryCompatibleBackfills(ctx, &pb.QueryCompatibleBackfillsRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.Equal(t, codes.InvalidArgument, status.Convert(err).Code())
+}
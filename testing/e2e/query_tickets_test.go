@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
@@ -217,3 +218,75 @@ func returnedByQueryID(t *testing.T, tc testcases.TestCase) (found bool) {
 
 	return len(ids) == 1
 }
+
+// TestReserveTickets covers tickets reserved for a profile being hidden from
+// queries for other profiles, until the reservation expires.
+func TestReserveTickets(t *testing.T) {
+	ctx := context.Background()
+	om := newOM(t)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		resp, err := om.Frontend().CreateTicket(ctx, &pb.CreateTicketRequest{Ticket: &pb.Ticket{
+			SearchFields: &pb.SearchFields{Tags: []string{"reserve"}},
+		}})
+		require.Nil(t, err)
+		ids = append(ids, resp.Id)
+	}
+	pool := &pb.Pool{TagPresentFilters: []*pb.TagPresentFilter{{Tag: "reserve"}}}
+
+	query := func(profile string) []string {
+		stream, err := om.Query().QueryTicketIds(ctx, &pb.QueryTicketIdsRequest{Pool: pool, ProfileName: profile})
+		require.Nil(t, err)
+		found := []string{}
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return found
+			}
+			require.Nil(t, err)
+			found = append(found, resp.Ids...)
+		}
+	}
+
+	_, err := om.Query().ReserveTickets(ctx, &pb.ReserveTicketsRequest{TicketIds: ids[:1], Cycles: 1})
+	require.Equal(t, codes.InvalidArgument, status.Convert(err).Code())
+	_, err = om.Query().ReserveTickets(ctx, &pb.ReserveTicketsRequest{ProfileName: "br", TicketIds: ids[:1]})
+	require.Equal(t, codes.InvalidArgument, status.Convert(err).Code())
+
+	resp, err := om.Query().ReserveTickets(ctx, &pb.ReserveTicketsRequest{ProfileName: "br", TicketIds: ids[:2], Cycles: 1})
+	require.Nil(t, err)
+	require.ElementsMatch(t, ids[:2], resp.TicketIds)
+
+	// Another profile can't take over the reservation.
+	resp, err = om.Query().ReserveTickets(ctx, &pb.ReserveTicketsRequest{ProfileName: "1v1", TicketIds: ids, Cycles: 1})
+	require.Nil(t, err)
+	require.Equal(t, ids[2:], resp.TicketIds)
+
+	require.ElementsMatch(t, ids[:2], query("br"))
+	require.ElementsMatch(t, ids[2:], query("1v1"))
+	require.Empty(t, query(""))
+
+	// Reservations expire as synchronizer cycles pass.
+	om.SetMMF(func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error {
+		return nil
+	})
+	om.SetEvaluator(func(ctx context.Context, in <-chan *pb.Match, out chan<- string) error {
+		for range in {
+		}
+		return nil
+	})
+	require.Eventually(t, func() bool {
+		stream, err := om.Backend().FetchMatches(ctx, &pb.FetchMatchesRequest{
+			Config:  om.MMFConfigGRPC(),
+			Profile: &pb.MatchProfile{Name: "cycle"},
+		})
+		if err != nil {
+			return false
+		}
+		if _, err = stream.Recv(); err != io.EOF {
+			return false
+		}
+		return len(query("")) == len(ids)
+	}, 10*time.Second, 100*time.Millisecond)
+}
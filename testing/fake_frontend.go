@@ -55,12 +55,37 @@ func (s *FakeFrontend) WatchAssignments(req *pb.WatchAssignmentsRequest, stream
 	return status.Error(codes.Unimplemented, "not implemented")
 }
 
+// AcceptAssignment accepts the tentative Assignment of the provided Ticket id.
+func (s *FakeFrontend) AcceptAssignment(ctx context.Context, req *pb.AcceptAssignmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
+// DeclineAssignment declines the tentative Assignment of the provided Ticket id.
+func (s *FakeFrontend) DeclineAssignment(ctx context.Context, req *pb.DeclineAssignmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
 // AcknowledgeBackfill is used to notify OpenMatch about GameServer connection info.
 // This triggers an assignment process.
 func (s *FakeFrontend) AcknowledgeBackfill(ctx context.Context, req *pb.AcknowledgeBackfillRequest) (*pb.AcknowledgeBackfillResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
 }
 
+// WatchBackfill streams back the Backfill each time Tickets are matched to it or it is updated.
+func (s *FakeFrontend) WatchBackfill(req *pb.WatchBackfillRequest, stream pb.FrontendService_WatchBackfillServer) error {
+	return status.Error(codes.Unimplemented, "not implemented")
+}
+
+// WatchBackfillEvents streams back the life-cycle events of Backfills.
+func (s *FakeFrontend) WatchBackfillEvents(req *pb.WatchBackfillEventsRequest, stream pb.FrontendService_WatchBackfillEventsServer) error {
+	return status.Error(codes.Unimplemented, "not implemented")
+}
+
+// RemoveBackfillTickets reopens the slots of Tickets whose players never joined the GameServer.
+func (s *FakeFrontend) RemoveBackfillTickets(ctx context.Context, req *pb.RemoveBackfillTicketsRequest) (*pb.RemoveBackfillTicketsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "not implemented")
+}
+
 // CreateBackfill creates a new Backfill object.
 func (s *FakeFrontend) CreateBackfill(ctx context.Context, req *pb.CreateBackfillRequest) (*pb.Backfill, error) {
 	return nil, status.Error(codes.Unimplemented, "not implemented")
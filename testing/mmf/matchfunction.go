@@ -18,19 +18,20 @@ package mmf
 import (
 	"google.golang.org/grpc"
 	"open-match.dev/open-match/internal/appmain"
+	"open-match.dev/open-match/internal/rpc"
+	"open-match.dev/open-match/pkg/matchfunction"
 	"open-match.dev/open-match/pkg/pb"
 )
 
 // BindServiceFor creates the match function service and binds it to the serving harness.
 func BindServiceFor(mmf MatchFunction) appmain.Bind {
 	return func(p *appmain.Params, b *appmain.Bindings) error {
-		service := &matchFunctionService{
-			mmf: mmf,
-		}
+		service := matchfunction.NewServer(mmf)
 
 		b.AddHandleFunc(func(s *grpc.Server) {
 			pb.RegisterMatchFunctionServer(s, service)
 		}, pb.RegisterMatchFunctionHandlerFromEndpoint)
+		b.AddWebSocketHandler("/v1/matchfunction:run", rpc.NewWebSocketHandler(service, pb.MatchFunction_ServiceDesc.Streams[0].Handler))
 
 		return nil
 	}
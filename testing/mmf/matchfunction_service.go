@@ -16,42 +16,8 @@
 package mmf
 
 import (
-	"context"
-
-	"golang.org/x/sync/errgroup"
-	"open-match.dev/open-match/pkg/pb"
+	"open-match.dev/open-match/pkg/matchfunction"
 )
 
 // MatchFunction is the function signature for the Match Making Function (MMF) to be implemented by the user.
-type MatchFunction func(ctx context.Context, profile *pb.MatchProfile, out chan<- *pb.Match) error
-
-type matchFunctionService struct {
-	mmf MatchFunction
-}
-
-func (s *matchFunctionService) Run(req *pb.RunRequest, stream pb.MatchFunction_RunServer) error {
-	g, ctx := errgroup.WithContext(stream.Context())
-
-	out := make(chan *pb.Match)
-
-	g.Go(func() error {
-		defer close(out)
-		return s.mmf(ctx, req.Profile, out)
-	})
-	g.Go(func() error {
-		defer func() {
-			for range out {
-			}
-		}()
-
-		for m := range out {
-			err := stream.Send(&pb.RunResponse{Proposal: m})
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	})
-
-	return g.Wait()
-}
+type MatchFunction = matchfunction.MatchFunction